@@ -10,9 +10,11 @@ import (
 	"fmt"
 	"math/big"
 	"net/http"
+	"strconv"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/v2/api/utils"
@@ -22,24 +24,33 @@ import (
 	"github.com/vechain/thor/v2/runtime"
 	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
 	"github.com/vechain/thor/v2/tx"
 	"github.com/vechain/thor/v2/xenv"
 )
 
+const (
+	defaultStorageRangeLimit = 100
+	maxStorageRangeLimit     = 1000
+)
+
 type Accounts struct {
 	repo         *chain.Repository
 	stater       *state.Stater
 	callGasLimit uint64
 	forkConfig   thor.ForkConfig
 	bft          bft.Finalizer
+	batchLimit   uint64
 }
 
+// New creates the accounts API. batchLimit caps the number of accounts of a batch retrieval, unlimited if 0.
 func New(
 	repo *chain.Repository,
 	stater *state.Stater,
 	callGasLimit uint64,
 	forkConfig thor.ForkConfig,
 	bft bft.Finalizer,
+	batchLimit uint64,
 ) *Accounts {
 	return &Accounts{
 		repo,
@@ -47,9 +58,22 @@ func New(
 		callGasLimit,
 		forkConfig,
 		bft,
+		batchLimit,
 	}
 }
 
+// revisionError maps the error of resolving the revision for account queries. A block ID unknown
+// to the repository is not found, while other unresolvable revisions are bad requests.
+func (a *Accounts) revisionError(revision *utils.Revision, err error) error {
+	if a.repo.IsNotFound(err) {
+		if revision.IsBlockID() {
+			return utils.HTTPError(errors.WithMessage(err, "revision"), http.StatusNotFound)
+		}
+		return utils.BadRequest(errors.WithMessage(err, "revision"))
+	}
+	return err
+}
+
 func (a *Accounts) getCode(addr thor.Address, state *state.State) ([]byte, error) {
 	code, err := state.GetCode(addr)
 	if err != nil {
@@ -71,10 +95,7 @@ func (a *Accounts) handleGetCode(w http.ResponseWriter, req *http.Request) error
 
 	_, st, err := utils.GetSummaryAndState(revision, a.repo, a.bft, a.stater)
 	if err != nil {
-		if a.repo.IsNotFound(err) {
-			return utils.BadRequest(errors.WithMessage(err, "revision"))
-		}
-		return err
+		return a.revisionError(revision, err)
 	}
 	code, err := a.getCode(addr, st)
 	if err != nil {
@@ -125,10 +146,7 @@ func (a *Accounts) handleGetAccount(w http.ResponseWriter, req *http.Request) er
 
 	summary, st, err := utils.GetSummaryAndState(revision, a.repo, a.bft, a.stater)
 	if err != nil {
-		if a.repo.IsNotFound(err) {
-			return utils.BadRequest(errors.WithMessage(err, "revision"))
-		}
-		return err
+		return a.revisionError(revision, err)
 	}
 
 	acc, err := a.getAccount(addr, summary.Header, st)
@@ -138,6 +156,37 @@ func (a *Accounts) handleGetAccount(w http.ResponseWriter, req *http.Request) er
 	return utils.WriteJSON(w, acc)
 }
 
+func (a *Accounts) handleGetBatchAccounts(w http.ResponseWriter, req *http.Request) error {
+	var batch BatchAccountsData
+	if err := utils.ParseJSON(req.Body, &batch); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if a.batchLimit > 0 && uint64(len(batch.Addresses)) > a.batchLimit {
+		return utils.HTTPError(
+			fmt.Errorf("addresses: exceeds the batch limit %v", a.batchLimit),
+			http.StatusRequestEntityTooLarge)
+	}
+	revision, err := utils.ParseRevision(req.URL.Query().Get("revision"), false)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "revision"))
+	}
+
+	summary, st, err := utils.GetSummaryAndState(revision, a.repo, a.bft, a.stater)
+	if err != nil {
+		return a.revisionError(revision, err)
+	}
+
+	accs := make([]*Account, 0, len(batch.Addresses))
+	for _, addr := range batch.Addresses {
+		acc, err := a.getAccount(addr, summary.Header, st)
+		if err != nil {
+			return err
+		}
+		accs = append(accs, acc)
+	}
+	return utils.WriteJSON(w, accs)
+}
+
 func (a *Accounts) handleGetStorage(w http.ResponseWriter, req *http.Request) error {
 	addr, err := thor.ParseAddress(mux.Vars(req)["address"])
 	if err != nil {
@@ -154,10 +203,7 @@ func (a *Accounts) handleGetStorage(w http.ResponseWriter, req *http.Request) er
 
 	_, st, err := utils.GetSummaryAndState(revision, a.repo, a.bft, a.stater)
 	if err != nil {
-		if a.repo.IsNotFound(err) {
-			return utils.BadRequest(errors.WithMessage(err, "revision"))
-		}
-		return err
+		return a.revisionError(revision, err)
 	}
 
 	storage, err := a.getStorage(addr, key, st)
@@ -167,6 +213,73 @@ func (a *Accounts) handleGetStorage(w http.ResponseWriter, req *http.Request) er
 	return utils.WriteJSON(w, map[string]string{"value": storage.String()})
 }
 
+// storageRange returns at most limit storage entries of the account, starting from the hashed key start.
+func (a *Accounts) storageRange(addr thor.Address, start thor.Bytes32, limit int, state *state.State) (*StorageRange, error) {
+	storageTrie, err := state.BuildStorageTrie(addr)
+	if err != nil {
+		return nil, err
+	}
+	it := trie.NewIterator(storageTrie.NodeIterator(start.Bytes(), 0))
+	result := &StorageRange{Storage: make([]*StorageEntry, 0)}
+	for len(result.Storage) < limit && it.Next() {
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return nil, err
+		}
+		result.Storage = append(result.Storage, &StorageEntry{
+			Key:   thor.BytesToBytes32(it.Meta),
+			Value: thor.BytesToBytes32(content),
+		})
+	}
+	if it.Next() {
+		next := thor.BytesToBytes32(it.Key)
+		result.Next = &next
+	}
+	if it.Err != nil {
+		return nil, it.Err
+	}
+	return result, nil
+}
+
+func (a *Accounts) handleGetStorageRange(w http.ResponseWriter, req *http.Request) error {
+	addr, err := thor.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	var start thor.Bytes32
+	if s := req.URL.Query().Get("start"); s != "" {
+		if start, err = thor.ParseBytes32(s); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "start"))
+		}
+	}
+	limit := defaultStorageRangeLimit
+	if s := req.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "limit"))
+		}
+		if n == 0 || n > maxStorageRangeLimit {
+			return utils.BadRequest(fmt.Errorf("limit: should be in [1, %v]", maxStorageRangeLimit))
+		}
+		limit = int(n)
+	}
+	revision, err := utils.ParseRevision(req.URL.Query().Get("revision"), false)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "revision"))
+	}
+
+	_, st, err := utils.GetSummaryAndState(revision, a.repo, a.bft, a.stater)
+	if err != nil {
+		return a.revisionError(revision, err)
+	}
+
+	result, err := a.storageRange(addr, start, limit, st)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, result)
+}
+
 func (a *Accounts) handleCallContract(w http.ResponseWriter, req *http.Request) error {
 	callData := &CallData{}
 	if err := utils.ParseJSON(req.Body, &callData); err != nil {
@@ -360,6 +473,11 @@ func (a *Accounts) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodPost).
 		Name("accounts_call_batch_code").
 		HandlerFunc(utils.WrapHandlerFunc(a.handleCallBatchCode))
+	// mounted before the contract call route, which also matches it
+	sub.Path("/batch").
+		Methods(http.MethodPost).
+		Name("accounts_get_batch").
+		HandlerFunc(utils.WrapHandlerFunc(a.handleGetBatchAccounts))
 	sub.Path("/{address}").
 		Methods(http.MethodGet).
 		Name("accounts_get_account").
@@ -368,6 +486,11 @@ func (a *Accounts) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodGet).
 		Name("accounts_get_code").
 		HandlerFunc(utils.WrapHandlerFunc(a.handleGetCode))
+	// mounted before the single slot route, which also matches it
+	sub.Path("/{address}/storage/range").
+		Methods(http.MethodGet).
+		Name("accounts_get_storage_range").
+		HandlerFunc(utils.WrapHandlerFunc(a.handleGetStorageRange))
 	sub.Path("/{address}/storage/{key}").
 		Methods("GET").
 		Name("accounts_get_storage").
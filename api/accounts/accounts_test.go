@@ -93,6 +93,8 @@ var gasLimit uint64
 var genesisBlock *block.Block
 
 var contractAddr thor.Address
+var sideAddr = thor.BytesToAddress([]byte("side"))
+var sideBlockID thor.Bytes32
 
 var bytecode = common.Hex2Bytes("608060405234801561001057600080fd5b50610125806100206000396000f3006080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806324b8ba5f14604e578063bb4e3f4d14607b575b600080fd5b348015605957600080fd5b506079600480360381019080803560ff16906020019092919050505060cf565b005b348015608657600080fd5b5060b3600480360381019080803560ff169060200190929190803560ff16906020019092919050505060ec565b604051808260ff1660ff16815260200191505060405180910390f35b806000806101000a81548160ff021916908360ff16021790555050565b60008183019050929150505600a165627a7a723058201584add23e31d36c569b468097fe01033525686b59bbb263fb3ab82e9553dae50029")
 
@@ -114,10 +116,14 @@ func TestAccount(t *testing.T) {
 		"getAccountWithNonExisitingRevision":   getAccountWithNonExisitingRevision,
 		"getAccountWithGenesisRevision":        getAccountWithGenesisRevision,
 		"getAccountWithFinalizedRevision":      getAccountWithFinalizedRevision,
+		"getAccountWithSideChainRevision":      getAccountWithSideChainRevision,
+		"getAccountWithMalformedRevision":      getAccountWithMalformedRevision,
 		"getCode":                              getCode,
 		"getCodeWithNonExisitingRevision":      getCodeWithNonExisitingRevision,
 		"getStorage":                           getStorage,
 		"getStorageWithNonExisitingRevision":   getStorageWithNonExisitingRevision,
+		"getStorageRange":                      getStorageRange,
+		"getBatchAccounts":                     getBatchAccounts,
 		"deployContractWithCall":               deployContractWithCall,
 		"callContract":                         callContract,
 		"callContractWithNonExisitingRevision": callContractWithNonExisitingRevision,
@@ -150,7 +156,7 @@ func getAccountWithNonExisitingRevision(t *testing.T) {
 
 	res, statusCode := httpGet(t, ts.URL+"/accounts/"+addr.String()+"?revision="+revision64Len)
 
-	assert.Equal(t, http.StatusBadRequest, statusCode, "bad revision")
+	assert.Equal(t, http.StatusNotFound, statusCode, "revision not found")
 	assert.Equal(t, "revision: leveldb: not found\n", string(res), "revision not found")
 }
 
@@ -186,6 +192,33 @@ func getAccountWithFinalizedRevision(t *testing.T) {
 	assert.Equal(t, genesisEnergy, finalizedEnergy, "finalized energy should equal genesis energy")
 }
 
+func getAccountWithSideChainRevision(t *testing.T) {
+	// the side block is not on the best chain
+	sideAccount := httpGetAccount(t, sideAddr.String()+"?revision="+sideBlockID.String())
+	bestAccount := httpGetAccount(t, sideAddr.String())
+
+	assert.Equal(t, math.HexOrDecimal256(*value), sideAccount.Balance)
+	assert.Equal(t, 0, (*big.Int)(&bestAccount.Balance).Sign())
+
+	// the side block is also at number 1, but the number refers to the best chain
+	numberAccount := httpGetAccount(t, sideAddr.String()+"?revision=1")
+	assert.Equal(t, 0, (*big.Int)(&numberAccount.Balance).Sign())
+}
+
+func getAccountWithMalformedRevision(t *testing.T) {
+	for _, revision := range []string{
+		invalidBytes32,
+		"0x00000000851caf3cfdb6e899cf5958bfb1ac3413d346d43539627e6be7ec1b", // truncated block ID
+		"abc",
+	} {
+		_, statusCode := httpGet(t, ts.URL+"/accounts/"+addr.String()+"?revision="+revision)
+		assert.Equal(t, http.StatusBadRequest, statusCode, revision)
+	}
+	// block number beyond the best block
+	_, statusCode := httpGet(t, ts.URL+"/accounts/"+addr.String()+"?revision=1000")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
 func getCode(t *testing.T) {
 	_, statusCode := httpGet(t, ts.URL+"/accounts/"+invalidAddr+"/code")
 	assert.Equal(t, http.StatusBadRequest, statusCode, "bad address")
@@ -212,7 +245,7 @@ func getCodeWithNonExisitingRevision(t *testing.T) {
 
 	res, statusCode := httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/code?revision="+revision64Len)
 
-	assert.Equal(t, http.StatusBadRequest, statusCode, "bad revision")
+	assert.Equal(t, http.StatusNotFound, statusCode, "revision not found")
 	assert.Equal(t, "revision: leveldb: not found\n", string(res), "revision not found")
 }
 
@@ -245,10 +278,111 @@ func getStorageWithNonExisitingRevision(t *testing.T) {
 
 	res, statusCode := httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage/"+storageKey.String()+"?revision="+revision64Len)
 
-	assert.Equal(t, http.StatusBadRequest, statusCode, "bad revision")
+	assert.Equal(t, http.StatusNotFound, statusCode, "revision not found")
 	assert.Equal(t, "revision: leveldb: not found\n", string(res), "revision not found")
 }
 
+func getBatchAccounts(t *testing.T) {
+	emptyAddr := thor.BytesToAddress([]byte("empty"))
+
+	res, statusCode := httpPost(t, ts.URL+"/accounts/batch", &accounts.BatchAccountsData{
+		Addresses: []thor.Address{addr, contractAddr, emptyAddr},
+	})
+	assert.Equal(t, http.StatusOK, statusCode)
+	var accs []*accounts.Account
+	if err := json.Unmarshal(res, &accs); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 3, len(accs))
+	assert.Equal(t, *httpGetAccount(t, addr.String()), *accs[0])
+	assert.True(t, accs[1].HasCode)
+	assert.Equal(t, 0, (*big.Int)(&accs[2].Balance).Sign())
+	assert.False(t, accs[2].HasCode)
+
+	// in the same state of the given revision
+	res, statusCode = httpPost(t, ts.URL+"/accounts/batch?revision="+genesisBlock.Header().ID().String(), &accounts.BatchAccountsData{
+		Addresses: []thor.Address{addr},
+	})
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(res, &accs); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(accs))
+	assert.Equal(t, *httpGetAccount(t, addr.String()+"?revision="+genesisBlock.Header().ID().String()), *accs[0])
+
+	// empty batch
+	res, statusCode = httpPost(t, ts.URL+"/accounts/batch", &accounts.BatchAccountsData{})
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "[]\n", string(res))
+
+	res, statusCode = httpPost(t, ts.URL+"/accounts/batch", &accounts.BatchAccountsData{
+		Addresses: []thor.Address{addr, addr, addr, addr},
+	})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, statusCode, "exceeds the batch limit")
+	assert.Equal(t, "addresses: exceeds the batch limit 3\n", string(res))
+
+	_, statusCode = httpPost(t, ts.URL+"/accounts/batch", map[string]interface{}{"addresses": []string{invalidAddr}})
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad address")
+
+	_, statusCode = httpPost(t, ts.URL+"/accounts/batch?revision="+invalidNumberRevision, &accounts.BatchAccountsData{})
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad revision")
+
+	_, statusCode = httpPost(t, ts.URL+"/accounts/batch?revision=0x00000000851caf3cfdb6e899cf5958bfb1ac3413d346d43539627e6be7ec1b4a", &accounts.BatchAccountsData{})
+	assert.Equal(t, http.StatusNotFound, statusCode, "revision not found")
+}
+
+func getStorageRange(t *testing.T) {
+	_, statusCode := httpGet(t, ts.URL+"/accounts/"+invalidAddr+"/storage/range")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad address")
+
+	_, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage/range?start="+invalidBytes32)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad start")
+
+	_, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage/range?limit=0")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad limit")
+
+	_, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage/range?limit=1001")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad limit")
+
+	_, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage/range?revision="+invalidNumberRevision)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad revision")
+
+	getRange := func(query string) *accounts.StorageRange {
+		res, statusCode := httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage/range"+query)
+		assert.Equal(t, http.StatusOK, statusCode, "OK")
+		var r accounts.StorageRange
+		if err := json.Unmarshal(res, &r); err != nil {
+			t.Fatal(err)
+		}
+		return &r
+	}
+
+	r := getRange("?limit=1")
+	assert.Equal(t, []*accounts.StorageEntry{{Key: storageKey, Value: thor.BytesToBytes32([]byte{storageValue})}}, r.Storage)
+	assert.Nil(t, r.Next)
+
+	// start is inclusive
+	hashedKey := thor.Blake2b(storageKey.Bytes())
+	r = getRange("?start=" + hashedKey.String())
+	assert.Len(t, r.Storage, 1)
+
+	// beyond the only key
+	hashedKey[31]++
+	r = getRange("?start=" + hashedKey.String())
+	assert.Empty(t, r.Storage)
+	assert.Nil(t, r.Next)
+
+	// at the historical state before the storage set
+	r = getRange("?revision=1")
+	assert.Empty(t, r.Storage)
+	assert.Nil(t, r.Next)
+
+	// account without storage
+	res, statusCode := httpGet(t, ts.URL+"/accounts/"+addr.String()+"/storage/range")
+	assert.Equal(t, http.StatusOK, statusCode, "OK")
+	assert.Equal(t, `{"storage":[],"next":null}`+"\n", string(res))
+}
+
 func initAccountServer(t *testing.T) {
 	db := muxdb.NewMem()
 	stater := state.NewStater(db)
@@ -277,9 +411,13 @@ func initAccountServer(t *testing.T) {
 	transactionCall := buildTxWithClauses(t, repo.ChainTag(), claCall)
 	packTx(repo, stater, transactionCall, t)
 
+	// a block off the genesis, conflicting with the best chain
+	claSide := tx.NewClause(&sideAddr).WithValue(value)
+	sideBlockID = packSideTx(repo, stater, repo.GenesisBlock().Header().ID(), buildTxWithClauses(t, repo.ChainTag(), claSide), t)
+
 	router := mux.NewRouter()
 	gasLimit = math.MaxUint32
-	acc = accounts.New(repo, stater, gasLimit, thor.NoFork, solo.NewBFTEngine(repo))
+	acc = accounts.New(repo, stater, gasLimit, thor.NoFork, solo.NewBFTEngine(repo), 3)
 	acc.Mount(router, "/accounts")
 	ts = httptest.NewServer(router)
 }
@@ -326,6 +464,37 @@ func packTx(repo *chain.Repository, stater *state.Stater, transaction *tx.Transa
 	}
 }
 
+// packSideTx packs the transaction into a block on the given parent without changing the best block.
+func packSideTx(repo *chain.Repository, stater *state.Stater, parentID thor.Bytes32, transaction *tx.Transaction, t *testing.T) thor.Bytes32 {
+	parent, err := repo.GetBlockSummary(parentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflicts, err := repo.ScanConflicts(parent.Header.Number() + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	packer := packer.New(repo, stater, genesis.DevAccounts()[0].Address, &genesis.DevAccounts()[0].Address, thor.NoFork)
+	flow, err := packer.Schedule(parent, uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := flow.Adopt(transaction); err != nil {
+		t.Fatal(err)
+	}
+	b, stage, receipts, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey, conflicts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddBlock(b, receipts, conflicts); err != nil {
+		t.Fatal(err)
+	}
+	return b.Header().ID()
+}
+
 func deployContractWithCall(t *testing.T) {
 	badBody := &accounts.CallData{
 		Gas:  10000000,
@@ -20,6 +20,23 @@ type Account struct {
 	HasCode bool                 `json:"hasCode"`
 }
 
+// BatchAccountsData is the body of batch account retrieval.
+type BatchAccountsData struct {
+	Addresses []thor.Address `json:"addresses"`
+}
+
+// StorageRange for marshal a range of account storage entries, ordered by hashed key.
+type StorageRange struct {
+	Storage []*StorageEntry `json:"storage"`
+	Next    *thor.Bytes32   `json:"next"` // the hashed key to continue with, nil if the end reached
+}
+
+// StorageEntry is a storage key and value pair.
+type StorageEntry struct {
+	Key   thor.Bytes32 `json:"key"`
+	Value thor.Bytes32 `json:"value"`
+}
+
 // CallData represents contract-call body
 type CallData struct {
 	Value    *math.HexOrDecimal256 `json:"value"`
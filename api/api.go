@@ -6,9 +6,12 @@
 package api
 
 import (
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/pprof"
 	"strings"
+	"time"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
@@ -18,10 +21,12 @@ import (
 	"github.com/vechain/thor/v2/api/debug"
 	"github.com/vechain/thor/v2/api/doc"
 	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/api/health"
 	"github.com/vechain/thor/v2/api/node"
 	"github.com/vechain/thor/v2/api/subscriptions"
 	"github.com/vechain/thor/v2/api/transactions"
 	"github.com/vechain/thor/v2/api/transfers"
+	"github.com/vechain/thor/v2/api/utils"
 	"github.com/vechain/thor/v2/bft"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/logdb"
@@ -32,6 +37,64 @@ import (
 
 var log = log15.New("pkg", "api")
 
+// EndpointGroups lists names of the endpoint groups which can be disabled.
+// The "transactions-post" group is the tx submission only, while "transactions" is all.
+var EndpointGroups = []string{
+	"accounts",
+	"logs",
+	"blocks",
+	"transactions",
+	"transactions-post",
+	"debug",
+	"node",
+	"subscriptions",
+	"health",
+}
+
+// ValidateEndpointGroups returns error if any group name is unknown.
+func ValidateEndpointGroups(groups []string) error {
+	for _, g := range groups {
+		known := false
+		for _, eg := range EndpointGroups {
+			if g == eg {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown endpoint group %q", g)
+		}
+	}
+	return nil
+}
+
+// Options optional parameters for the API.
+type Options struct {
+	AllowedOrigins     string // comma separated domains from which to accept cross origin requests
+	BacktraceLimit     uint32
+	CallGasLimit       uint64
+	TraceGasLimit      uint64 // follows CallGasLimit if 0
+	PprofOn            bool
+	SkipLogs           bool
+	AllowCustomTracer  bool
+	EnableReqLogger    bool
+	EnableMetrics      bool
+	LogsLimit          uint64
+	RateLimiter        RateLimiter // not rate limited if nil
+	TrustedProxies     []*net.IPNet
+	DisabledEndpoints  []string // names of EndpointGroups
+	SubsMsgQueueSize   int
+	BlockInterval      uint64
+	MaxRequestBody     int64 // no limit if 0
+	SignerCacheSize    int
+	IdempotencyTTL     time.Duration
+	DisableCompression bool
+	ReadyMaxLag        uint32
+	AccountsBatchLimit uint64
+	AdminAllowlist     []*net.IPNet
+	Production         node.Production // /node/production not mounted if nil
+}
+
 // New return api router
 func New(
 	repo *chain.Repository,
@@ -41,21 +104,23 @@ func New(
 	bft bft.Finalizer,
 	nw node.Network,
 	forkConfig thor.ForkConfig,
-	allowedOrigins string,
-	backtraceLimit uint32,
-	callGasLimit uint64,
-	pprofOn bool,
-	skipLogs bool,
-	allowCustomTracer bool,
-	enableReqLogger bool,
-	enableMetrics bool,
-	logsLimit uint64,
+	opts Options,
 ) (http.HandlerFunc, func()) {
-	origins := strings.Split(strings.TrimSpace(allowedOrigins), ",")
+	origins := strings.Split(strings.TrimSpace(opts.AllowedOrigins), ",")
 	for i, o := range origins {
 		origins[i] = strings.ToLower(strings.TrimSpace(o))
 	}
 
+	// trace gas limit follows the call one if not set
+	if opts.TraceGasLimit == 0 {
+		opts.TraceGasLimit = opts.CallGasLimit
+	}
+
+	disabled := make(map[string]bool)
+	for _, g := range opts.DisabledEndpoints {
+		disabled[g] = true
+	}
+
 	router := mux.NewRouter()
 
 	// to serve stoplight, swagger and api docs
@@ -69,27 +134,47 @@ func New(
 			http.Redirect(w, req, "doc/stoplight-ui/", http.StatusTemporaryRedirect)
 		})
 
-	accounts.New(repo, stater, callGasLimit, forkConfig, bft).
-		Mount(router, "/accounts")
-
-	if !skipLogs {
-		events.New(repo, logDB, logsLimit).
+	// disabled endpoints are simply not mounted, which results in 404
+	if !disabled["accounts"] {
+		accounts.New(repo, stater, opts.CallGasLimit, forkConfig, bft, opts.AccountsBatchLimit).
+			Mount(router, "/accounts")
+	}
+	if !opts.SkipLogs && !disabled["logs"] {
+		events.New(repo, logDB, opts.LogsLimit).
 			Mount(router, "/logs/event")
-		transfers.New(repo, logDB, logsLimit).
+		transfers.New(repo, logDB, opts.LogsLimit).
 			Mount(router, "/logs/transfer")
 	}
-	blocks.New(repo, bft).
-		Mount(router, "/blocks")
-	transactions.New(repo, txPool).
-		Mount(router, "/transactions")
-	debug.New(repo, stater, forkConfig, callGasLimit, allowCustomTracer, bft).
-		Mount(router, "/debug")
-	node.New(nw).
-		Mount(router, "/node")
-	subs := subscriptions.New(repo, origins, backtraceLimit, txPool)
-	subs.Mount(router, "/subscriptions")
-
-	if pprofOn {
+	if !disabled["blocks"] {
+		blocks.New(repo, bft).
+			Mount(router, "/blocks")
+	}
+	if !disabled["transactions"] {
+		if disabled["transactions-post"] {
+			// routes are matched in order, so it shadows the tx submission route mounted below
+			router.Path("/transactions").Methods(http.MethodPost).Handler(http.NotFoundHandler())
+		}
+		transactions.New(repo, txPool, opts.BlockInterval, opts.SignerCacheSize, opts.IdempotencyTTL).
+			Mount(router, "/transactions")
+	}
+	if !disabled["debug"] {
+		debug.New(repo, stater, forkConfig, opts.TraceGasLimit, opts.AllowCustomTracer, bft).
+			Mount(router, "/debug")
+	}
+	if !disabled["node"] {
+		node.New(repo, stater, txPool, nw, opts.BlockInterval, forkConfig, opts.Production).
+			Mount(router, "/node")
+	}
+	if !disabled["health"] {
+		health.New(repo, nw, opts.ReadyMaxLag).
+			Mount(router, "/health")
+	}
+	subs := subscriptions.New(repo, origins, opts.BacktraceLimit, txPool, opts.SubsMsgQueueSize)
+	if !disabled["subscriptions"] {
+		subs.Mount(router, "/subscriptions")
+	}
+
+	if opts.PprofOn {
 		router.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
 		router.HandleFunc("/debug/pprof/profile", pprof.Profile)
 		router.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
@@ -97,20 +182,44 @@ func New(
 		router.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
 	}
 
-	if enableMetrics {
+	if opts.EnableMetrics {
 		router.Use(metricsMiddleware)
 	}
 
-	handler := handlers.CompressHandler(router)
+	var handler http.Handler = router
+	if !opts.DisableCompression {
+		handler = CompressHandler(handler, compressMinSize)
+	}
+	if opts.RateLimiter != nil {
+		handler = RateLimitHandler(handler, opts.RateLimiter, opts.TrustedProxies)
+	}
+	if opts.AdminAllowlist != nil {
+		// the debug endpoints, including pprof, are expensive and expose node internals
+		handler = IPAllowlistHandler(handler, "/debug", opts.AdminAllowlist)
+	}
+	// the tx pool content and pausing block production are never open to the public, loopback only if no allowlist set
+	adminAllowlist := opts.AdminAllowlist
+	if adminAllowlist == nil {
+		adminAllowlist = loopbackNets
+	}
+	handler = IPAllowlistHandler(handler, "/node/txpool", adminAllowlist)
+	if opts.Production != nil {
+		handler = IPAllowlistHandler(handler, "/node/production", adminAllowlist)
+	}
 	handler = handlers.CORS(
 		handlers.AllowedOrigins(origins),
-		handlers.AllowedHeaders([]string{"content-type", "x-genesis-id"}),
-		handlers.ExposedHeaders([]string{"x-genesis-id", "x-thorest-ver"}),
+		handlers.AllowedHeaders([]string{"content-type", "x-genesis-id", "x-request-id", "idempotency-key"}),
+		handlers.ExposedHeaders([]string{"x-genesis-id", "x-thorest-ver", "x-request-id", utils.LogsCursorHeader}),
 	)(handler)
 
-	if enableReqLogger {
+	if opts.EnableReqLogger {
 		handler = RequestLoggerHandler(handler, log)
 	}
+	// applied before the request logger, which reads the whole body
+	if opts.MaxRequestBody > 0 {
+		handler = MaxRequestBodyHandler(handler, opts.MaxRequestBody)
+	}
+	handler = RequestIDHandler(handler)
 
 	return handler.ServeHTTP, subs.Close // subscriptions handles hijacked conns, which need to be closed
 }
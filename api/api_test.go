@@ -0,0 +1,140 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/solo"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+func TestValidateEndpointGroups(t *testing.T) {
+	assert.Nil(t, ValidateEndpointGroups(nil))
+	assert.Nil(t, ValidateEndpointGroups(EndpointGroups))
+	assert.EqualError(t, ValidateEndpointGroups([]string{"blocks", "foo"}), `unknown endpoint group "foo"`)
+}
+
+// newTestAPI serves the API of a devnet with the given options.
+func newTestAPI(t *testing.T, opts Options) *httptest.Server {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, _ := chain.NewRepository(db, b)
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { logDB.Close() })
+	txPool := txpool.New(repo, stater, txpool.Options{Limit: 100, LimitPerAccount: 16, MaxLifetime: time.Hour})
+	t.Cleanup(txPool.Close)
+
+	handler, closer := New(
+		repo,
+		stater,
+		txPool,
+		logDB,
+		solo.NewBFTEngine(repo),
+		&solo.Communicator{},
+		thor.NoFork,
+		opts,
+	)
+	t.Cleanup(closer)
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestDisabledEndpoints(t *testing.T) {
+	ts := newTestAPI(t, Options{
+		AllowedOrigins:    "*",
+		LogsLimit:         1000,
+		DisabledEndpoints: []string{"transactions-post", "subscriptions"},
+		BlockInterval:     thor.BlockInterval,
+	})
+
+	_, code := httpGet(t, ts.URL+"/blocks/best")
+	assert.Equal(t, http.StatusOK, code)
+
+	_, code = httpGet(t, ts.URL+"/accounts/"+thor.Address{}.String())
+	assert.Equal(t, http.StatusOK, code)
+
+	// tx submission is disabled, while the query is still available
+	res, err := http.Post(ts.URL+"/transactions", "application/json", strings.NewReader("{}")) // nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	_, code = httpGet(t, ts.URL+"/transactions/"+thor.Bytes32{}.String())
+	assert.Equal(t, http.StatusOK, code)
+
+	_, code = httpGet(t, ts.URL+"/subscriptions/beat2")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestCompression(t *testing.T) {
+	get := func(ts *httptest.Server) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/doc/thor.yaml", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// set explicitly to disable the transparent decompression
+		req.Header.Set("Accept-Encoding", "gzip")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res
+	}
+
+	// enabled by default
+	res := get(newTestAPI(t, Options{}))
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "gzip", res.Header.Get("Content-Encoding"))
+
+	res = get(newTestAPI(t, Options{DisableCompression: true}))
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "", res.Header.Get("Content-Encoding"))
+}
+
+func TestTxPoolAllowlist(t *testing.T) {
+	get := func(ts *httptest.Server, remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/node/txpool", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		ts.Config.Handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// loopback only if no allowlist set
+	ts := newTestAPI(t, Options{})
+	assert.Equal(t, http.StatusOK, get(ts, "127.0.0.1:1234"))
+	assert.Equal(t, http.StatusOK, get(ts, "[::1]:1234"))
+	assert.Equal(t, http.StatusForbidden, get(ts, "10.1.2.3:1234"))
+
+	_, allowed, _ := net.ParseCIDR("10.0.0.0/8")
+	ts = newTestAPI(t, Options{AdminAllowlist: []*net.IPNet{allowed}})
+	assert.Equal(t, http.StatusOK, get(ts, "10.1.2.3:1234"))
+	assert.Equal(t, http.StatusForbidden, get(ts, "127.0.0.1:1234"))
+}
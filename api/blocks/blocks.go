@@ -8,6 +8,8 @@ package blocks
 import (
 	"net/http"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/v2/api/utils"
@@ -38,6 +40,10 @@ func (b *Blocks) handleGetBlock(w http.ResponseWriter, req *http.Request) error
 	if expanded != "" && expanded != "false" && expanded != "true" {
 		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "expanded"))
 	}
+	raw := req.URL.Query().Get("raw")
+	if raw != "" && raw != "false" && raw != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "raw"))
+	}
 
 	summary, err := utils.GetSummary(revision, b.repo, b.bft)
 	if err != nil {
@@ -60,6 +66,22 @@ func (b *Blocks) handleGetBlock(w http.ResponseWriter, req *http.Request) error
 		}
 	}
 
+	if raw == "true" {
+		blk, err := b.repo.GetBlock(summary.Header.ID())
+		if err != nil {
+			return err
+		}
+		data, err := rlp.EncodeToBytes(blk)
+		if err != nil {
+			return err
+		}
+		return utils.WriteJSON(w, &JSONRawBlockSummary{
+			Raw:         hexutil.Encode(data),
+			IsTrunk:     isTrunk,
+			IsFinalized: isFinalized,
+		})
+	}
+
 	jSummary := buildJSONBlockSummary(summary, isTrunk, isFinalized)
 	if expanded == "true" {
 		txs, err := b.repo.GetBlockTransactions(summary.Header.ID())
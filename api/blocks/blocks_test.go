@@ -17,7 +17,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/api/blocks"
@@ -49,6 +51,7 @@ func TestBlock(t *testing.T) {
 		"testGetBlockById":                      testGetBlockById,
 		"testGetBlockNotFound":                  testGetBlockNotFound,
 		"testGetExpandedBlockById":              testGetExpandedBlockById,
+		"testGetRawBlockById":                   testGetRawBlockById,
 		"testGetBlockByHeight":                  testGetBlockByHeight,
 		"testGetBestBlock":                      testGetBestBlock,
 		"testGetFinalizedBlock":                 testGetFinalizedBlock,
@@ -126,6 +129,27 @@ func testGetExpandedBlockById(t *testing.T) {
 	assert.Equal(t, http.StatusOK, statusCode)
 }
 
+func testGetRawBlockById(t *testing.T) {
+	res, statusCode := httpGet(t, ts.URL+"/blocks/"+blk.Header().ID().String()+"?raw=true")
+	rb := new(blocks.JSONRawBlockSummary)
+	if err := json.Unmarshal(res, rb); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.True(t, rb.IsTrunk)
+
+	data, err := hexutil.Decode(rb.Raw)
+	assert.NoError(t, err)
+	var decoded block.Block
+	assert.NoError(t, rlp.DecodeBytes(data, &decoded))
+	assert.Equal(t, blk.Header().ID(), decoded.Header().ID())
+	assert.Equal(t, blk.Transactions().RootHash(), decoded.Transactions().RootHash())
+
+	res, statusCode = httpGet(t, ts.URL+"/blocks/best?raw=1")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "raw: should be boolean", strings.TrimSpace(string(res)))
+}
+
 func testInvalidBlockNumber(t *testing.T) {
 	invalidNumberRevision := "4294967296" //invalid block number
 	_, statusCode := httpGet(t, ts.URL+"/blocks/"+invalidNumberRevision)
@@ -38,6 +38,13 @@ type JSONCollapsedBlock struct {
 	Transactions []thor.Bytes32 `json:"transactions"`
 }
 
+// JSONRawBlockSummary is the RLP encoded block, including its transactions.
+type JSONRawBlockSummary struct {
+	Raw         string `json:"raw"`
+	IsTrunk     bool   `json:"isTrunk"`
+	IsFinalized bool   `json:"isFinalized"`
+}
+
 type JSONClause struct {
 	To    *thor.Address        `json:"to"`
 	Value math.HexOrDecimal256 `json:"value"`
@@ -115,6 +122,14 @@ func buildJSONBlockSummary(summary *chain.BlockSummary, isTrunk bool, isFinalize
 	}
 }
 
+// BuildJSONExpandedBlock builds the expanded block, in the same form as replied by the blocks API.
+func BuildJSONExpandedBlock(summary *chain.BlockSummary, txs tx.Transactions, receipts tx.Receipts, isTrunk bool, isFinalized bool) *JSONExpandedBlock {
+	return &JSONExpandedBlock{
+		buildJSONBlockSummary(summary, isTrunk, isFinalized),
+		buildJSONEmbeddedTxs(txs, receipts),
+	}
+}
+
 func buildJSONOutput(txID thor.Bytes32, index uint32, c *tx.Clause, o *tx.Output) *JSONOutput {
 	jo := &JSONOutput{
 		ContractAddress: nil,
@@ -0,0 +1,192 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// compressMinSize is the min size of response body to be compressed.
+const compressMinSize = 1024
+
+// CompressHandler returns a http handler which compresses responses with gzip or deflate, according
+// to the request's Accept-Encoding header. Responses are buffered until minSize bytes written, and
+// smaller ones are sent as is with Content-Length set. Subscriptions are excluded, since they are
+// hijacked websocket connections.
+func CompressHandler(handler http.Handler, minSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// always vary, to prevent intermediate caches from serving the wrong encoding
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" ||
+			r.Method == http.MethodHead ||
+			r.Header.Get("Upgrade") != "" ||
+			strings.HasPrefix(r.URL.Path, "/subscriptions") {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressResponseWriter{
+			ResponseWriter: w,
+			encoding:       encoding,
+			minSize:        minSize,
+		}
+		defer cw.Close()
+		handler.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding returns the preferred encoding accepted by the client, gzip over deflate.
+// Empty string returned if neither is accepted.
+func negotiateEncoding(acceptEncoding string) string {
+	var gz, df bool
+	for _, enc := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(enc), ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "gzip":
+			gz = true
+		case "deflate":
+			df = true
+		}
+	}
+	if gz {
+		return "gzip"
+	}
+	if df {
+		return "deflate"
+	}
+	return ""
+}
+
+// compressResponseWriter buffers the response body until it's decided whether to compress.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status  int
+	buf     []byte
+	started bool
+	enc     io.WriteCloser // nil if not compressing
+}
+
+func (cw *compressResponseWriter) WriteHeader(status int) {
+	// informational headers are sent right away
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if cw.status == 0 {
+		cw.status = status
+	}
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if cw.started {
+		if cw.enc != nil {
+			return cw.enc.Write(b)
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.start(false); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush starts compressing, since the response is being streamed.
+func (cw *compressResponseWriter) Flush() {
+	if !cw.started {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		if err := cw.start(false); err != nil {
+			return
+		}
+	}
+	if f, ok := cw.enc.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close sends the buffered response if not yet, and finishes the compression.
+func (cw *compressResponseWriter) Close() error {
+	if !cw.started {
+		if cw.status == 0 {
+			// nothing written by the handler
+			return nil
+		}
+		if err := cw.start(true); err != nil {
+			return err
+		}
+	}
+	if cw.enc != nil {
+		return cw.enc.Close()
+	}
+	return nil
+}
+
+// start writes the header and the buffered body. A complete body smaller than minSize is sent as is
+// with Content-Length, otherwise it's compressed unless already encoded by the handler.
+func (cw *compressResponseWriter) start(complete bool) error {
+	cw.started = true
+	compress := !complete || len(cw.buf) >= cw.minSize
+
+	h := cw.Header()
+	if h.Get("Content-Type") == "" && len(cw.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+	if h.Get("Content-Encoding") != "" || !bodyAllowed(cw.status) {
+		compress = false
+	}
+
+	if compress {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", cw.encoding)
+		if cw.encoding == "gzip" {
+			cw.enc = gzip.NewWriter(cw.ResponseWriter)
+		} else {
+			cw.enc, _ = flate.NewWriter(cw.ResponseWriter, flate.DefaultCompression)
+		}
+		cw.ResponseWriter.WriteHeader(cw.status)
+		_, err := cw.enc.Write(cw.buf)
+		cw.buf = nil
+		return err
+	}
+
+	if complete && h.Get("Content-Length") == "" && bodyAllowed(cw.status) {
+		h.Set("Content-Length", strconv.Itoa(len(cw.buf)))
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+	_, err := cw.ResponseWriter.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
+
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
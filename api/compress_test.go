@@ -0,0 +1,109 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressHandler(t *testing.T) {
+	small := strings.Repeat("a", 10)
+	large := strings.Repeat("a", 100)
+	handler := CompressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/small":
+			io.WriteString(w, small)
+		case "/stream", "/subscriptions/beat":
+			// written in pieces, each smaller than the threshold
+			for i := 0; i < 10; i++ {
+				io.WriteString(w, large[:10])
+			}
+		case "/flush":
+			io.WriteString(w, small)
+			w.(http.Flusher).Flush()
+		case "/error":
+			http.Error(w, large, http.StatusBadRequest)
+		}
+	}), 50)
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+	decode := func(rr *httptest.ResponseRecorder) string {
+		var r io.Reader
+		switch rr.Header().Get("Content-Encoding") {
+		case "gzip":
+			gr, err := gzip.NewReader(rr.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r = gr
+		case "deflate":
+			r = flate.NewReader(rr.Body)
+		default:
+			r = rr.Body
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	// small body sent as is
+	rr := get("/small", "gzip")
+	assert.Equal(t, "", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "10", rr.Header().Get("Content-Length"))
+	assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+	assert.Equal(t, small, rr.Body.String())
+
+	rr = get("/stream", "gzip, deflate")
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "", rr.Header().Get("Content-Length"))
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Equal(t, large, decode(rr))
+
+	rr = get("/stream", "deflate, gzip;q=0")
+	assert.Equal(t, "deflate", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, decode(rr))
+
+	// not accepted
+	rr = get("/stream", "")
+	assert.Equal(t, "", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, rr.Body.String())
+	rr = get("/stream", "br")
+	assert.Equal(t, "", rr.Header().Get("Content-Encoding"))
+
+	// subscriptions are excluded
+	rr = get("/subscriptions/beat", "gzip")
+	assert.Equal(t, "", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, rr.Body.String())
+
+	// flushing starts compression
+	rr = get("/flush", "gzip")
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, small, decode(rr))
+
+	rr = get("/error", "gzip")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, large+"\n", decode(rr))
+}
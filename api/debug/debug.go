@@ -8,6 +8,7 @@ package debug
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math"
 	"math/big"
 	"net/http"
@@ -38,6 +39,9 @@ import (
 
 var devNetGenesisID = genesis.NewDevnet().ID()
 
+// maxBundleTxs is the max number of transactions of a bundle to be simulated.
+const maxBundleTxs = 64
+
 type Debug struct {
 	repo              *chain.Repository
 	stater            *state.Stater
@@ -159,6 +163,9 @@ func (d *Debug) handleTraceClause(w http.ResponseWriter, req *http.Request) erro
 
 	tracer, err := d.createTracer(opt.Name, opt.Config)
 	if err != nil {
+		if err == tracers.ErrJSTracerDisabled {
+			return utils.BadRequest(errors.WithMessage(err, "name"))
+		}
 		return utils.Forbidden(err)
 	}
 
@@ -192,19 +199,33 @@ func (d *Debug) handleTraceCall(w http.ResponseWriter, req *http.Request) error
 
 	tracer, err := d.createTracer(opt.Name, opt.Config)
 	if err != nil {
+		if err == tracers.ErrJSTracerDisabled {
+			return utils.BadRequest(errors.WithMessage(err, "name"))
+		}
 		return utils.Forbidden(err)
 	}
 
-	txCtx, gas, clause, err := d.handleTraceCallOption(&opt)
+	txCtx, gas, clauses, err := d.handleTraceCallOption(&opt)
 	if err != nil {
 		return err
 	}
 
-	res, err := d.traceCall(req.Context(), tracer, summary.Header, st, txCtx, gas, clause)
+	var clauseIndex uint32
+	if opt.ClauseIndex != nil {
+		if *opt.ClauseIndex >= uint32(len(clauses)) {
+			return utils.BadRequest(errors.New("clauseIndex: out of range"))
+		}
+		clauseIndex = *opt.ClauseIndex
+	}
+
+	res, gasUsed, err := d.traceCall(req.Context(), tracer, summary.Header, st, txCtx, gas, clauses, clauseIndex)
 	if err != nil {
 		return err
 	}
 
+	if opt.PerClause {
+		return utils.WriteJSON(w, &PerClauseTraceResult{Result: res, GasUsed: gasUsed})
+	}
 	return utils.WriteJSON(w, res)
 }
 
@@ -215,7 +236,19 @@ func (d *Debug) createTracer(name string, config json.RawMessage) (tracers.Trace
 	return tracers.DefaultDirectory.New(name, config, d.allowCustomTracer)
 }
 
-func (d *Debug) traceCall(ctx context.Context, tracer tracers.Tracer, header *block.Header, st *state.State, txCtx *xenv.TransactionContext, gas uint64, clause *tx.Clause) (interface{}, error) {
+// traceCall executes the clauses in sequence, and traces the clause at clauseIndex.
+// It returns the trace result, and the gas used by each executed clause. Like a transaction,
+// the execution stops once a clause reverted.
+func (d *Debug) traceCall(
+	ctx context.Context,
+	tracer tracers.Tracer,
+	header *block.Header,
+	st *state.State,
+	txCtx *xenv.TransactionContext,
+	gas uint64,
+	clauses []*tx.Clause,
+	clauseIndex uint32,
+) (interface{}, []uint64, error) {
 	signer, _ := header.Signer()
 
 	rt := runtime.New(
@@ -232,30 +265,192 @@ func (d *Debug) traceCall(ctx context.Context, tracer tracers.Tracer, header *bl
 		d.forkConfig)
 
 	tracer.SetContext(&tracers.Context{
-		BlockID:   header.ID(),
-		BlockTime: header.Timestamp(),
-		State:     st,
+		BlockID:     header.ID(),
+		BlockTime:   header.Timestamp(),
+		ClauseIndex: clauseIndex,
+		State:       st,
 	})
-	rt.SetVMConfig(vm.Config{Tracer: tracer})
 
-	errCh := make(chan error, 1)
-	exec, interrupt := rt.PrepareClause(clause, 0, gas, txCtx)
-	go func() {
-		_, _, err := exec()
-		errCh <- err
-	}()
-	select {
-	case <-ctx.Done():
-		err := ctx.Err()
-		tracer.Stop(err)
-		interrupt()
+	type result struct {
+		output *runtime.Output
+		err    error
+	}
+
+	gasUsed := make([]uint64, 0, len(clauses))
+	resultCh := make(chan result, 1)
+loop:
+	for i, clause := range clauses {
+		if uint32(i) == clauseIndex {
+			rt.SetVMConfig(vm.Config{Tracer: tracer})
+		} else {
+			rt.SetVMConfig(vm.Config{})
+		}
+
+		exec, interrupt := rt.PrepareClause(clause, uint32(i), gas, txCtx)
+		go func() {
+			output, _, err := exec()
+			resultCh <- result{output, err}
+		}()
+
+		select {
+		case <-ctx.Done():
+			err := ctx.Err()
+			if uint32(i) == clauseIndex {
+				tracer.Stop(err)
+			}
+			interrupt()
+			return nil, nil, err
+		case r := <-resultCh:
+			if r.err != nil {
+				return nil, nil, r.err
+			}
+			// a reverted clause consumes gas as well
+			gasUsed = append(gasUsed, gas-r.output.LeftOverGas)
+			gas = r.output.LeftOverGas
+			if r.output.VMErr != nil {
+				if uint32(i) < clauseIndex {
+					return nil, nil, utils.BadRequest(errors.Errorf("clauseIndex: unreachable, clauses[%d] reverted", i))
+				}
+				// the remaining clauses are not executed, same as a transaction does
+				break loop
+			}
+		}
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, gasUsed, nil
+}
+
+// simulateBundle executes the transactions in sequence on top of the parent block, and computes the resulted state root
+// without committing. A failed or reverted transaction is skipped with its state changes reverted, unless atomic is set,
+// which aborts the bundle.
+func (d *Debug) simulateBundle(ctx context.Context, parent *chain.BlockSummary, st *state.State, blockCtx *xenv.BlockContext, txs tx.Transactions, atomic bool) (*SimulateBundleResult, error) {
+	rt := runtime.New(d.repo.NewChain(parent.Header.ID()), st, blockCtx, d.forkConfig)
+
+	var (
+		chainTag = d.repo.ChainTag()
+		gasUsed  uint64
+		results  = make([]*BundleTxResult, 0, len(txs))
+	)
+	for _, trx := range txs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result := &BundleTxResult{TxID: trx.ID()}
+		results = append(results, result)
+
+		// same checks as the packer does on adopting a tx
+		if trx.ChainTag() != chainTag {
+			result.Error = "chain tag mismatch"
+		} else if trx.BlockRef().Number() > blockCtx.Number {
+			result.Error = "block ref out of schedule"
+		} else if trx.IsExpired(blockCtx.Number) {
+			result.Error = "expired"
+		} else if trx.Gas() > blockCtx.GasLimit-gasUsed {
+			result.Error = "gas limit reached"
+		} else {
+			checkpoint := st.NewCheckpoint()
+			receipt, err := rt.ExecuteTransaction(trx)
+			if err != nil {
+				st.RevertTo(checkpoint)
+				result.Error = err.Error()
+			} else {
+				gasUsed += receipt.GasUsed
+				result.Receipt = convertBundleReceipt(receipt, trx)
+			}
+		}
+
+		if atomic && (result.Error != "" || result.Receipt.Reverted) {
+			return &SimulateBundleResult{Results: results, Aborted: true}, nil
+		}
+	}
+
+	// the block to be built conflicts with the saved ones of the same number
+	conflicts, err := d.repo.ScanConflicts(blockCtx.Number)
+	if err != nil {
 		return nil, err
-	case err := <-errCh:
+	}
+	// staging only hashes the tries, nodes are neither written nor cached unless committed
+	stage, err := st.StageContext(ctx, blockCtx.Number, conflicts)
+	if err != nil {
+		return nil, err
+	}
+	// changes are never committed
+	defer stage.Revert()
+
+	root := stage.Hash()
+	return &SimulateBundleResult{Results: results, StateRoot: &root}, nil
+}
+
+func (d *Debug) handleSimulateBundle(w http.ResponseWriter, req *http.Request) error {
+	var opt SimulateBundleOption
+	if err := utils.ParseJSON(req.Body, &opt); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if len(opt.Transactions) == 0 {
+		return utils.BadRequest(errors.New("transactions: empty"))
+	}
+	if len(opt.Transactions) > maxBundleTxs {
+		return utils.Forbidden(errors.Errorf("transactions: exceeds limit of %d", maxBundleTxs))
+	}
+	if opt.GasLimit != nil && *opt.GasLimit > d.callGasLimit {
+		return utils.Forbidden(errors.New("gas: exceeds limit"))
+	}
+	txs := make(tx.Transactions, 0, len(opt.Transactions))
+	for i, raw := range opt.Transactions {
+		data, err := hexutil.Decode(raw)
 		if err != nil {
-			return nil, err
+			return utils.BadRequest(errors.WithMessage(err, fmt.Sprintf("transactions[%d]", i)))
+		}
+		var trx *tx.Transaction
+		if err := rlp.DecodeBytes(data, &trx); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, fmt.Sprintf("transactions[%d]", i)))
 		}
+		txs = append(txs, trx)
 	}
-	return tracer.GetResult()
+
+	revision, err := utils.ParseRevision(req.URL.Query().Get("revision"), false)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "revision"))
+	}
+	summary, st, err := utils.GetSummaryAndState(revision, d.repo, d.bft, d.stater)
+	if err != nil {
+		if d.repo.IsNotFound(err) {
+			return utils.BadRequest(errors.WithMessage(err, "revision"))
+		}
+		return err
+	}
+
+	// the context of the block to be built on top of the revision block
+	parent := summary.Header
+	blockCtx := &xenv.BlockContext{
+		Beneficiary: parent.Beneficiary(),
+		Number:      parent.Number() + 1,
+		Time:        parent.Timestamp() + thor.BlockInterval,
+		GasLimit:    parent.GasLimit(),
+		TotalScore:  parent.TotalScore(),
+	}
+	if opt.Beneficiary != nil {
+		blockCtx.Beneficiary = *opt.Beneficiary
+	}
+	if opt.Timestamp != nil {
+		if *opt.Timestamp <= parent.Timestamp() {
+			return utils.BadRequest(errors.New("timestamp: not after the revision block"))
+		}
+		blockCtx.Time = *opt.Timestamp
+	}
+	if opt.GasLimit != nil {
+		blockCtx.GasLimit = *opt.GasLimit
+	}
+
+	res, err := d.simulateBundle(req.Context(), summary, st, blockCtx, txs, opt.Atomic)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, res)
 }
 
 func (d *Debug) debugStorage(ctx context.Context, contractAddress thor.Address, blockID thor.Bytes32, txIndex uint64, clauseIndex uint32, keyStart []byte, maxResult int) (*StorageRangeResult, error) {
@@ -355,7 +550,7 @@ func (d *Debug) parseTarget(target string) (blockID thor.Bytes32, txIndex uint64
 	return
 }
 
-func (d *Debug) handleTraceCallOption(opt *TraceCallOption) (*xenv.TransactionContext, uint64, *tx.Clause, error) {
+func (d *Debug) handleTraceCallOption(opt *TraceCallOption) (*xenv.TransactionContext, uint64, []*tx.Clause, error) {
 	gas := opt.Gas
 	if opt.Gas > d.callGasLimit {
 		return nil, 0, nil, utils.Forbidden(errors.New("gas: exceeds limit"))
@@ -415,8 +610,32 @@ func (d *Debug) handleTraceCallOption(opt *TraceCallOption) (*xenv.TransactionCo
 		}
 	}
 
-	clause := tx.NewClause(opt.To).WithValue(value).WithData(data)
-	return &txCtx, gas, clause, nil
+	if len(opt.Clauses) == 0 {
+		return &txCtx, gas, []*tx.Clause{tx.NewClause(opt.To).WithValue(value).WithData(data)}, nil
+	}
+
+	if opt.To != nil || opt.Value != nil || opt.Data != "" {
+		return nil, 0, nil, utils.BadRequest(errors.New("clauses: can not be used together with to, value or data"))
+	}
+	clauses := make([]*tx.Clause, 0, len(opt.Clauses))
+	for i, c := range opt.Clauses {
+		if c == nil {
+			return nil, 0, nil, utils.BadRequest(errors.Errorf("clauses[%d]: null", i))
+		}
+		value := new(big.Int)
+		if c.Value != nil {
+			value = (*big.Int)(c.Value)
+		}
+		var data []byte
+		if c.Data != "" {
+			data, err = hexutil.Decode(c.Data)
+			if err != nil {
+				return nil, 0, nil, utils.BadRequest(errors.WithMessage(err, fmt.Sprintf("clauses[%d].data", i)))
+			}
+		}
+		clauses = append(clauses, tx.NewClause(c.To).WithValue(value).WithData(data))
+	}
+	return &txCtx, gas, clauses, nil
 }
 
 func (d *Debug) Mount(root *mux.Router, pathPrefix string) {
@@ -430,6 +649,10 @@ func (d *Debug) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodPost).
 		Name("debug_trace_call").
 		HandlerFunc(utils.WrapHandlerFunc(d.handleTraceCall))
+	sub.Path("/simulateBundle").
+		Methods(http.MethodPost).
+		Name("debug_simulate_bundle").
+		HandlerFunc(utils.WrapHandlerFunc(d.handleSimulateBundle))
 	sub.Path("/storage-range").
 		Methods(http.MethodPost).
 		Name("debug_trace_storage").
@@ -22,9 +22,11 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/builtin"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/cmd/thor/solo"
 	"github.com/vechain/thor/v2/genesis"
@@ -32,6 +34,7 @@ import (
 	"github.com/vechain/thor/v2/packer"
 	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tracers"
 	"github.com/vechain/thor/v2/tracers/logger"
 	"github.com/vechain/thor/v2/tx"
 
@@ -78,6 +81,17 @@ func TestDebug(t *testing.T) {
 		"testHandleTraceCallWithBadBlockRef":                 testHandleTraceCallWithBadBlockRef,
 		"testHandleTraceCallWithInvalidLengthBlockRef":       testHandleTraceCallWithInvalidLengthBlockRef,
 		"testTraceCallNextBlock":                             testTraceCallNextBlock,
+		"testHandleTraceCallWithClauses":                     testHandleTraceCallWithClauses,
+		"testHandleTraceCallWithBadClauses":                  testHandleTraceCallWithBadClauses,
+	} {
+		t.Run(name, tt)
+	}
+
+	// /simulateBundle endpoint
+	for name, tt := range map[string]func(*testing.T){
+		"testSimulateBundleWithBadBody": testSimulateBundleWithBadBody,
+		"testSimulateBundle":            testSimulateBundle,
+		"testSimulateBundleAtomic":      testSimulateBundleAtomic,
 	} {
 		t.Run(name, tt)
 	}
@@ -89,6 +103,9 @@ func TestDebug(t *testing.T) {
 	} {
 		t.Run(name, tt)
 	}
+
+	// checked last, since disabling JS tracers is global
+	t.Run("testTraceClauseWithJSTracerDisabled", testTraceClauseWithJSTracerDisabled)
 }
 
 func TestStorageRangeFunc(t *testing.T) {
@@ -200,6 +217,19 @@ func testTraceClauseWithCustomTracer(t *testing.T) {
 	assert.Equal(t, expectedExecutionResult, parsedExecutionRes)
 }
 
+func testTraceClauseWithJSTracerDisabled(t *testing.T) {
+	tracers.DefaultDirectory.DisableJS()
+
+	target := fmt.Sprintf("%s/%s/1", blk.Header().ID(), transaction.ID())
+	for _, name := range []string{"opcount", "{result: function() { return 1 }, fault: function() {}}"} {
+		res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers", &TraceClauseOption{Target: target, Name: name}, 400)
+		assert.Equal(t, "name: js tracer disabled", strings.TrimSpace(res))
+	}
+
+	// native tracers are still available
+	httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers", &TraceClauseOption{Target: target, Name: "callTracer"}, 200)
+}
+
 func testTraceClause(t *testing.T) {
 	traceClauseOption := &TraceClauseOption{
 		Target: fmt.Sprintf("%s/%s/1", blk.Header().ID(), transaction.ID()),
@@ -353,6 +383,87 @@ func testHandleTraceCallWithMalfomredRevision(t *testing.T) {
 	assert.Equal(t, "revision: block number out of max uint32", strings.TrimSpace(res))
 }
 
+func testHandleTraceCallWithClauses(t *testing.T) {
+	addr := randAddress()
+	method, _ := builtin.Energy.ABI.MethodByName("totalSupply")
+	input, err := method.EncodeInput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clauseIndex := uint32(1)
+	traceCallOption := &TraceCallOption{
+		Clauses: []*Clause{
+			{To: &addr},
+			{To: &builtin.Energy.Address, Data: hexutil.Encode(input)},
+		},
+		ClauseIndex: &clauseIndex,
+		PerClause:   true,
+		Name:        "callTracer",
+	}
+
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call", traceCallOption, 200)
+
+	var parsedRes struct {
+		Result  map[string]interface{} `json:"result"`
+		GasUsed []uint64               `json:"gasUsed"`
+	}
+	if err := json.Unmarshal([]byte(res), &parsedRes); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, builtin.Energy.Address.String(), parsedRes.Result["to"])
+	assert.Len(t, parsedRes.GasUsed, 2)
+	assert.Equal(t, uint64(0), parsedRes.GasUsed[0])
+	assert.True(t, parsedRes.GasUsed[1] > 0)
+
+	// without perClause, only the trace result is returned
+	traceCallOption.PerClause = false
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call", traceCallOption, 200)
+
+	var parsedTrace map[string]interface{}
+	if err := json.Unmarshal([]byte(res), &parsedTrace); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, builtin.Energy.Address.String(), parsedTrace["to"])
+}
+
+func testHandleTraceCallWithBadClauses(t *testing.T) {
+	addr := randAddress()
+
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call", &TraceCallOption{
+		To:      &addr,
+		Clauses: []*Clause{{To: &addr}},
+	}, 400)
+	assert.Equal(t, "clauses: can not be used together with to, value or data", strings.TrimSpace(res))
+
+	clauseIndex := uint32(1)
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call", &TraceCallOption{
+		Clauses:     []*Clause{{To: &addr}},
+		ClauseIndex: &clauseIndex,
+	}, 400)
+	assert.Equal(t, "clauseIndex: out of range", strings.TrimSpace(res))
+
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call", &TraceCallOption{
+		Clauses: []*Clause{{To: &addr, Data: "0xzz"}},
+	}, 400)
+	assert.True(t, strings.HasPrefix(strings.TrimSpace(res), "clauses[0].data"))
+
+	// the traced clause is never reached since the first one runs out of gas
+	method, _ := builtin.Energy.ABI.MethodByName("totalSupply")
+	input, err := method.EncodeInput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call", &TraceCallOption{
+		Clauses: []*Clause{
+			{To: &builtin.Energy.Address, Data: hexutil.Encode(input)},
+			{To: &addr},
+		},
+		ClauseIndex: &clauseIndex,
+		Gas:         1,
+	}, 400)
+	assert.Equal(t, "clauseIndex: unreachable, clauses[0] reverted", strings.TrimSpace(res))
+}
+
 func testHandleTraceCallWithInsufficientGas(t *testing.T) {
 	addr := randAddress()
 	traceCallOption := &TraceCallOption{
@@ -441,6 +552,135 @@ func testStorageRange(t *testing.T) {
 	assert.Equal(t, expectedStorageRangeResult, parsedExecutionRes)
 }
 
+func testSimulateBundleWithBadBody(t *testing.T) {
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/simulateBundle", &SimulateBundleOption{}, 400)
+	assert.Equal(t, "transactions: empty", strings.TrimSpace(res))
+
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/debug/simulateBundle", &SimulateBundleOption{Transactions: []string{"0xzz"}}, 400)
+	assert.Equal(t, "transactions[0]: invalid hex string", strings.TrimSpace(res))
+
+	opt := &SimulateBundleOption{Transactions: []string{encodeBundleTx(t, newBundleTx(t, 1, big.NewInt(1)))}}
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/debug/simulateBundle?revision=badRevision", opt, 400)
+	assert.Equal(t, "revision: strconv.ParseUint: parsing \"badRevision\": invalid syntax", strings.TrimSpace(res))
+
+	gasLimit := uint64(21001)
+	opt.GasLimit = &gasLimit
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/debug/simulateBundle", opt, 403)
+	assert.Equal(t, "gas: exceeds limit", strings.TrimSpace(res))
+
+	opt = &SimulateBundleOption{Transactions: make([]string, maxBundleTxs+1)}
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/debug/simulateBundle", opt, 403)
+	assert.Equal(t, "transactions: exceeds limit of 64", strings.TrimSpace(res))
+}
+
+func testSimulateBundle(t *testing.T) {
+	to := thor.BytesToAddress([]byte("to"))
+	balance := func() *big.Int {
+		b, err := debug.stater.NewState(blk.Header().StateRoot(), blk.Header().Number(), 0, 0).GetBalance(to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	before := balance()
+
+	sign := func(b *tx.Builder) *tx.Transaction {
+		trx := b.Build()
+		sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return trx.WithSignature(sig)
+	}
+	chainTag := debug.repo.ChainTag()
+
+	txs := tx.Transactions{
+		newBundleTx(t, 1, big.NewInt(1)),
+		newBundleTx(t, 2, new(big.Int).Lsh(big.NewInt(1), 128)), // reverted, insufficient balance
+		sign(new(tx.Builder).ChainTag(0xff).Expiration(10).Gas(21000).Nonce(4)),
+		sign(new(tx.Builder).ChainTag(chainTag).BlockRef(tx.NewBlockRef(100)).Expiration(10).Gas(21000).Nonce(5)),
+		sign(new(tx.Builder).ChainTag(chainTag).BlockRef(tx.NewBlockRef(0)).Expiration(1).Gas(21000).Nonce(6)),
+		sign(new(tx.Builder).ChainTag(chainTag).Expiration(10).Gas(math.MaxUint64 - 1000).Nonce(7)), // overflows if summed up
+		newBundleTx(t, 3, big.NewInt(2)),
+	}
+	opt := &SimulateBundleOption{}
+	for _, trx := range txs {
+		opt.Transactions = append(opt.Transactions, encodeBundleTx(t, trx))
+	}
+
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/simulateBundle", opt, 200)
+	var result SimulateBundleResult
+	if err := json.Unmarshal([]byte(res), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.False(t, result.Aborted)
+	assert.NotNil(t, result.StateRoot)
+	assert.NotEqual(t, blk.Header().StateRoot(), *result.StateRoot)
+	assert.Equal(t, 7, len(result.Results))
+	for i, r := range result.Results {
+		assert.Equal(t, txs[i].ID(), r.TxID)
+	}
+	assert.False(t, result.Results[0].Receipt.Reverted)
+	assert.Equal(t, 1, len(result.Results[0].Receipt.Outputs[0].Transfers))
+	assert.True(t, result.Results[1].Receipt.Reverted)
+	for i, want := range []string{"chain tag mismatch", "block ref out of schedule", "expired", "gas limit reached"} {
+		assert.Nil(t, result.Results[2+i].Receipt)
+		assert.Equal(t, want, result.Results[2+i].Error)
+	}
+	assert.False(t, result.Results[6].Receipt.Reverted)
+
+	// nothing committed
+	assert.Equal(t, before, balance())
+}
+
+func testSimulateBundleAtomic(t *testing.T) {
+	opt := &SimulateBundleOption{
+		Atomic: true,
+		Transactions: []string{
+			encodeBundleTx(t, newBundleTx(t, 1, big.NewInt(1))),
+			encodeBundleTx(t, newBundleTx(t, 2, new(big.Int).Lsh(big.NewInt(1), 128))),
+			encodeBundleTx(t, newBundleTx(t, 3, big.NewInt(2))),
+		},
+	}
+
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/simulateBundle", opt, 200)
+	var result SimulateBundleResult
+	if err := json.Unmarshal([]byte(res), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, result.Aborted)
+	assert.Nil(t, result.StateRoot)
+	assert.Equal(t, 2, len(result.Results))
+	assert.True(t, result.Results[1].Receipt.Reverted)
+}
+
+func newBundleTx(t *testing.T, nonce uint64, value *big.Int) *tx.Transaction {
+	to := thor.BytesToAddress([]byte("to"))
+	trx := new(tx.Builder).
+		ChainTag(debug.repo.ChainTag()).
+		Expiration(100).
+		Gas(21000).
+		Nonce(nonce).
+		Clause(tx.NewClause(&to).WithValue(value)).
+		BlockRef(tx.NewBlockRef(0)).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[1].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trx.WithSignature(sig)
+}
+
+func encodeBundleTx(t *testing.T, trx *tx.Transaction) string {
+	data, err := rlp.EncodeToBytes(trx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hexutil.Encode(data)
+}
+
 func initDebugServer(t *testing.T) {
 	db := muxdb.NewMem()
 	stater := state.NewStater(db)
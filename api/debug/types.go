@@ -9,7 +9,9 @@ import (
 	"encoding/json"
 
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/v2/api/transactions"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
 )
 
 type TraceClauseOption struct {
@@ -31,6 +33,67 @@ type TraceCallOption struct {
 	BlockRef   string                `json:"blockRef"`
 	Name       string                `json:"name"`   // Tracer
 	Config     json.RawMessage       `json:"config"` // Config specific to given tracer.
+	// Clauses to be executed in sequence, can not be used together with To, Value and Data.
+	Clauses     []*Clause `json:"clauses"`
+	ClauseIndex *uint32   `json:"clauseIndex"` // index of the clause to trace, defaults to 0
+	PerClause   bool      `json:"perClause"`   // also return the gas used by each executed clause
+}
+
+// Clause is a clause of the multi-clause call to trace.
+type Clause struct {
+	To    *thor.Address         `json:"to"`
+	Value *math.HexOrDecimal256 `json:"value"`
+	Data  string                `json:"data"`
+}
+
+// PerClauseTraceResult is the trace result with the gas used by each executed clause.
+type PerClauseTraceResult struct {
+	Result  interface{} `json:"result"`
+	GasUsed []uint64    `json:"gasUsed"`
+}
+
+// SimulateBundleOption is the bundle of raw transactions to be executed in sequence on top of the revision block.
+type SimulateBundleOption struct {
+	Transactions []string      `json:"transactions"` // hex encoded raw transactions
+	Atomic       bool          `json:"atomic"`       // abort the bundle once a transaction reverted or failed
+	Beneficiary  *thor.Address `json:"beneficiary"`  // defaults to the beneficiary of the revision block
+	Timestamp    *uint64       `json:"timestamp"`    // defaults to the revision block timestamp plus block interval
+	GasLimit     *uint64       `json:"gasLimit"`     // defaults to the gas limit of the revision block
+}
+
+// SimulateBundleResult is the result of a simulated bundle.
+type SimulateBundleResult struct {
+	Results   []*BundleTxResult `json:"results"`
+	StateRoot *thor.Bytes32     `json:"stateRoot"` // nil if the bundle is aborted
+	Aborted   bool              `json:"aborted"`
+}
+
+// BundleTxResult is the execution result of a transaction in the bundle.
+type BundleTxResult struct {
+	TxID    thor.Bytes32   `json:"txID"`
+	Receipt *BundleReceipt `json:"receipt"` // nil if the transaction failed to execute
+	Error   string         `json:"error"`   // reason why the transaction failed to execute
+}
+
+// BundleReceipt is the receipt of a simulated transaction, which has no block meta.
+type BundleReceipt struct {
+	GasUsed  uint64                 `json:"gasUsed"`
+	GasPayer thor.Address           `json:"gasPayer"`
+	Paid     *math.HexOrDecimal256  `json:"paid"`
+	Reward   *math.HexOrDecimal256  `json:"reward"`
+	Reverted bool                   `json:"reverted"`
+	Outputs  []*transactions.Output `json:"outputs"`
+}
+
+func convertBundleReceipt(receipt *tx.Receipt, trx *tx.Transaction) *BundleReceipt {
+	return &BundleReceipt{
+		GasUsed:  receipt.GasUsed,
+		GasPayer: receipt.GasPayer,
+		Paid:     (*math.HexOrDecimal256)(receipt.Paid),
+		Reward:   (*math.HexOrDecimal256)(receipt.Reward),
+		Reverted: receipt.Reverted,
+		Outputs:  transactions.ConvertOutputs(receipt, trx),
+	}
 }
 
 type StorageRangeOption struct {
@@ -0,0 +1,105 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package events
+
+import (
+	"bufio"
+	"encoding/binary"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// BinaryContentType is the media type of the binary events format, requested by the Accept header.
+//
+// The format is a leading format byte, followed by events each prefixed with its length:
+//
+//	stream = format-byte event*
+//	event  = length(uint32, big-endian) rlp(BinaryEvent)
+//
+// BinaryFormatV1 is the only format so far. New formats come with new format bytes, so that decoders
+// can reject the ones they don't know.
+const BinaryContentType = "application/x-thor-events"
+
+// BinaryFormatV1 is the format byte of the events encoded as BinaryEvent.
+const BinaryFormatV1 byte = 1
+
+// BinaryEvent is the event in binary format v1. It carries the same fields as FilteredEvent,
+// with topics and data in raw bytes.
+type BinaryEvent struct {
+	Address        thor.Address
+	Topics         []thor.Bytes32
+	Data           []byte
+	BlockID        thor.Bytes32
+	BlockNumber    uint32
+	BlockTimestamp uint64
+	TxID           thor.Bytes32
+	TxOrigin       thor.Address
+	ClauseIndex    uint32
+}
+
+// acceptsBinary returns whether the binary format is accepted by the Accept header.
+// JSON is preferred if both accepted, as the default.
+func acceptsBinary(accept string) bool {
+	var binary bool
+	for _, v := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "application/json", "application/*", "*/*":
+			return false
+		case BinaryContentType:
+			binary = true
+		}
+	}
+	return binary
+}
+
+func writeEventsBinary(w http.ResponseWriter, events []*logdb.Event) error {
+	w.Header().Set("Content-Type", BinaryContentType)
+
+	bw := bufio.NewWriter(w)
+	if err := bw.WriteByte(BinaryFormatV1); err != nil {
+		return err
+	}
+	var prefix [4]byte
+	for _, e := range events {
+		be := BinaryEvent{
+			Address:        e.Address,
+			Topics:         make([]thor.Bytes32, 0, len(e.Topics)),
+			Data:           e.Data,
+			BlockID:        e.BlockID,
+			BlockNumber:    e.BlockNumber,
+			BlockTimestamp: e.BlockTime,
+			TxID:           e.TxID,
+			TxOrigin:       e.TxOrigin,
+			ClauseIndex:    e.ClauseIndex,
+		}
+		for _, topic := range e.Topics {
+			if topic != nil {
+				be.Topics = append(be.Topics, *topic)
+			}
+		}
+		data, err := rlp.EncodeToBytes(&be)
+		if err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint32(prefix[:], uint32(len(data)))
+		if _, err := bw.Write(prefix[:]); err != nil {
+			return err
+		}
+		if _, err := bw.Write(data); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
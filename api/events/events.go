@@ -31,7 +31,7 @@ func New(repo *chain.Repository, db *logdb.LogDB, logsLimit uint64) *Events {
 }
 
 // Filter query events with option
-func (e *Events) filter(ctx context.Context, ef *EventFilter) ([]*FilteredEvent, error) {
+func (e *Events) filter(ctx context.Context, ef *EventFilter) ([]*logdb.Event, error) {
 	chain := e.repo.NewBestChain()
 	filter, err := convertEventFilter(chain, ef)
 	if err != nil {
@@ -39,13 +39,12 @@ func (e *Events) filter(ctx context.Context, ef *EventFilter) ([]*FilteredEvent,
 	}
 	events, err := e.db.FilterEvents(ctx, filter)
 	if err != nil {
+		if err == logdb.ErrQueryQueueTimeout {
+			return nil, utils.HTTPError(err, http.StatusServiceUnavailable)
+		}
 		return nil, err
 	}
-	fes := make([]*FilteredEvent, len(events))
-	for i, e := range events {
-		fes[i] = convertEvent(e)
-	}
-	return fes, nil
+	return events, nil
 }
 
 func (e *Events) handleFilter(w http.ResponseWriter, req *http.Request) error {
@@ -63,10 +62,23 @@ func (e *Events) handleFilter(w http.ResponseWriter, req *http.Request) error {
 		}
 	}
 
-	fes, err := e.filter(req.Context(), &filter)
+	events, err := e.filter(req.Context(), &filter)
 	if err != nil {
 		return err
 	}
+	// the cursor of the last event is returned for keyset pagination
+	if len(events) > 0 {
+		w.Header().Set(utils.LogsCursorHeader, events[len(events)-1].Cursor().String())
+	}
+
+	w.Header().Add("Vary", "Accept")
+	if acceptsBinary(req.Header.Get("Accept")) {
+		return writeEventsBinary(w, events)
+	}
+	fes := make([]*FilteredEvent, len(events))
+	for i, e := range events {
+		fes[i] = convertEvent(e)
+	}
 	return utils.WriteJSON(w, fes)
 }
 
@@ -7,6 +7,8 @@ package events_test
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -14,6 +16,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/api/events"
@@ -59,6 +62,74 @@ func TestEvents(t *testing.T) {
 	testEventWithBlocks(t, blocksToInsert)
 }
 
+func TestEventsBinary(t *testing.T) {
+	db := createDb(t)
+	initEventServer(t, db, defaultLogLimit)
+	defer ts.Close()
+	insertBlocks(t, db, 3)
+
+	post := func(accept string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/events", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept", accept)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	// json preferred if both accepted
+	res := post("application/json, " + events.BinaryContentType)
+	res.Body.Close()
+	assert.Contains(t, res.Header.Get("Content-Type"), "application/json")
+
+	res = post(events.BinaryContentType)
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, events.BinaryContentType, res.Header.Get("Content-Type"))
+	assert.NotEmpty(t, res.Header.Get("x-logs-cursor"))
+
+	var jsonEvents []*events.FilteredEvent
+	res2, _ := httpPost(t, ts.URL+"/events", events.EventFilter{})
+	if err := json.Unmarshal(res2, &jsonEvents); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, events.BinaryFormatV1, body[0])
+	body = body[1:]
+	var binEvents []*events.BinaryEvent
+	for len(body) > 0 {
+		n := binary.BigEndian.Uint32(body)
+		var be events.BinaryEvent
+		if err := rlp.DecodeBytes(body[4:4+n], &be); err != nil {
+			t.Fatal(err)
+		}
+		binEvents = append(binEvents, &be)
+		body = body[4+n:]
+	}
+
+	assert.Equal(t, len(jsonEvents), len(binEvents))
+	for i, be := range binEvents {
+		je := jsonEvents[i]
+		assert.Equal(t, je.Address, be.Address)
+		assert.Equal(t, len(je.Topics), len(be.Topics))
+		for j, topic := range be.Topics {
+			assert.Equal(t, *je.Topics[j], topic)
+		}
+		assert.Equal(t, je.Data, "0x"+hex.EncodeToString(be.Data))
+		assert.Equal(t, je.Meta.BlockID, be.BlockID)
+		assert.Equal(t, je.Meta.BlockNumber, be.BlockNumber)
+		assert.Equal(t, je.Meta.TxID, be.TxID)
+	}
+}
+
 func TestOption(t *testing.T) {
 	db := createDb(t)
 	initEventServer(t, db, 5)
@@ -0,0 +1,93 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+)
+
+// Health serves the liveness and readiness checks. The API is served only after the log db
+// synced with the chain, so the readiness check doesn't need to take care of it.
+type Health struct {
+	repo   *chain.Repository
+	nw     Network
+	maxLag uint32
+}
+
+// New creates the health checks. The node is ready when no peer is ahead of the local best block
+// by more than maxLag blocks.
+func New(repo *chain.Repository, nw Network, maxLag uint32) *Health {
+	return &Health{
+		repo,
+		nw,
+		maxLag,
+	}
+}
+
+// Readiness returns whether the node is ready to serve.
+func (h *Health) Readiness() *Readiness {
+	r := &Readiness{
+		BestBlock: h.repo.BestBlockSummary().Header.Number(),
+	}
+	r.NetworkBestBlock = r.BestBlock
+
+	stats := h.nw.PeersStats()
+	r.Peers = len(stats)
+	for _, s := range stats {
+		if num := block.Number(s.BestBlockID); num > r.NetworkBestBlock {
+			r.NetworkBestBlock = num
+		}
+	}
+
+	if sr, ok := h.nw.(SyncReporter); ok {
+		select {
+		case <-sr.Synced():
+		default:
+			r.Reason = "initial synchronization in progress"
+			return r
+		}
+	}
+	if lag := r.NetworkBestBlock - r.BestBlock; lag > h.maxLag {
+		r.Reason = fmt.Sprintf("%v blocks behind the network", lag)
+		return r
+	}
+	r.Ready = true
+	return r
+}
+
+func (h *Health) handleLive(w http.ResponseWriter, _ *http.Request) error {
+	return utils.WriteJSON(w, utils.M{"live": true})
+}
+
+func (h *Health) handleReady(w http.ResponseWriter, _ *http.Request) error {
+	r := h.Readiness()
+	if r.Ready {
+		return utils.WriteJSON(w, r)
+	}
+	w.Header().Set("Content-Type", utils.JSONContentType)
+	w.WriteHeader(http.StatusServiceUnavailable)
+	return json.NewEncoder(w).Encode(r)
+}
+
+func (h *Health) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("/live").
+		Methods(http.MethodGet).
+		Name("health_get_live").
+		HandlerFunc(utils.WrapHandlerFunc(h.handleLive))
+	sub.Path("/ready").
+		Methods(http.MethodGet).
+		Name("health_get_ready").
+		HandlerFunc(utils.WrapHandlerFunc(h.handleReady))
+}
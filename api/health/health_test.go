@@ -0,0 +1,143 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package health_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/api/health"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/comm"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+)
+
+type mockNetwork []*comm.PeerStats
+
+func (m mockNetwork) PeersStats() []*comm.PeerStats { return m }
+
+type mockSyncingNetwork struct {
+	mockNetwork
+	synced chan struct{}
+}
+
+func (m mockSyncingNetwork) Synced() <-chan struct{} { return m.synced }
+
+func peerAt(num uint32) *comm.PeerStats {
+	var id thor.Bytes32
+	binary.BigEndian.PutUint32(id[:], num)
+	return &comm.PeerStats{BestBlockID: id}
+}
+
+func newRepo(t *testing.T) *chain.Repository {
+	db := muxdb.NewMem()
+	b, _, _, err := genesis.NewDevnet().Build(state.NewStater(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := chain.NewRepository(db, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func serve(repo *chain.Repository, nw health.Network, maxLag uint32) *httptest.Server {
+	router := mux.NewRouter()
+	health.New(repo, nw, maxLag).Mount(router, "/health")
+	return httptest.NewServer(router)
+}
+
+func httpGet(t *testing.T, url string) ([]byte, int) {
+	res, err := http.Get(url) // nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	r, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, res.StatusCode
+}
+
+func TestLive(t *testing.T) {
+	ts := serve(newRepo(t), mockNetwork{}, 0)
+	defer ts.Close()
+
+	body, code := httpGet(t, ts.URL+"/health/live")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"live":true}`, string(body))
+}
+
+func TestReady(t *testing.T) {
+	repo := newRepo(t)
+	synced := make(chan struct{})
+	close(synced)
+
+	tests := []struct {
+		name   string
+		nw     health.Network
+		status int
+		want   health.Readiness
+	}{
+		{
+			"no peers",
+			mockNetwork{},
+			http.StatusOK,
+			health.Readiness{Ready: true},
+		},
+		{
+			"within lag",
+			mockNetwork{peerAt(3), peerAt(6)},
+			http.StatusOK,
+			health.Readiness{Ready: true, NetworkBestBlock: 6, Peers: 2},
+		},
+		{
+			"lagging",
+			mockNetwork{peerAt(7), peerAt(2)},
+			http.StatusServiceUnavailable,
+			health.Readiness{Reason: "7 blocks behind the network", NetworkBestBlock: 7, Peers: 2},
+		},
+		{
+			"syncing",
+			mockSyncingNetwork{mockNetwork{peerAt(1)}, make(chan struct{})},
+			http.StatusServiceUnavailable,
+			health.Readiness{Reason: "initial synchronization in progress", NetworkBestBlock: 1, Peers: 1},
+		},
+		{
+			"synced",
+			mockSyncingNetwork{mockNetwork{peerAt(1)}, synced},
+			http.StatusOK,
+			health.Readiness{Ready: true, NetworkBestBlock: 1, Peers: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := serve(repo, tt.nw, 6)
+			defer ts.Close()
+
+			body, code := httpGet(t, ts.URL+"/health/ready")
+			assert.Equal(t, tt.status, code)
+
+			var got health.Readiness
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
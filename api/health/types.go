@@ -0,0 +1,30 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package health
+
+import (
+	"github.com/vechain/thor/v2/comm"
+)
+
+// Network is the p2p network to learn the network head from.
+type Network interface {
+	PeersStats() []*comm.PeerStats
+}
+
+// SyncReporter is optionally implemented by the Network, to report whether the initial
+// synchronization passed. Networks without it, like solo, are considered synced.
+type SyncReporter interface {
+	Synced() <-chan struct{}
+}
+
+// Readiness is the response of the readiness check.
+type Readiness struct {
+	Ready            bool   `json:"ready"`
+	Reason           string `json:"reason,omitempty"`
+	BestBlock        uint32 `json:"bestBlock"`
+	NetworkBestBlock uint32 `json:"networkBestBlock"`
+	Peers            int    `json:"peers"`
+}
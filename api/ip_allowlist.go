@@ -0,0 +1,48 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// loopbackNets are the IPv4 and IPv6 loopback networks.
+var loopbackNets = []*net.IPNet{
+	{IP: net.IPv4(127, 0, 0, 0), Mask: net.CIDRMask(8, 32)},
+	{IP: net.IPv6loopback, Mask: net.CIDRMask(128, 128)},
+}
+
+// IPAllowlistHandler returns a http handler which rejects requests to paths under pathPrefix with 403 Forbidden,
+// unless the remote address is in the allowlist. 'X-Forwarded-For' header is never trusted, even from trusted
+// proxies, since the allowlist guards admin endpoints.
+func IPAllowlistHandler(handler http.Handler, pathPrefix string, allowlist []*net.IPNet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (r.URL.Path == pathPrefix || strings.HasPrefix(r.URL.Path, pathPrefix+"/")) && !ipAllowed(r.RemoteAddr, allowlist) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func ipAllowed(remoteAddr string, allowlist []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range allowlist {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
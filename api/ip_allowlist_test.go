@@ -0,0 +1,56 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPAllowlistHandler(t *testing.T) {
+	_, local, _ := net.ParseCIDR("127.0.0.0/8")
+	_, private, _ := net.ParseCIDR("10.0.0.0/8")
+	handler := IPAllowlistHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), "/debug", []*net.IPNet{local, private})
+
+	tests := []struct {
+		path       string
+		remoteAddr string
+		xff        string
+		want       int
+	}{
+		{"/debug/tracers", "127.0.0.1:1234", "", http.StatusOK},
+		{"/debug/tracers", "10.1.2.3:1234", "", http.StatusOK},
+		{"/debug/tracers", "1.1.1.1:1234", "", http.StatusForbidden},
+		{"/debug/pprof/", "1.1.1.1:1234", "", http.StatusForbidden},
+		{"/debug", "1.1.1.1:1234", "", http.StatusForbidden},
+		{"/debug/tracers", "1.1.1.1:1234", "127.0.0.1", http.StatusForbidden}, // forwarded header not trusted
+		{"/debugger", "1.1.1.1:1234", "", http.StatusOK},
+		{"/blocks/best", "1.1.1.1:1234", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.RemoteAddr = tt.remoteAddr
+		if tt.xff != "" {
+			req.Header.Set("X-Forwarded-For", tt.xff)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, "%v from %v", tt.path, tt.remoteAddr)
+	}
+}
+
+func TestIPAllowlistLoopback(t *testing.T) {
+	assert.True(t, ipAllowed("127.0.0.1:1234", loopbackNets))
+	assert.True(t, ipAllowed("[::1]:1234", loopbackNets))
+	assert.False(t, ipAllowed("10.1.2.3:1234", loopbackNets))
+	assert.False(t, ipAllowed("[2001:db8::1]:1234", loopbackNets))
+}
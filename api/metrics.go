@@ -62,10 +62,13 @@ func metricsMiddleware(next http.Handler) http.Handler {
 			name    = ""
 		)
 
-		// all named route will be recorded
-		if rt != nil && rt.GetName() != "" {
-			enabled = true
-			name = rt.GetName()
+		// all matched routes will be recorded, labeled by the route name, or the path template if unnamed,
+		// so that path variables are collapsed into one label
+		if rt != nil {
+			if name = rt.GetName(); name == "" {
+				name, _ = rt.GetPathTemplate()
+			}
+			enabled = name != ""
 		}
 
 		now := time.Now()
@@ -53,7 +53,7 @@ func TestMetricsMiddleware(t *testing.T) {
 	assert.NotNil(t, err)
 
 	router := mux.NewRouter()
-	acc := accounts.New(repo, stater, math.MaxUint64, thor.NoFork, solo.NewBFTEngine(repo))
+	acc := accounts.New(repo, stater, math.MaxUint64, thor.NoFork, solo.NewBFTEngine(repo), 3)
 	acc.Mount(router, "/accounts")
 	router.PathPrefix("/metrics").Handler(metrics.HTTPHandler())
 	router.Use(metricsMiddleware)
@@ -103,6 +103,33 @@ func TestMetricsMiddleware(t *testing.T) {
 	assert.Equal(t, "accounts_get_account", labels[2].GetValue())
 }
 
+func TestMetricsMiddlewareUnnamedRoute(t *testing.T) {
+	router := mux.NewRouter()
+	router.Path("/items/{id}").HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {})
+	router.PathPrefix("/metrics").Handler(metrics.HTTPHandler())
+	router.Use(metricsMiddleware)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	httpGet(t, ts.URL+"/items/1")
+	httpGet(t, ts.URL+"/items/2")
+
+	body, _ := httpGet(t, ts.URL+"/metrics")
+	parser := expfmt.TextParser{}
+	metrics, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	assert.Nil(t, err)
+
+	var count float64
+	for _, m := range metrics["thor_metrics_api_request_count"].GetMetric() {
+		for _, l := range m.GetLabel() {
+			if l.GetName() == "name" && l.GetValue() == "/items/{id}" {
+				count += m.GetCounter().GetValue()
+			}
+		}
+	}
+	assert.Equal(t, float64(2), count, "path variables should be collapsed into one label")
+}
+
 func httpGet(t *testing.T, url string) ([]byte, int) {
 	res, err := http.Get(url) // nolint:gosec
 	if err != nil {
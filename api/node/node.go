@@ -7,18 +7,55 @@ package node
 
 import (
 	"net/http"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/p2psrv"
+	"github.com/vechain/thor/v2/poa"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/txpool"
 )
 
+// maxScheduleRounds is the max rounds of the proposer schedule to be returned.
+const maxScheduleRounds = 10
+
 type Node struct {
-	nw Network
+	repo          *chain.Repository
+	stater        *state.Stater
+	txPool        *txpool.TxPool
+	seeder        *poa.Seeder
+	nw            Network
+	blockInterval uint64
+	forkConfig    thor.ForkConfig
+	production    Production
 }
 
-func New(nw Network) *Node {
+// New returns the node API. production is nil if the node doesn't produce blocks.
+func New(
+	repo *chain.Repository,
+	stater *state.Stater,
+	txPool *txpool.TxPool,
+	nw Network,
+	blockInterval uint64,
+	forkConfig thor.ForkConfig,
+	production Production,
+) *Node {
 	return &Node{
+		repo,
+		stater,
+		txPool,
+		poa.NewSeeder(repo),
 		nw,
+		blockInterval,
+		forkConfig,
+		production,
 	}
 }
 
@@ -26,8 +63,181 @@ func (n *Node) PeersStats() []*PeerStats {
 	return ConvertPeersStats(n.nw.PeersStats())
 }
 
+func (n *Node) handleBootnodes(w http.ResponseWriter, _ *http.Request) error {
+	var health []*p2psrv.NodeHealth
+	if r, ok := n.nw.(BootnodesReporter); ok {
+		health = r.BootstrapNodesHealth()
+	}
+	return utils.WriteJSON(w, ConvertBootnodesHealth(health))
+}
+
 func (n *Node) handleNetwork(w http.ResponseWriter, req *http.Request) error {
-	return utils.WriteJSON(w, n.PeersStats())
+	var filter func(*PeerStats) bool
+	switch direction := req.URL.Query().Get("direction"); direction {
+	case "", "all":
+	case "inbound":
+		filter = func(s *PeerStats) bool { return s.Inbound }
+	case "outbound":
+		filter = func(s *PeerStats) bool { return !s.Inbound }
+	default:
+		return utils.BadRequest(errors.New("direction: should be one of inbound|outbound|all"))
+	}
+
+	stats := n.PeersStats()
+	if filter != nil {
+		filtered := make([]*PeerStats, 0, len(stats))
+		for _, s := range stats {
+			if filter(s) {
+				filtered = append(filtered, s)
+			}
+		}
+		stats = filtered
+	}
+	return utils.WriteJSON(w, stats)
+}
+
+func (n *Node) handleConfig(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, &Config{
+		BlockInterval: n.blockInterval,
+		ForkConfig:    n.forkConfig,
+	})
+}
+
+func (n *Node) handleGetProduction(w http.ResponseWriter, _ *http.Request) error {
+	enabled := n.production.Enabled()
+	return utils.WriteJSON(w, &ProductionState{Enabled: &enabled})
+}
+
+func (n *Node) handleSetProduction(w http.ResponseWriter, req *http.Request) error {
+	var state ProductionState
+	if err := utils.ParseJSON(req.Body, &state); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if state.Enabled == nil {
+		return utils.BadRequest(errors.New("enabled: required"))
+	}
+	n.production.SetEnabled(*state.Enabled)
+	return n.handleGetProduction(w, req)
+}
+
+func (n *Node) handleTxPool(w http.ResponseWriter, req *http.Request) error {
+	var origin *thor.Address
+	if s := req.URL.Query().Get("origin"); s != "" {
+		addr, err := thor.ParseAddress(s)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "origin"))
+		}
+		origin = &addr
+	}
+	return utils.WriteJSON(w, ConvertTxPoolContent(n.txPool.Content(origin), time.Now()))
+}
+
+func (n *Node) handleSchedule(w http.ResponseWriter, req *http.Request) error {
+	nowTime := uint64(time.Now().Unix())
+	if s := req.URL.Query().Get("time"); s != "" {
+		t, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "time"))
+		}
+		nowTime = t
+	}
+	rounds := uint64(1)
+	if s := req.URL.Query().Get("rounds"); s != "" {
+		r, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "rounds"))
+		}
+		if r == 0 || r > maxScheduleRounds {
+			return utils.BadRequest(errors.Errorf("rounds: should be in [1, %v]", maxScheduleRounds))
+		}
+		rounds = r
+	}
+
+	schedule, err := n.schedule(n.repo.BestBlockSummary(), nowTime, rounds)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, schedule)
+}
+
+// schedule computes block slots of proposers upon the parent block, from nowTime for the given rounds.
+// A round lasts as many slots as active proposers. Each proposer is scheduled as the consensus does, so an
+// absentee gets the slots it would take if it brings itself back, which shift those of the others.
+func (n *Node) schedule(parent *chain.BlockSummary, nowTime uint64, rounds uint64) (*Schedule, error) {
+	st := n.stater.NewState(parent.Header.StateRoot(), parent.Header.Number(), parent.Conflicts, parent.SteadyNum)
+
+	params := builtin.Params.Native(st)
+	endorsement, err := params.Get(thor.KeyProposerEndorsement)
+	if err != nil {
+		return nil, err
+	}
+	mbp, err := params.Get(thor.KeyMaxBlockProposers)
+	if err != nil {
+		return nil, err
+	}
+	maxBlockProposers := mbp.Uint64()
+	if maxBlockProposers == 0 || maxBlockProposers > thor.InitialMaxBlockProposers {
+		maxBlockProposers = thor.InitialMaxBlockProposers
+	}
+	candidates, err := builtin.Authority.Native(st).Candidates(endorsement, maxBlockProposers)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		proposers = make([]poa.Proposer, 0, len(candidates))
+		actives   uint64
+	)
+	for _, c := range candidates {
+		proposers = append(proposers, poa.Proposer{Address: c.NodeMaster, Active: c.Active})
+		if c.Active {
+			actives++
+		}
+	}
+
+	var seed []byte
+	v2 := parent.Header.Number()+1 >= n.forkConfig.VIP214
+	if v2 {
+		if seed, err = n.seeder.Generate(parent.Header.ID()); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		T     = thor.BlockInterval
+		start = parent.Header.Timestamp() + T
+	)
+	if nowTime > start {
+		start += (nowTime - start + T - 1) / T * T
+	}
+	end := start + rounds*max(actives, 1)*T
+
+	schedule := &Schedule{
+		ParentID: parent.Header.ID(),
+		Slots:    make([]*ScheduleSlot, 0),
+	}
+	for _, p := range proposers {
+		var sched poa.Scheduler
+		if v2 {
+			sched, err = poa.NewSchedulerV2(p.Address, proposers, parent.Header.Number(), parent.Header.Timestamp(), seed)
+		} else {
+			sched, err = poa.NewSchedulerV1(p.Address, proposers, parent.Header.Number(), parent.Header.Timestamp())
+		}
+		if err != nil {
+			return nil, err
+		}
+		for t := sched.Schedule(start); t < end; t = sched.Schedule(t + 1) {
+			schedule.Slots = append(schedule.Slots, &ScheduleSlot{
+				Proposer:  p.Address,
+				Timestamp: t,
+				Absentee:  !p.Active,
+			})
+		}
+	}
+	sort.SliceStable(schedule.Slots, func(i, j int) bool {
+		return schedule.Slots[i].Timestamp < schedule.Slots[j].Timestamp
+	})
+	return schedule, nil
 }
 
 func (n *Node) Mount(root *mux.Router, pathPrefix string) {
@@ -37,4 +247,33 @@ func (n *Node) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodGet).
 		Name("node_get_peers").
 		HandlerFunc(utils.WrapHandlerFunc(n.handleNetwork))
+	sub.Path("/bootnodes").
+		Methods(http.MethodGet).
+		Name("node_get_bootnodes").
+		HandlerFunc(utils.WrapHandlerFunc(n.handleBootnodes))
+	sub.Path("/config").
+		Methods(http.MethodGet).
+		Name("node_get_config").
+		HandlerFunc(utils.WrapHandlerFunc(n.handleConfig))
+	sub.Path("/schedule").
+		Methods(http.MethodGet).
+		Name("node_get_schedule").
+		HandlerFunc(utils.WrapHandlerFunc(n.handleSchedule))
+
+	if n.txPool != nil {
+		sub.Path("/txpool").
+			Methods(http.MethodGet).
+			Name("node_get_txpool").
+			HandlerFunc(utils.WrapHandlerFunc(n.handleTxPool))
+	}
+	if n.production != nil {
+		sub.Path("/production").
+			Methods(http.MethodGet).
+			Name("node_get_production").
+			HandlerFunc(utils.WrapHandlerFunc(n.handleGetProduction))
+		sub.Path("/production").
+			Methods(http.MethodPost).
+			Name("node_set_production").
+			HandlerFunc(utils.WrapHandlerFunc(n.handleSetProduction))
+	}
 }
@@ -9,9 +9,12 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/p2p/discover"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/api/node"
@@ -19,7 +22,9 @@ import (
 	"github.com/vechain/thor/v2/comm"
 	"github.com/vechain/thor/v2/genesis"
 	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/p2psrv"
 	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/txpool"
 )
 
@@ -35,6 +40,199 @@ func TestNode(t *testing.T) {
 	assert.Equal(t, 0, len(peersStats), "count should be zero")
 }
 
+func TestNodeTxPool(t *testing.T) {
+	initCommServer(t)
+	defer ts.Close()
+
+	var content node.TxPoolContent
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/txpool"), &content); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, node.TxPoolContent{Pending: []*node.PooledTx{}, Queued: []*node.PooledTx{}}, content)
+
+	res, err := http.Get(ts.URL + "/node/txpool?origin=0x1") // nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+type mockNetwork []*comm.PeerStats
+
+func (m mockNetwork) PeersStats() []*comm.PeerStats { return m }
+
+func TestNodePeersDirection(t *testing.T) {
+	router := mux.NewRouter()
+	node.New(nil, nil, nil, mockNetwork{
+		{PeerID: "in", Inbound: true},
+		{PeerID: "out", Inbound: false},
+	}, thor.BlockInterval, thor.NoFork, nil).Mount(router, "/node")
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	tests := []struct {
+		direction string
+		want      []string
+	}{
+		{"", []string{"in", "out"}},
+		{"all", []string{"in", "out"}},
+		{"inbound", []string{"in"}},
+		{"outbound", []string{"out"}},
+	}
+	for _, tt := range tests {
+		var peersStats []*node.PeerStats
+		if err := json.Unmarshal(httpGet(t, server.URL+"/node/network/peers?direction="+tt.direction), &peersStats); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, s := range peersStats {
+			got = append(got, s.PeerID)
+		}
+		assert.Equal(t, tt.want, got, tt.direction)
+	}
+
+	res, err := http.Get(server.URL + "/node/network/peers?direction=sideways") // nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestNodeConfig(t *testing.T) {
+	forkConfig := thor.NoFork
+	forkConfig.VIP191 = 1
+
+	router := mux.NewRouter()
+	node.New(nil, nil, nil, mockNetwork{}, 3, forkConfig, nil).Mount(router, "/node")
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	var config node.Config
+	if err := json.Unmarshal(httpGet(t, server.URL+"/node/config"), &config); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(3), config.BlockInterval)
+	assert.Equal(t, forkConfig, config.ForkConfig)
+}
+
+type mockBootnodesNetwork struct {
+	mockNetwork
+	health []*p2psrv.NodeHealth
+}
+
+func (m mockBootnodesNetwork) BootstrapNodesHealth() []*p2psrv.NodeHealth { return m.health }
+
+func TestNodeBootnodes(t *testing.T) {
+	bootnode := discover.MustParseNode("enode://1234cf28ab5f0255a3923ac094d0168ce884a9fa5f3998b1844986b4a2b1eac52fcccd8f2916be9b8b0f7798147ee5592ec3c83518925fac50f812577515d6ad@10.3.58.6:30303")
+	lastSuccess := time.Unix(1700000000, 0)
+
+	router := mux.NewRouter()
+	node.New(nil, nil, nil, mockBootnodesNetwork{health: []*p2psrv.NodeHealth{
+		{Node: bootnode, Connected: true, Successes: 2, Failures: 1, LastSuccess: lastSuccess, LastError: "i/o timeout"},
+	}}, thor.BlockInterval, thor.NoFork, nil).Mount(router, "/node")
+	node.New(nil, nil, nil, mockNetwork{}, thor.BlockInterval, thor.NoFork, nil).Mount(router, "/plain")
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	var stats []*node.BootnodeStats
+	if err := json.Unmarshal(httpGet(t, server.URL+"/node/bootnodes"), &stats); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*node.BootnodeStats{{
+		Enode:       bootnode.String(),
+		Connected:   true,
+		Successes:   2,
+		Failures:    1,
+		LastSuccess: uint64(lastSuccess.Unix()),
+		LastFailure: 0,
+		LastError:   "i/o timeout",
+	}}, stats)
+
+	// network without bootnodes reporting
+	assert.Equal(t, "[]", strings.TrimSpace(string(httpGet(t, server.URL+"/plain/bootnodes"))))
+}
+
+func TestNodeSchedule(t *testing.T) {
+	initCommServer(t)
+
+	var schedule node.Schedule
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/schedule?time=0&rounds=3"), &schedule); err != nil {
+		t.Fatal(err)
+	}
+	// the devnet has the only proposer
+	assert.Equal(t, 3, len(schedule.Slots))
+	genesisTime := schedule.Slots[0].Timestamp - thor.BlockInterval
+	for i, slot := range schedule.Slots {
+		assert.Equal(t, schedule.Slots[0].Proposer, slot.Proposer)
+		assert.Equal(t, genesisTime+uint64(i+1)*thor.BlockInterval, slot.Timestamp)
+		assert.False(t, slot.Absentee)
+	}
+
+	// slots start from the given time
+	nowTime := genesisTime + 100
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/schedule?time="+strconv.FormatUint(nowTime, 10)), &schedule); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(schedule.Slots))
+	assert.True(t, schedule.Slots[0].Timestamp >= nowTime)
+	assert.True(t, schedule.Slots[0].Timestamp < nowTime+thor.BlockInterval)
+
+	for _, query := range []string{"time=abc", "rounds=0", "rounds=100"} {
+		res, err := http.Get(ts.URL + "/node/schedule?" + query) // nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+	}
+}
+
+type mockProduction struct{ enabled bool }
+
+func (m *mockProduction) Enabled() bool           { return m.enabled }
+func (m *mockProduction) SetEnabled(enabled bool) { m.enabled = enabled }
+
+func TestNodeProduction(t *testing.T) {
+	router := mux.NewRouter()
+	production := &mockProduction{enabled: true}
+	node.New(nil, nil, nil, mockNetwork{}, thor.BlockInterval, thor.NoFork, production).Mount(router, "/node")
+	node.New(nil, nil, nil, mockNetwork{}, thor.BlockInterval, thor.NoFork, nil).Mount(router, "/plain")
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	post := func(url, body string) (int, string) {
+		res, err := http.Post(url, "application/json", strings.NewReader(body)) // nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		data, _ := io.ReadAll(res.Body)
+		return res.StatusCode, strings.TrimSpace(string(data))
+	}
+
+	assert.Equal(t, `{"enabled":true}`, strings.TrimSpace(string(httpGet(t, server.URL+"/node/production"))))
+
+	code, body := post(server.URL+"/node/production", `{"enabled":false}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"enabled":false}`, body)
+	assert.False(t, production.enabled)
+	assert.Equal(t, `{"enabled":false}`, strings.TrimSpace(string(httpGet(t, server.URL+"/node/production"))))
+
+	code, body = post(server.URL+"/node/production", `{}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "enabled: required", body)
+
+	code, _ = post(server.URL+"/node/production", `{"enabled":true}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, production.enabled)
+
+	// not mounted if the node doesn't produce blocks
+	code, _ = post(server.URL+"/plain/production", `{"enabled":true}`)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
 func initCommServer(t *testing.T) {
 	db := muxdb.NewMem()
 	stater := state.NewStater(db)
@@ -45,13 +243,14 @@ func initCommServer(t *testing.T) {
 		t.Fatal(err)
 	}
 	repo, _ := chain.NewRepository(db, b)
-	comm := comm.New(repo, txpool.New(repo, stater, txpool.Options{
+	pool := txpool.New(repo, stater, txpool.Options{
 		Limit:           10000,
 		LimitPerAccount: 16,
 		MaxLifetime:     10 * time.Minute,
-	}))
+	})
+	comm := comm.New(repo, pool)
 	router := mux.NewRouter()
-	node.New(comm).Mount(router, "/node")
+	node.New(repo, stater, pool, comm, thor.BlockInterval, thor.NoFork, nil).Mount(router, "/node")
 	ts = httptest.NewServer(router)
 }
 
@@ -6,14 +6,79 @@
 package node
 
 import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+
 	"github.com/vechain/thor/v2/comm"
+	"github.com/vechain/thor/v2/p2psrv"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/txpool"
 )
 
 type Network interface {
 	PeersStats() []*comm.PeerStats
 }
 
+// BootnodesReporter is optionally implemented by the Network, to report the health of bootnodes.
+type BootnodesReporter interface {
+	BootstrapNodesHealth() []*p2psrv.NodeHealth
+}
+
+// Production pauses and resumes the block production of the node.
+type Production interface {
+	Enabled() bool
+	SetEnabled(enabled bool)
+}
+
+// ProductionState is the state of the block production.
+type ProductionState struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// TxPoolContent is the txs in the pool. Pending ones are executable as of the last check of the pool,
+// and the others are queued, e.g. for unmet dependencies or future block refs.
+type TxPoolContent struct {
+	Pending []*PooledTx `json:"pending"`
+	Queued  []*PooledTx `json:"queued"`
+}
+
+// PooledTx is a tx in the pool.
+type PooledTx struct {
+	ID         thor.Bytes32        `json:"id"`
+	Origin     thor.Address        `json:"origin"`
+	Nonce      math.HexOrDecimal64 `json:"nonce"`
+	TimeInPool uint64              `json:"timeInPool"` // seconds
+	Local      bool                `json:"local"`      // submitted locally on this node
+}
+
+// ConvertTxPoolContent splits the pooled txs into pending and queued ones.
+func ConvertTxPoolContent(txs []*txpool.PooledTx, now time.Time) *TxPoolContent {
+	content := &TxPoolContent{
+		Pending: make([]*PooledTx, 0),
+		Queued:  make([]*PooledTx, 0),
+	}
+	for _, tx := range txs {
+		var timeInPool uint64
+		if d := now.Sub(tx.TimeAdded); d > 0 {
+			timeInPool = uint64(d / time.Second)
+		}
+		ptx := &PooledTx{
+			ID:         tx.ID,
+			Origin:     tx.Origin,
+			Nonce:      math.HexOrDecimal64(tx.Nonce),
+			TimeInPool: timeInPool,
+			Local:      tx.Local,
+		}
+		if tx.Executable {
+			content.Pending = append(content.Pending, ptx)
+		} else {
+			content.Queued = append(content.Queued, ptx)
+		}
+	}
+	return content
+}
+
 type PeerStats struct {
 	Name        string       `json:"name"`
 	BestBlockID thor.Bytes32 `json:"bestBlockID"`
@@ -24,6 +89,36 @@ type PeerStats struct {
 	Duration    uint64       `json:"duration"`
 }
 
+// BootnodeStats is the connection health of a bootnode.
+type BootnodeStats struct {
+	Enode       string `json:"enode"`
+	Connected   bool   `json:"connected"`
+	Successes   uint64 `json:"successes"`
+	Failures    uint64 `json:"failures"`
+	LastSuccess uint64 `json:"lastSuccess"` // unix timestamp, 0 if never
+	LastFailure uint64 `json:"lastFailure"` // unix timestamp, 0 if never
+	LastError   string `json:"lastError"`
+}
+
+// Config is the chain config that clients need to know, e.g. to decide the polling frequency.
+type Config struct {
+	BlockInterval uint64          `json:"blockInterval"`
+	ForkConfig    thor.ForkConfig `json:"forkConfig"`
+}
+
+// Schedule is the proposer schedule upon the parent block.
+type Schedule struct {
+	ParentID thor.Bytes32    `json:"parentID"`
+	Slots    []*ScheduleSlot `json:"slots"`
+}
+
+// ScheduleSlot is a block slot of a proposer. An absentee's slots are taken only if it brings itself back.
+type ScheduleSlot struct {
+	Proposer  thor.Address `json:"proposer"`
+	Timestamp uint64       `json:"timestamp"`
+	Absentee  bool         `json:"absentee"`
+}
+
 func ConvertPeersStats(ss []*comm.PeerStats) []*PeerStats {
 	if len(ss) == 0 {
 		return nil
@@ -42,3 +137,25 @@ func ConvertPeersStats(ss []*comm.PeerStats) []*PeerStats {
 	}
 	return peersStats
 }
+
+func ConvertBootnodesHealth(hs []*p2psrv.NodeHealth) []*BootnodeStats {
+	unix := func(t time.Time) uint64 {
+		if t.IsZero() {
+			return 0
+		}
+		return uint64(t.Unix())
+	}
+	stats := make([]*BootnodeStats, len(hs))
+	for i, h := range hs {
+		stats[i] = &BootnodeStats{
+			Enode:       h.Node.String(),
+			Connected:   h.Connected,
+			Successes:   h.Successes,
+			Failures:    h.Failures,
+			LastSuccess: unix(h.LastSuccess),
+			LastFailure: unix(h.LastFailure),
+			LastError:   h.LastError,
+		}
+	}
+	return stats
+}
@@ -8,13 +8,25 @@ package node_test
 import (
 	"crypto/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/api/node"
 	"github.com/vechain/thor/v2/comm"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/txpool"
 )
 
+func TestConvertTxPoolContent(t *testing.T) {
+	now := time.Now()
+	pending := &txpool.PooledTx{ID: randomBytes32(), Nonce: 1, TimeAdded: now.Add(-time.Minute), Executable: true, Local: true}
+	queued := &txpool.PooledTx{ID: randomBytes32(), Nonce: 2, TimeAdded: now.Add(time.Second)}
+
+	content := node.ConvertTxPoolContent([]*txpool.PooledTx{pending, queued}, now)
+	assert.Equal(t, []*node.PooledTx{{ID: pending.ID, Nonce: 1, TimeInPool: 60, Local: true}}, content.Pending)
+	assert.Equal(t, []*node.PooledTx{{ID: queued.ID, Nonce: 2, TimeInPool: 0}}, content.Queued)
+}
+
 func TestConvertPeersStats(t *testing.T) {
 	// Test case 1: Empty input slice
 	ss := []*comm.PeerStats{}
@@ -0,0 +1,137 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bucketSweepInterval is the interval to drop idle buckets.
+const bucketSweepInterval = time.Minute
+
+// RateLimiter decides whether a request from the given client IP can be served.
+type RateLimiter interface {
+	// Allow reports whether a request from ip is allowed, if not, it also returns
+	// the duration the client should wait before retrying.
+	Allow(ip string) (bool, time.Duration)
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// ipRateLimiter implements RateLimiter with a token bucket per client IP.
+type ipRateLimiter struct {
+	lock      sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewIPRateLimiter creates a per-IP token bucket rate limiter, which allows rate
+// requests per second on average and bursts of up to burst requests.
+func NewIPRateLimiter(rate float64, burst int) RateLimiter {
+	return &ipRateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+func (l *ipRateLimiter) Allow(ip string) (bool, time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) > bucketSweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[ip]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[ip] = b
+	} else {
+		b.tokens = l.refill(b, now)
+		b.last = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// refill returns the amount of tokens the bucket holds at the given time.
+func (l *ipRateLimiter) refill(b *tokenBucket, now time.Time) float64 {
+	tokens := b.tokens + now.Sub(b.last).Seconds()*l.rate
+	return math.Min(tokens, l.burst)
+}
+
+// sweep drops all fully refilled buckets, they are equivalent to absent ones.
+func (l *ipRateLimiter) sweep(now time.Time) {
+	for ip, b := range l.buckets {
+		if l.refill(b, now) >= l.burst {
+			delete(l.buckets, ip)
+		}
+	}
+	l.lastSweep = now
+}
+
+// clientIP extracts the client IP of the request. 'X-Forwarded-For' header is set by the client, so it's
+// honored only if the remote address is a trusted proxy. Addresses in the header are then walked backwards,
+// and the first one not of a trusted proxy is taken, so that clients behind the proxies are distinguished.
+func clientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if len(trustedProxies) == 0 || !ipAllowed(r.RemoteAddr, trustedProxies) {
+		return ip
+	}
+
+	addrs := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(addrs) - 1; i >= 0; i-- {
+		fwd := net.ParseIP(strings.TrimSpace(addrs[i]))
+		if fwd == nil {
+			break
+		}
+		ip = fwd.String()
+		if !ipAllowed(ip, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+// RateLimitHandler returns a http handler which rejects requests with 429 Too Many Requests
+// once the client exceeds the limit. 'X-Forwarded-For' header is trusted only from trustedProxies.
+func RateLimitHandler(handler http.Handler, limiter RateLimiter, trustedProxies []*net.IPNet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := limiter.Allow(clientIP(r, trustedProxies)); !ok {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
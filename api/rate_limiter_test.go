@@ -0,0 +1,121 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPRateLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := NewIPRateLimiter(2, 3).(*ipRateLimiter)
+	limiter.now = func() time.Time { return now }
+
+	// burst
+	for i := 0; i < 3; i++ {
+		ok, _ := limiter.Allow("1.1.1.1")
+		assert.True(t, ok)
+	}
+	ok, wait := limiter.Allow("1.1.1.1")
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// other IPs are not affected
+	ok, _ = limiter.Allow("2.2.2.2")
+	assert.True(t, ok)
+
+	// refilled
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = limiter.Allow("1.1.1.1")
+	assert.True(t, ok)
+	ok, _ = limiter.Allow("1.1.1.1")
+	assert.False(t, ok)
+
+	// idle buckets are dropped
+	now = now.Add(2 * bucketSweepInterval)
+	ok, _ = limiter.Allow("1.1.1.1")
+	assert.True(t, ok)
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func TestClientIP(t *testing.T) {
+	proxies := []*net.IPNet{{IP: net.IPv4(10, 0, 0, 0), Mask: net.CIDRMask(8, 32)}}
+
+	req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	assert.Equal(t, "10.0.0.1", clientIP(req, proxies))
+
+	// the last address not of trusted proxies
+	req.Header.Set("X-Forwarded-For", "198.51.100.1, 203.0.113.1, 10.0.0.2")
+	assert.Equal(t, "203.0.113.1", clientIP(req, proxies))
+
+	req.Header.Set("X-Forwarded-For", "invalid")
+	assert.Equal(t, "10.0.0.1", clientIP(req, proxies))
+
+	req.Header.Set("X-Forwarded-For", "invalid, 10.0.0.2")
+	assert.Equal(t, "10.0.0.2", clientIP(req, proxies))
+
+	// not trusted if no proxies configured
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+	assert.Equal(t, "10.0.0.1", clientIP(req, nil))
+
+	// spoofed by an untrusted peer
+	req.RemoteAddr = "192.0.2.1:1234"
+	assert.Equal(t, "192.0.2.1", clientIP(req, proxies))
+}
+
+func TestRateLimitHandlerSpoofedXFF(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	proxies := []*net.IPNet{{IP: net.IPv4(10, 0, 0, 0), Mask: net.CIDRMask(8, 32)}}
+	handler := RateLimitHandler(testHandler, NewIPRateLimiter(0.5, 1), proxies)
+
+	// a fresh 'X-Forwarded-For' per request from an untrusted peer doesn't get a fresh bucket
+	for i, expected := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i+1))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, expected, rr.Code)
+	}
+
+	// while clients behind a trusted proxy are distinguished
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i+1))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RateLimitHandler(testHandler, NewIPRateLimiter(0.5, 1), nil)
+
+	req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"net/http"
+)
+
+// MaxRequestBodyHandler returns a http handler which limits the request body to maxBytes.
+// Requests declaring a larger Content-Length are rejected with 413 Request Entity Too Large
+// right away, otherwise reading beyond the limit fails with *http.MaxBytesError.
+func MaxRequestBodyHandler(handler http.Handler, maxBytes int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > maxBytes {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/api/utils"
+)
+
+func TestMaxRequestBodyHandler(t *testing.T) {
+	parse := utils.WrapHandlerFunc(func(w http.ResponseWriter, req *http.Request) error {
+		var body map[string]string
+		if err := utils.ParseJSON(req.Body, &body); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "body"))
+		}
+		return utils.WriteJSON(w, body)
+	})
+	handler := MaxRequestBodyHandler(parse, 16)
+
+	post := func(h http.Handler, body string, chunked bool) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		if chunked {
+			// unknown length, the limit is hit while reading
+			req.ContentLength = -1
+			req.Body = io.NopCloser(req.Body)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, post(handler, `{"a":"b"}`, false))
+	assert.Equal(t, http.StatusOK, post(handler, `{"a":"b"}`, true))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(handler, `{"a":"0123456789abcdef"}`, false))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(handler, `{"a":"0123456789abcdef"}`, true))
+	// other malformed bodies are still bad requests
+	assert.Equal(t, http.StatusBadRequest, post(handler, `{"a":1}`, true))
+
+	// the request logger reads the whole body
+	logged := MaxRequestBodyHandler(RequestLoggerHandler(parse, log15.New()), 16)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(logged, `{"a":"0123456789abcdef"}`, true))
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDHandler(t *testing.T) {
+	var got string
+	handler := RequestIDHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = RequestID(r.Context())
+	}))
+
+	tests := []struct {
+		name    string
+		inbound string
+		honored bool
+	}{
+		{"generated", "", false},
+		{"honored", "abc-123", true},
+		{"too long", strings.Repeat("a", maxRequestIDLen+1), false},
+		{"invalid chars", "abc\n123", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.inbound != "" {
+				req.Header.Set(RequestIDHeader, tt.inbound)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			id := rr.Header().Get(RequestIDHeader)
+			assert.Equal(t, id, got)
+			if tt.honored {
+				assert.Equal(t, tt.inbound, id)
+			} else {
+				assert.NotNil(t, uuid.Parse(id))
+			}
+		})
+	}
+}
+
+func TestRequestIDLogged(t *testing.T) {
+	mockLog := &mockLogger{}
+	handler := RequestIDHandler(RequestLoggerHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), mockLog))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, mockLog.GetLoggedData(), "RequestID")
+	assert.Contains(t, mockLog.GetLoggedData(), "abc-123")
+}
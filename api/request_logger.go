@@ -24,6 +24,10 @@ func RequestLoggerHandler(handler http.Handler, logger log15.Logger) http.Handle
 		if r.Body != nil {
 			bodyBytes, err = io.ReadAll(r.Body)
 			if err != nil {
+				if _, ok := err.(*http.MaxBytesError); ok {
+					http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+					return
+				}
 				logger.Warn("unexpected body read error", "err", err)
 				return // don't pass bad request to the next handler
 			}
@@ -32,6 +36,7 @@ func RequestLoggerHandler(handler http.Handler, logger log15.Logger) http.Handle
 
 		logger.Info("API Request",
 			"timestamp", time.Now().Unix(),
+			"RequestID", RequestID(r.Context()),
 			"URI", r.URL.String(),
 			"Method", r.Method,
 			"Body", string(bodyBytes),
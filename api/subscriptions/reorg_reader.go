@@ -0,0 +1,59 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// reorgReader compares the best chain with the last seen head, and emits a message
+// when the head was replaced by a competing branch.
+type reorgReader struct {
+	repo *chain.Repository
+	head thor.Bytes32
+}
+
+func newReorgReader(repo *chain.Repository) *reorgReader {
+	return &reorgReader{
+		repo: repo,
+		head: repo.BestBlockSummary().Header.ID(),
+	}
+}
+
+func (rr *reorgReader) Read() ([]interface{}, bool, error) {
+	bestChain := rr.repo.NewBestChain()
+	newHead := bestChain.HeadID()
+	if newHead == rr.head {
+		return nil, false, nil
+	}
+
+	// blocks of the old branch, in ascending order
+	dropped, err := rr.repo.NewChain(rr.head).Exclude(bestChain)
+	if err != nil {
+		return nil, false, err
+	}
+	oldHead := rr.head
+	rr.head = newHead
+	if len(dropped) == 0 {
+		// the best chain extended
+		return nil, false, nil
+	}
+
+	sum, err := rr.repo.GetBlockSummary(dropped[0])
+	if err != nil {
+		return nil, false, err
+	}
+	ancestor := sum.Header.ParentID()
+	return []interface{}{&ReorgMessage{
+		OldHead:        oldHead,
+		NewHead:        newHead,
+		CommonAncestor: ancestor,
+		DroppedBlocks:  dropped,
+		Depth:          block.Number(oldHead) - block.Number(ancestor),
+	}}, false, nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+func newReorgTestRepo(t *testing.T) (*chain.Repository, *state.Stater) {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b0, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := chain.NewRepository(db, b0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, stater
+}
+
+// addReorgTestBlock adds a block on the parent, without changing the best block.
+func addReorgTestBlock(t *testing.T, repo *chain.Repository, parent *block.Block, ts uint64) *block.Block {
+	b := new(block.Builder).
+		ParentID(parent.Header().ID()).
+		Timestamp(ts).
+		Build()
+	pk, _ := crypto.GenerateKey()
+	sig, _ := crypto.Sign(b.Header().SigningHash().Bytes(), pk)
+	b = b.WithSignature(sig)
+
+	conflicts, err := repo.ScanConflicts(b.Header().Number())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddBlock(b, nil, conflicts); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestReorgReader_Read(t *testing.T) {
+	repo, _ := newReorgTestRepo(t)
+	b0 := repo.GenesisBlock()
+	b1 := addReorgTestBlock(t, repo, b0, 10)
+	b2 := addReorgTestBlock(t, repo, b1, 20)
+	assert.Nil(t, repo.SetBestBlockID(b2.Header().ID()))
+
+	rr := newReorgReader(repo)
+	msgs, hasMore, err := rr.Read()
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Empty(t, msgs)
+
+	// the best chain extended
+	b3 := addReorgTestBlock(t, repo, b2, 30)
+	assert.Nil(t, repo.SetBestBlockID(b3.Header().ID()))
+	msgs, _, err = rr.Read()
+	assert.Nil(t, err)
+	assert.Empty(t, msgs)
+
+	// switched to a competing branch
+	b2x := addReorgTestBlock(t, repo, b1, 21)
+	b3x := addReorgTestBlock(t, repo, b2x, 31)
+	b4x := addReorgTestBlock(t, repo, b3x, 41)
+	assert.Nil(t, repo.SetBestBlockID(b4x.Header().ID()))
+	msgs, hasMore, err = rr.Read()
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, []interface{}{&ReorgMessage{
+		OldHead:        b3.Header().ID(),
+		NewHead:        b4x.Header().ID(),
+		CommonAncestor: b1.Header().ID(),
+		DroppedBlocks:  []thor.Bytes32{b2.Header().ID(), b3.Header().ID()},
+		Depth:          2,
+	}}, msgs)
+
+	// notified once
+	msgs, _, err = rr.Read()
+	assert.Nil(t, err)
+	assert.Empty(t, msgs)
+}
+
+func TestHandleSubjectWithReorg(t *testing.T) {
+	repo, stater := newReorgTestRepo(t)
+	b0 := repo.GenesisBlock()
+	b1 := addReorgTestBlock(t, repo, b0, 10)
+	assert.Nil(t, repo.SetBestBlockID(b1.Header().ID()))
+
+	router := mux.NewRouter()
+	pool := txpool.New(repo, stater, txpool.Options{Limit: 100, LimitPerAccount: 16, MaxLifetime: time.Hour})
+	defer pool.Close()
+	sub := New(repo, []string{}, 5, pool, 0)
+	defer sub.Close()
+	sub.Mount(router, "/subscriptions")
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/subscriptions/reorg", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	b1x := addReorgTestBlock(t, repo, b0, 11)
+	b2x := addReorgTestBlock(t, repo, b1x, 21)
+	assert.Nil(t, repo.SetBestBlockID(b2x.Header().ID()))
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg ReorgMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ReorgMessage{
+		OldHead:        b1.Header().ID(),
+		NewHead:        b2x.Header().ID(),
+		CommonAncestor: b0.Header().ID(),
+		DroppedBlocks:  []thor.Bytes32{b1.Header().ID()},
+		Depth:          1,
+	}, msg)
+}
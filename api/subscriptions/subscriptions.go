@@ -22,10 +22,15 @@ import (
 	"github.com/vechain/thor/v2/txpool"
 )
 
-const txQueueSize = 20
+const (
+	txQueueSize = 20
+	// defaultMsgQueueSize is the default max count of queued messages per subscription.
+	defaultMsgQueueSize = 100
+)
 
 type Subscriptions struct {
 	backtraceLimit uint32
+	msgQueueSize   int
 	repo           *chain.Repository
 	upgrader       *websocket.Upgrader
 	pendingTx      *pendingTx
@@ -39,6 +44,8 @@ type msgReader interface {
 
 var (
 	log = log15.New("pkg", "subscriptions")
+
+	errSlowConsumer = errors.New("message queue exceeded")
 )
 
 const (
@@ -46,11 +53,21 @@ const (
 	pongWait = 60 * time.Second
 	// Send pings to peer with this period. Must be less than pongWait.
 	pingPeriod = (pongWait * 7) / 10
+	// Time allowed to write a control message to the peer.
+	writeWait = time.Second
+	// Time allowed for the peer to consume a message when the message queue is full.
+	queueFullWait = 5 * time.Second
 )
 
-func New(repo *chain.Repository, allowedOrigins []string, backtraceLimit uint32, txpool *txpool.TxPool) *Subscriptions {
+// New creates a Subscriptions object. msgQueueSize limits the count of messages queued for each
+// subscription, the connection is closed with policy violation once exceeded. 0 means the default.
+func New(repo *chain.Repository, allowedOrigins []string, backtraceLimit uint32, txpool *txpool.TxPool, msgQueueSize int) *Subscriptions {
+	if msgQueueSize <= 0 {
+		msgQueueSize = defaultMsgQueueSize
+	}
 	sub := &Subscriptions{
 		backtraceLimit: backtraceLimit,
+		msgQueueSize:   msgQueueSize,
 		repo:           repo,
 		upgrader: &websocket.Upgrader{
 			EnableCompression: true,
@@ -198,6 +215,8 @@ func (s *Subscriptions) handleSubject(w http.ResponseWriter, req *http.Request)
 		if reader, err = s.handleBeat2Reader(w, req); err != nil {
 			return err
 		}
+	case "reorg":
+		reader = newReorgReader(s.repo)
 	default:
 		return utils.HTTPError(errors.New("not found"), http.StatusNotFound)
 	}
@@ -248,7 +267,7 @@ func (s *Subscriptions) handlePendingTransactions(w http.ResponseWriter, req *ht
 		case <-closed:
 			return nil
 		case <-pingTicker.C:
-			conn.WriteMessage(websocket.PingMessage, nil)
+			conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
 		}
 	}
 }
@@ -283,13 +302,16 @@ func (s *Subscriptions) setupConn(w http.ResponseWriter, req *http.Request) (*we
 
 func (s *Subscriptions) closeConn(conn *websocket.Conn, err error) {
 	var closeMsg []byte
-	if err != nil {
+	if err == errSlowConsumer {
+		closeMsg = websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error())
+	} else if err != nil {
 		closeMsg = websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error())
 	} else {
 		closeMsg = websocket.FormatCloseMessage(websocket.CloseGoingAway, "")
 	}
 
-	if err := conn.WriteMessage(websocket.CloseMessage, closeMsg); err != nil {
+	// control message is allowed to be written concurrently with the message writer
+	if err := conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(writeWait)); err != nil {
 		log.Debug("write close message", "err", err)
 	}
 
@@ -298,7 +320,46 @@ func (s *Subscriptions) closeConn(conn *websocket.Conn, err error) {
 	}
 }
 
+// pipe reads messages from the reader and queues them to be written to the conn. The queue is bounded,
+// errSlowConsumer is returned if the peer is unable to consume the queued messages in time.
 func (s *Subscriptions) pipe(conn *websocket.Conn, reader msgReader, closed chan struct{}) error {
+	queue := make(chan interface{}, s.msgQueueSize)
+	writeErr := make(chan error, 1)
+	defer close(queue)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for msg := range queue {
+			if err := conn.WriteJSON(msg); err != nil {
+				writeErr <- err
+				// the conn is closed then, drain the queue
+				for range queue {
+				}
+				return
+			}
+		}
+	}()
+
+	enqueue := func(msg interface{}) error {
+		select {
+		case queue <- msg:
+			return nil
+		default:
+		}
+		// queue is full, wait a while for the peer to catch up
+		timer := time.NewTimer(queueFullWait)
+		defer timer.Stop()
+		select {
+		case queue <- msg:
+			return nil
+		case err := <-writeErr:
+			return err
+		case <-timer.C:
+			return errSlowConsumer
+		}
+	}
+
 	ticker := s.repo.NewTicker()
 	pingTicker := time.NewTicker(pingPeriod)
 	defer pingTicker.Stop()
@@ -308,7 +369,7 @@ func (s *Subscriptions) pipe(conn *websocket.Conn, reader msgReader, closed chan
 			return err
 		}
 		for _, msg := range msgs {
-			if err := conn.WriteJSON(msg); err != nil {
+			if err := enqueue(msg); err != nil {
 				return err
 			}
 		}
@@ -318,8 +379,10 @@ func (s *Subscriptions) pipe(conn *websocket.Conn, reader msgReader, closed chan
 				return nil
 			case <-closed:
 				return nil
+			case err := <-writeErr:
+				return err
 			case <-pingTicker.C:
-				conn.WriteMessage(websocket.PingMessage, nil)
+				conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
 			default:
 			}
 		} else {
@@ -328,9 +391,11 @@ func (s *Subscriptions) pipe(conn *websocket.Conn, reader msgReader, closed chan
 				return nil
 			case <-closed:
 				return nil
+			case err := <-writeErr:
+				return err
 			case <-ticker.C():
 			case <-pingTicker.C:
-				conn.WriteMessage(websocket.PingMessage, nil)
+				conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
 			}
 		}
 	}
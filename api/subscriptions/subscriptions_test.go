@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
@@ -201,6 +202,56 @@ func testHandleSubjectWithNonValidArgument(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
+// endlessReader produces messages endlessly
+type endlessReader struct {
+	msg interface{}
+}
+
+func (r *endlessReader) Read() ([]interface{}, bool, error) {
+	return []interface{}{r.msg}, true, nil
+}
+
+func TestPipeWithStalledReader(t *testing.T) {
+	repo, _, txPool := initChain(t)
+	s := New(repo, []string{}, 5, txPool, 2)
+	defer s.Close()
+
+	reader := &endlessReader{strings.Repeat("x", 64*1024)}
+	pipeErr := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, closed, err := s.setupConn(w, req)
+		if err != nil {
+			pipeErr <- err
+			return
+		}
+		err = s.pipe(conn, reader, closed)
+		s.closeConn(conn, err)
+		pipeErr <- err
+	}))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// the client never reads, the server should drop it rather than buffering endlessly
+	select {
+	case err := <-pipeErr:
+		assert.Equal(t, errSlowConsumer, err)
+	case <-time.After(3 * queueFullWait):
+		t.Fatal("stalled reader not dropped")
+	}
+
+	// the conn ends after messages already in flight, the close message might be lost since the
+	// server gives up writing to the stalled conn
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation, websocket.CloseAbnormalClosure))
+			break
+		}
+	}
+}
+
 func TestParseAddress(t *testing.T) {
 	addrStr := "0x0123456789abcdef0123456789abcdef01234567"
 	expectedAddr := thor.MustParseAddress(addrStr)
@@ -217,7 +268,7 @@ func initSubscriptionsServer(t *testing.T) {
 	txPool = pool
 	blocks = generatedBlocks
 	router := mux.NewRouter()
-	sub = New(repo, []string{}, 5, txPool)
+	sub = New(repo, []string{}, 5, txPool, 0)
 	sub.Mount(router, "/subscriptions")
 	ts = httptest.NewServer(router)
 	client = &http.Client{}
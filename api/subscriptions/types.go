@@ -197,6 +197,15 @@ func (tf *TransferFilter) Match(transfer *tx.Transfer, origin thor.Address) bool
 	return true
 }
 
+// ReorgMessage notifies that the best chain head was replaced by a competing branch.
+type ReorgMessage struct {
+	OldHead        thor.Bytes32   `json:"oldHead"`
+	NewHead        thor.Bytes32   `json:"newHead"`
+	CommonAncestor thor.Bytes32   `json:"commonAncestor"`
+	DroppedBlocks  []thor.Bytes32 `json:"droppedBlocks"` // blocks of the old branch, in ascending order
+	Depth          uint32         `json:"depth"`         // count of dropped blocks
+}
+
 type BeatMessage struct {
 	Number      uint32       `json:"number"`
 	ID          thor.Bytes32 `json:"id"`
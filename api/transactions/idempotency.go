@@ -0,0 +1,137 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package transactions
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// IdempotencyKeyHeader is the request header carrying the client supplied key of a tx submission.
+// Submissions with the same key get the outcome of the first one replayed, within the TTL.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const (
+	idempotencyCacheSize = 8192
+	maxIdempotencyKeyLen = 255
+)
+
+// idempotencyOutcome is the recorded response of a tx submission.
+type idempotencyOutcome struct {
+	status      int
+	contentType string
+	body        []byte
+}
+
+func (o *idempotencyOutcome) writeTo(w http.ResponseWriter) {
+	if o.contentType != "" {
+		w.Header().Set("Content-Type", o.contentType)
+	}
+	w.WriteHeader(o.status)
+	w.Write(o.body)
+}
+
+type idempotencyEntry struct {
+	reqHash  thor.Bytes32
+	outcome  *idempotencyOutcome // nil while in progress
+	expireAt time.Time
+}
+
+// idempotencyCache caches outcomes of tx submissions by idempotency key, in a bounded LRU cache.
+type idempotencyCache struct {
+	ttl     time.Duration
+	lock    sync.Mutex
+	entries *lru.Cache
+}
+
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	entries, _ := lru.New(idempotencyCacheSize)
+	return &idempotencyCache{ttl: ttl, entries: entries}
+}
+
+// do returns the cached outcome of the key, or calls fn to make it. The request hash must match the one
+// the key first came with. Server errors are not cached, so that they can be retried.
+func (c *idempotencyCache) do(key string, reqHash thor.Bytes32, fn func() *idempotencyOutcome) (*idempotencyOutcome, error) {
+	c.lock.Lock()
+	if v, ok := c.entries.Get(key); ok {
+		entry := v.(*idempotencyEntry)
+		if entry.outcome != nil && time.Now().After(entry.expireAt) {
+			c.entries.Remove(key)
+		} else {
+			c.lock.Unlock()
+			if entry.reqHash != reqHash {
+				return nil, utils.HTTPError(errors.New("idempotency key reused with a different request"), http.StatusUnprocessableEntity)
+			}
+			if entry.outcome == nil {
+				return nil, utils.HTTPError(errors.New("request with the same idempotency key in progress"), http.StatusConflict)
+			}
+			return entry.outcome, nil
+		}
+	}
+	entry := &idempotencyEntry{reqHash: reqHash}
+	c.entries.Add(key, entry)
+	c.lock.Unlock()
+
+	var outcome *idempotencyOutcome
+	defer func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		// removed if fn panics as well, otherwise the key would be in progress forever
+		if outcome == nil || outcome.status >= http.StatusInternalServerError {
+			if v, ok := c.entries.Peek(key); ok && v == entry {
+				c.entries.Remove(key)
+			}
+		} else {
+			entry.outcome = outcome
+			entry.expireAt = time.Now().Add(c.ttl)
+		}
+	}()
+	outcome = fn()
+	return outcome, nil
+}
+
+// outcomeRecorder records the response of a handler as an idempotency outcome.
+type outcomeRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *outcomeRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *outcomeRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *outcomeRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *outcomeRecorder) outcome() *idempotencyOutcome {
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &idempotencyOutcome{
+		status:      status,
+		contentType: r.header.Get("Content-Type"),
+		body:        r.body.Bytes(),
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package transactions
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestIdempotencyCache(t *testing.T) {
+	cache := newIdempotencyCache(50 * time.Millisecond)
+
+	calls := 0
+	fn := func(status int) func() *idempotencyOutcome {
+		return func() *idempotencyOutcome {
+			calls++
+			return &idempotencyOutcome{status: status, body: []byte{byte(calls)}}
+		}
+	}
+
+	o, err := cache.do("a", thor.Bytes32{1}, fn(http.StatusOK))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1}, o.body)
+	o, err = cache.do("a", thor.Bytes32{1}, fn(http.StatusOK))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1}, o.body)
+	assert.Equal(t, 1, calls)
+
+	_, err = cache.do("a", thor.Bytes32{2}, fn(http.StatusOK))
+	assert.Contains(t, err.Error(), "different request")
+
+	// expired
+	time.Sleep(60 * time.Millisecond)
+	o, err = cache.do("a", thor.Bytes32{2}, fn(http.StatusOK))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{2}, o.body)
+
+	// server errors not cached
+	_, _ = cache.do("b", thor.Bytes32{1}, fn(http.StatusInternalServerError))
+	o, _ = cache.do("b", thor.Bytes32{1}, fn(http.StatusOK))
+	assert.Equal(t, []byte{4}, o.body)
+
+	// in progress
+	_, _ = cache.do("c", thor.Bytes32{1}, func() *idempotencyOutcome {
+		_, err := cache.do("c", thor.Bytes32{1}, fn(http.StatusOK))
+		assert.Contains(t, err.Error(), "in progress")
+		return &idempotencyOutcome{status: http.StatusOK}
+	})
+	assert.Equal(t, 4, calls)
+
+	// not in progress forever if panicked
+	assert.Panics(t, func() {
+		_, _ = cache.do("d", thor.Bytes32{1}, func() *idempotencyOutcome { panic("boom") })
+	})
+	o, err = cache.do("d", thor.Bytes32{1}, fn(http.StatusOK))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{5}, o.body)
+}
@@ -6,30 +6,79 @@
 package transactions
 
 import (
+	"bytes"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/v2/api/utils"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
 	"github.com/vechain/thor/v2/txpool"
 )
 
 type Transactions struct {
-	repo *chain.Repository
-	pool *txpool.TxPool
+	repo          *chain.Repository
+	pool          *txpool.TxPool
+	blockInterval uint64
+	signerCache   *lru.Cache
+	idempotency   *idempotencyCache
 }
 
-func New(repo *chain.Repository, pool *txpool.TxPool) *Transactions {
+// New creates the transactions API. The block interval is the one the node configured with, to locate
+// the schedule position of blocks. Signers recovered from signatures are cached for up to
+// signerCacheSize txs, and the cache is disabled if it's 0. Outcomes of tx submissions with
+// idempotency keys are cached for idempotencyTTL, and keys are ignored if it's 0.
+func New(repo *chain.Repository, pool *txpool.TxPool, blockInterval uint64, signerCacheSize int, idempotencyTTL time.Duration) *Transactions {
+	var signerCache *lru.Cache
+	if signerCacheSize > 0 {
+		signerCache, _ = lru.New(signerCacheSize)
+	}
+	var idempotency *idempotencyCache
+	if idempotencyTTL > 0 {
+		idempotency = newIdempotencyCache(idempotencyTTL)
+	}
 	return &Transactions{
 		repo,
 		pool,
+		blockInterval,
+		signerCache,
+		idempotency,
 	}
 }
 
+// signers returns the tx id and signers. It's cached by tx hash, since tx objects loaded again are
+// not aware of the signers recovered before.
+func (t *Transactions) signers(trx *tx.Transaction) *txSigners {
+	var key thor.Bytes32
+	if t.signerCache != nil {
+		key = trx.Hash()
+		if cached, ok := t.signerCache.Get(key); ok {
+			return cached.(*txSigners)
+		}
+	}
+
+	origin, err := trx.Origin()
+	if err != nil {
+		return &txSigners{id: trx.ID()}
+	}
+	delegator, err := trx.Delegator()
+	if err != nil {
+		return &txSigners{id: trx.ID(), origin: origin}
+	}
+	signers := &txSigners{trx.ID(), origin, delegator}
+	if t.signerCache != nil {
+		t.signerCache.Add(key, signers)
+	}
+	return signers
+}
+
 func (t *Transactions) getRawTransaction(txID thor.Bytes32, head thor.Bytes32, allowPending bool) (*rawTransaction, error) {
 	chain := t.repo.NewChain(head)
 	tx, meta, err := chain.GetTransaction(txID)
@@ -76,7 +125,7 @@ func (t *Transactions) getTransactionByID(txID thor.Bytes32, head thor.Bytes32,
 		if t.repo.IsNotFound(err) {
 			if allowPending {
 				if pending := t.pool.Get(txID); pending != nil {
-					return convertTransaction(pending, nil), nil
+					return convertTransaction(pending, nil, t.signers(pending)), nil
 				}
 			}
 			return nil, nil
@@ -88,7 +137,7 @@ func (t *Transactions) getTransactionByID(txID thor.Bytes32, head thor.Bytes32,
 	if err != nil {
 		return nil, err
 	}
-	return convertTransaction(tx, summary.Header), nil
+	return convertTransaction(tx, summary.Header, t.signers(tx)), nil
 }
 
 // GetTransactionReceiptByID get tx's receipt
@@ -114,7 +163,77 @@ func (t *Transactions) getTransactionReceiptByID(txID thor.Bytes32, head thor.By
 
 	return convertReceipt(receipt, summary.Header, tx)
 }
+
+// getTransactionScheduleMeta get the meta of the tx's block, along with its schedule position.
+func (t *Transactions) getTransactionScheduleMeta(txID thor.Bytes32, head thor.Bytes32) (*TxScheduleMeta, error) {
+	chain := t.repo.NewChain(head)
+	_, meta, err := chain.GetTransaction(txID)
+	if err != nil {
+		if t.repo.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	summary, err := t.repo.GetBlockSummary(meta.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := t.repo.GetBlockSummary(summary.Header.ParentID())
+	if err != nil {
+		return nil, err
+	}
+
+	// the earliest time slot after the parent, see poa.SchedulerV2
+	expected := parent.Header.Timestamp() + t.blockInterval
+	timestamp := summary.Header.Timestamp()
+
+	var skipped uint64
+	if timestamp > expected {
+		skipped = (timestamp - expected) / t.blockInterval
+	}
+	return &TxScheduleMeta{
+		TxMeta: TxMeta{
+			BlockID:        summary.Header.ID(),
+			BlockNumber:    summary.Header.Number(),
+			BlockTimestamp: timestamp,
+		},
+		ExpectedTimestamp: expected,
+		RoundsSkipped:     skipped,
+		OnTime:            skipped == 0,
+	}, nil
+}
+
 func (t *Transactions) handleSendTransaction(w http.ResponseWriter, req *http.Request) error {
+	key := req.Header.Get(IdempotencyKeyHeader)
+	if key == "" || t.idempotency == nil {
+		return t.sendTransaction(w, req)
+	}
+	if len(key) > maxIdempotencyKeyLen {
+		return utils.BadRequest(errors.New("idempotency key too long"))
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		if _, ok := err.(*http.MaxBytesError); ok {
+			return utils.HTTPError(errors.WithMessage(err, "body"), http.StatusRequestEntityTooLarge)
+		}
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	outcome, err := t.idempotency.do(key, thor.Blake2b(body), func() *idempotencyOutcome {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		rec := &outcomeRecorder{header: make(http.Header)}
+		utils.WrapHandlerFunc(t.sendTransaction)(rec, req)
+		return rec.outcome()
+	})
+	if err != nil {
+		return err
+	}
+	outcome.writeTo(w)
+	return nil
+}
+
+func (t *Transactions) sendTransaction(w http.ResponseWriter, req *http.Request) error {
 	var rawTx *RawTx
 	if err := utils.ParseJSON(req.Body, &rawTx); err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "body"))
@@ -203,6 +322,34 @@ func (t *Transactions) handleGetTransactionReceiptByID(w http.ResponseWriter, re
 	return utils.WriteJSON(w, receipt)
 }
 
+func (t *Transactions) handleGetTransactionMetaByID(w http.ResponseWriter, req *http.Request) error {
+	id := mux.Vars(req)["id"]
+	txID, err := thor.ParseBytes32(id)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+
+	head, err := t.parseHead(req.URL.Query().Get("head"))
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "head"))
+	}
+
+	if _, err := t.repo.GetBlockSummary(head); err != nil {
+		if t.repo.IsNotFound(err) {
+			return utils.BadRequest(errors.WithMessage(err, "head"))
+		}
+	}
+
+	meta, err := t.getTransactionScheduleMeta(txID, head)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		return utils.HTTPError(errors.New("transaction not found"), http.StatusNotFound)
+	}
+	return utils.WriteJSON(w, meta)
+}
+
 func (t *Transactions) parseHead(head string) (thor.Bytes32, error) {
 	if head == "" {
 		return t.repo.BestBlockSummary().Header.ID(), nil
@@ -229,4 +376,8 @@ func (t *Transactions) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodGet).
 		Name("transactions_get_receipt").
 		HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionReceiptByID))
+	sub.Path("/{id}/meta").
+		Methods(http.MethodGet).
+		Name("transactions_get_meta").
+		HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionMetaByID))
 }
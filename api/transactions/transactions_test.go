@@ -24,6 +24,7 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/api/transactions"
+	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/genesis"
 	"github.com/vechain/thor/v2/muxdb"
@@ -52,6 +53,7 @@ func TestTransaction(t *testing.T) {
 		"sendTx":              sendTx,
 		"sendTxWithBadFormat": sendTxWithBadFormat,
 		"sendTxThatCannotBeAcceptedInLocalMempool": sendTxThatCannotBeAcceptedInLocalMempool,
+		"sendTxWithIdempotencyKey":                 sendTxWithIdempotencyKey,
 	} {
 		t.Run(name, tt)
 	}
@@ -80,6 +82,15 @@ func TestTransaction(t *testing.T) {
 	} {
 		t.Run(name, tt)
 	}
+
+	// Get tx meta
+	for name, tt := range map[string]func(*testing.T){
+		"getTxMeta":         getTxMeta,
+		"getTxMetaNotFound": getTxMetaNotFound,
+		"getMetaWithBadId":  getMetaWithBadId,
+	} {
+		t.Run(name, tt)
+	}
 }
 
 func getTx(t *testing.T) {
@@ -111,6 +122,51 @@ func getTxReceipt(t *testing.T) {
 	assert.Equal(t, receipt.GasUsed, transaction.Gas(), "receipt gas used not equal to transaction gas")
 }
 
+func getTxMeta(t *testing.T) {
+	r := httpGetAndCheckResponseStatus(t, ts.URL+"/transactions/"+transaction.ID().String()+"/meta", 200)
+	var meta *transactions.TxScheduleMeta
+	if err := json.Unmarshal(r, &meta); err != nil {
+		t.Fatal(err)
+	}
+
+	best := repo.BestBlockSummary().Header
+	parent, err := repo.GetBlockSummary(best.ParentID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := parent.Header.Timestamp() + thor.BlockInterval
+
+	assert.Equal(t, best.ID(), meta.BlockID)
+	assert.Equal(t, best.Timestamp(), meta.BlockTimestamp)
+	assert.Equal(t, expected, meta.ExpectedTimestamp)
+	assert.Equal(t, (best.Timestamp()-expected)/thor.BlockInterval, meta.RoundsSkipped)
+	assert.Equal(t, best.Timestamp() == expected, meta.OnTime)
+
+	// the configured block interval is followed, e.g. in solo mode
+	router := mux.NewRouter()
+	transactions.New(repo, nil, 1, 0, 0).Mount(router, "/transactions")
+	customTs := httptest.NewServer(router)
+	defer customTs.Close()
+
+	r = httpGetAndCheckResponseStatus(t, customTs.URL+"/transactions/"+transaction.ID().String()+"/meta", 200)
+	if err := json.Unmarshal(r, &meta); err != nil {
+		t.Fatal(err)
+	}
+	expected = parent.Header.Timestamp() + 1
+	assert.Equal(t, expected, meta.ExpectedTimestamp)
+	assert.Equal(t, best.Timestamp()-expected, meta.RoundsSkipped)
+}
+
+func getTxMetaNotFound(t *testing.T) {
+	httpGetAndCheckResponseStatus(t, ts.URL+"/transactions/"+mempoolTx.ID().String()+"/meta", 404)
+}
+
+func getMetaWithBadId(t *testing.T) {
+	txBadId := "0x123"
+
+	httpGetAndCheckResponseStatus(t, ts.URL+"/transactions/"+txBadId+"/meta", 400)
+}
+
 func sendTx(t *testing.T) {
 	var blockRef = tx.NewBlockRef(0)
 	var chainTag = repo.ChainTag()
@@ -141,6 +197,72 @@ func sendTx(t *testing.T) {
 	assert.Equal(t, tx.ID().String(), txObj["id"], "should be the same transaction id")
 }
 
+func sendTxWithIdempotencyKey(t *testing.T) {
+	trx := new(tx.Builder).
+		BlockRef(tx.NewBlockRef(0)).
+		ChainTag(repo.ChainTag()).
+		Expiration(10).
+		Gas(21000).
+		Nonce(100).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[1].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trx = trx.WithSignature(sig)
+	rlpTx, err := rlp.EncodeToBytes(trx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(transactions.RawTx{Raw: hexutil.Encode(rlpTx)})
+
+	postTo := func(url, key string, body []byte) (int, []byte) {
+		req, err := http.NewRequest(http.MethodPost, url+"/transactions", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(transactions.IdempotencyKeyHeader, key)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		return res.StatusCode, parseBytesBody(t, res.Body)
+	}
+	post := func(key string, body []byte) (int, []byte) {
+		return postTo(ts.URL, key, body)
+	}
+
+	status, first := post("key1", body)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, string(first), trx.ID().String())
+
+	status, replayed := post("key1", body)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, first, replayed)
+
+	// errors are replayed too
+	status, first = post("key3", []byte("{}"))
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, replayed = post("key3", []byte("{}"))
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, first, replayed)
+
+	status, _ = post("key1", []byte("{}"))
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	status, _ = post(strings.Repeat("k", 256), body)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// oversized body
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		req.Body = http.MaxBytesReader(w, req.Body, 16)
+		ts.Config.Handler.ServeHTTP(w, req)
+	}))
+	defer limited.Close()
+	status, _ = postTo(limited.URL, "key4", body)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+}
+
 func getTxWithBadId(t *testing.T) {
 	txBadId := "0x123"
 
@@ -354,7 +476,7 @@ func initTransactionServer(t *testing.T) {
 		t.Fatal(e)
 	}
 
-	transactions.New(repo, mempool).Mount(router, "/transactions")
+	transactions.New(repo, mempool, thor.BlockInterval, 16, time.Minute).Mount(router, "/transactions")
 
 	ts = httptest.NewServer(router)
 }
@@ -396,3 +518,62 @@ func parseBytesBody(t *testing.T, body io.ReadCloser) []byte {
 	}
 	return r
 }
+
+// BenchmarkGetTransactionByID requests more txs than the repository caches repeatedly, so that
+// txs are loaded again and their signers have to be recovered, unless cached by the API.
+func BenchmarkGetTransactionByID(b *testing.B) {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b0, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		b.Fatal(err)
+	}
+	repo, _ := chain.NewRepository(db, b0)
+
+	var (
+		ids      []thor.Bytes32
+		parentID = b0.Header().ID()
+	)
+	for i := 0; i < 4; i++ {
+		builder := new(block.Builder).ParentID(parentID)
+		var receipts tx.Receipts
+		for j := 0; j < 1000; j++ {
+			trx := new(tx.Builder).ChainTag(repo.ChainTag()).Nonce(uint64(i*1000 + j)).Gas(21000).Build()
+			sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+			if err != nil {
+				b.Fatal(err)
+			}
+			trx = trx.WithSignature(sig)
+			builder.Transaction(trx)
+			receipts = append(receipts, &tx.Receipt{})
+			ids = append(ids, trx.ID())
+		}
+		blk := builder.Build()
+		if err := repo.AddBlock(blk, receipts, 0); err != nil {
+			b.Fatal(err)
+		}
+		parentID = blk.Header().ID()
+	}
+	if err := repo.SetBestBlockID(parentID); err != nil {
+		b.Fatal(err)
+	}
+	pool := txpool.New(repo, stater, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute})
+	defer pool.Close()
+
+	for _, size := range []int{0, 8192} {
+		b.Run(fmt.Sprintf("signer-cache-%v", size), func(b *testing.B) {
+			router := mux.NewRouter()
+			transactions.New(repo, pool, thor.BlockInterval, size, 0).Mount(router, "/transactions")
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				req := httptest.NewRequest(http.MethodGet, "/transactions/"+ids[i%len(ids)].String(), nil)
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				if rec.Code != http.StatusOK {
+					b.Fatal(rec.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -83,11 +83,15 @@ type rawTransaction struct {
 	Meta *TxMeta `json:"meta"`
 }
 
+// txSigners is the tx id and signers, which are derived from the recovered signature.
+type txSigners struct {
+	id        thor.Bytes32
+	origin    thor.Address
+	delegator *thor.Address
+}
+
 // convertTransaction convert a raw transaction into a json format transaction
-func convertTransaction(tx *tx.Transaction, header *block.Header) *Transaction {
-	//tx origin
-	origin, _ := tx.Origin()
-	delegator, _ := tx.Delegator()
+func convertTransaction(tx *tx.Transaction, header *block.Header, signers *txSigners) *Transaction {
 
 	cls := make(Clauses, len(tx.Clauses()))
 	for i, c := range tx.Clauses() {
@@ -96,8 +100,8 @@ func convertTransaction(tx *tx.Transaction, header *block.Header) *Transaction {
 	br := tx.BlockRef()
 	t := &Transaction{
 		ChainTag:     tx.ChainTag(),
-		ID:           tx.ID(),
-		Origin:       origin,
+		ID:           signers.id,
+		Origin:       signers.origin,
 		BlockRef:     hexutil.Encode(br[:]),
 		Expiration:   tx.Expiration(),
 		Nonce:        math.HexOrDecimal64(tx.Nonce()),
@@ -106,7 +110,7 @@ func convertTransaction(tx *tx.Transaction, header *block.Header) *Transaction {
 		Gas:          tx.Gas(),
 		DependsOn:    tx.DependsOn(),
 		Clauses:      cls,
-		Delegator:    delegator,
+		Delegator:    signers.delegator,
 	}
 
 	if header != nil {
@@ -125,6 +129,16 @@ type TxMeta struct {
 	BlockTimestamp uint64       `json:"blockTimestamp"`
 }
 
+// TxScheduleMeta is TxMeta along with the position of the block in the proposers schedule.
+// The block is on time if it's produced in the first slot after its parent, otherwise
+// RoundsSkipped slots were missed by the scheduled proposers.
+type TxScheduleMeta struct {
+	TxMeta
+	ExpectedTimestamp uint64 `json:"expectedTimestamp"`
+	RoundsSkipped     uint64 `json:"roundsSkipped"`
+	OnTime            bool   `json:"onTime"`
+}
+
 type ReceiptMeta struct {
 	BlockID        thor.Bytes32 `json:"blockID"`
 	BlockNumber    uint32       `json:"blockNumber"`
@@ -187,7 +201,13 @@ func convertReceipt(txReceipt *tx.Receipt, header *block.Header, tx *tx.Transact
 			origin,
 		},
 	}
-	receipt.Outputs = make([]*Output, len(txReceipt.Outputs))
+	receipt.Outputs = ConvertOutputs(txReceipt, tx)
+	return receipt, nil
+}
+
+// ConvertOutputs converts the clause outputs of a receipt into json format.
+func ConvertOutputs(txReceipt *tx.Receipt, tx *tx.Transaction) []*Output {
+	outputs := make([]*Output, len(txReceipt.Outputs))
 	for i, output := range txReceipt.Outputs {
 		clause := tx.Clauses()[i]
 		var contractAddr *thor.Address
@@ -216,7 +236,7 @@ func convertReceipt(txReceipt *tx.Receipt, header *block.Header, tx *tx.Transact
 			}
 			otp.Transfers[j] = transfer
 		}
-		receipt.Outputs[i] = otp
+		outputs[i] = otp
 	}
-	return receipt, nil
+	return outputs
 }
@@ -32,10 +32,11 @@ func New(repo *chain.Repository, db *logdb.LogDB, logsLimit uint64) *Transfers {
 }
 
 // Filter query logs with option
-func (t *Transfers) filter(ctx context.Context, filter *TransferFilter) ([]*FilteredTransfer, error) {
+// The cursor of the last transfer is returned for keyset pagination, nil if no transfer matched.
+func (t *Transfers) filter(ctx context.Context, filter *TransferFilter) ([]*FilteredTransfer, *logdb.LogCursor, error) {
 	rng, err := events.ConvertRange(t.repo.NewBestChain(), filter.Range)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	transfers, err := t.db.FilterTransfers(ctx, &logdb.TransferFilter{
@@ -45,13 +46,19 @@ func (t *Transfers) filter(ctx context.Context, filter *TransferFilter) ([]*Filt
 		Order:       filter.Order,
 	})
 	if err != nil {
-		return nil, err
+		if err == logdb.ErrQueryQueueTimeout {
+			return nil, nil, utils.HTTPError(err, http.StatusServiceUnavailable)
+		}
+		return nil, nil, err
 	}
 	tLogs := make([]*FilteredTransfer, len(transfers))
 	for i, trans := range transfers {
 		tLogs[i] = convertTransfer(trans)
 	}
-	return tLogs, nil
+	if len(transfers) == 0 {
+		return tLogs, nil, nil
+	}
+	return tLogs, transfers[len(transfers)-1].Cursor(), nil
 }
 
 func (t *Transfers) handleFilterTransferLogs(w http.ResponseWriter, req *http.Request) error {
@@ -69,10 +76,13 @@ func (t *Transfers) handleFilterTransferLogs(w http.ResponseWriter, req *http.Re
 		}
 	}
 
-	tLogs, err := t.filter(req.Context(), &filter)
+	tLogs, cursor, err := t.filter(req.Context(), &filter)
 	if err != nil {
 		return err
 	}
+	if cursor != nil {
+		w.Header().Set(utils.LogsCursorHeader, cursor.String())
+	}
 	return utils.WriteJSON(w, tLogs)
 }
 
@@ -9,6 +9,8 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+
+	"github.com/pkg/errors"
 )
 
 type httpError struct {
@@ -56,7 +58,12 @@ func WrapHandlerFunc(f HandlerFunc) http.HandlerFunc {
 		if err != nil {
 			if he, ok := err.(*httpError); ok {
 				if he.cause != nil {
-					http.Error(w, he.cause.Error(), he.status)
+					status := he.status
+					// the body exceeds the limit set by http.MaxBytesReader
+					if _, ok := errors.Cause(he.cause).(*http.MaxBytesError); ok {
+						status = http.StatusRequestEntityTooLarge
+					}
+					http.Error(w, he.cause.Error(), status)
 				} else {
 					w.WriteHeader(he.status)
 				}
@@ -72,6 +79,10 @@ const (
 	JSONContentType = "application/json; charset=utf-8"
 )
 
+// LogsCursorHeader is the response header of logs APIs, carrying the cursor of the last log in the page,
+// to be passed as options.after to get the next page.
+const LogsCursorHeader = "x-logs-cursor"
+
 // ParseJSON parse a JSON object using strict mode.
 func ParseJSON(r io.Reader, v interface{}) error {
 	decoder := json.NewDecoder(r)
@@ -31,6 +31,12 @@ func (rev *Revision) IsNext() bool {
 	return rev.val == revNext
 }
 
+// IsBlockID returns whether the revision is a block ID, which may refer to a block not on the best chain.
+func (rev *Revision) IsBlockID() bool {
+	_, ok := rev.val.(thor.Bytes32)
+	return ok
+}
+
 // ParseRevision parses a query parameter into a block number or block ID.
 func ParseRevision(revision string, allowNext bool) (*Revision, error) {
 	if revision == "" || revision == "best" {
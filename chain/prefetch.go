@@ -0,0 +1,123 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain
+
+import (
+	"sync"
+)
+
+const (
+	// prefetchMinStreak is the count of consecutive block numbers accessed to be taken as sequential access.
+	prefetchMinStreak = 3
+	// MaxPrefetchBlocks is the max count of blocks to read ahead, bounded by the size of the summaries cache,
+	// so that the prefetched blocks are not evicted before being accessed.
+	MaxPrefetchBlocks = 256
+)
+
+// prefetcher detects sequential block access, and warms caches for the next blocks on the best chain
+// in the background, including block summaries, txs, receipts and the trie nodes of the block index.
+// Header and tx IDs are computed as well.
+type prefetcher struct {
+	repo *Repository
+	n    uint32
+
+	lock   sync.Mutex
+	last   uint32        // number of the last accessed block
+	streak int           // count of consecutive block numbers accessed, up to last
+	ahead  uint32        // number of the last block prefetched for the current streak
+	done   chan struct{} // closed once the running prefetch finished, nil if not running
+}
+
+// SetPrefetch enables read-ahead of n blocks on sequential block access, or disables it if n is 0.
+// n is capped to MaxPrefetchBlocks. It should be called before the repository is being accessed.
+func (r *Repository) SetPrefetch(n uint32) {
+	if n == 0 {
+		r.prefetcher = nil
+		return
+	}
+	if n > MaxPrefetchBlocks {
+		n = MaxPrefetchBlocks
+	}
+	r.prefetcher = &prefetcher{repo: r, n: n}
+}
+
+// access records the block of num being accessed, and starts prefetching if it's sequential access
+// and the prefetched blocks are less than half of the read-ahead window.
+func (p *prefetcher) access(num uint32) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	switch num {
+	case p.last:
+		// e.g. the summary loaded again for txs or receipts
+		return
+	case p.last + 1:
+		p.streak++
+	default:
+		p.streak, p.ahead = 0, 0
+	}
+	p.last = num
+
+	if p.streak < prefetchMinStreak || p.done != nil || p.ahead >= num+p.n/2 {
+		return
+	}
+	from, to := num+1, num+p.n
+	if p.ahead >= from {
+		from = p.ahead + 1
+	}
+	p.ahead = to
+	done := make(chan struct{})
+	p.done = done
+	go func() {
+		defer close(done)
+		p.warm(from, to)
+
+		p.lock.Lock()
+		p.done = nil
+		p.lock.Unlock()
+	}()
+}
+
+// warm loads blocks in range [from, to] of the best chain into caches. It stops at the first error,
+// e.g. beyond the best block.
+func (p *prefetcher) warm(from, to uint32) {
+	var (
+		r     = p.repo
+		chain = r.NewBestChain()
+	)
+	for num := from; num <= to; num++ {
+		id, err := chain.GetBlockID(num)
+		if err != nil {
+			return
+		}
+		cached, err := r.caches.summaries.GetOrLoad(id, func() (interface{}, error) {
+			return loadBlockSummary(r.data, id)
+		})
+		if err != nil {
+			return
+		}
+		summary := cached.(*BlockSummary)
+		// IDs are cached once computed, which involve the costly signer recovery
+		summary.Header.ID()
+
+		var (
+			txKey      = makeTxKey(id, txInfix)
+			receiptKey = makeTxKey(id, receiptInfix)
+		)
+		for i := range summary.Txs {
+			txKey.SetIndex(uint64(i))
+			trx, err := r.getTransaction(txKey)
+			if err != nil {
+				return
+			}
+			trx.ID()
+			receiptKey.SetIndex(uint64(i))
+			if _, err := r.getReceipt(receiptKey); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// newPrefetchTestDB creates a db with blocks of the given count on the best chain, each with txsPerBlock txs.
+func newPrefetchTestDB(tb testing.TB, db *muxdb.MuxDB, blocks, txsPerBlock int) *block.Block {
+	b0 := new(block.Builder).ParentID(thor.Bytes32{0xff, 0xff, 0xff, 0xff}).Build()
+	repo, err := NewRepository(db, b0)
+	assert.Nil(tb, err)
+
+	pk, _ := crypto.GenerateKey()
+	parent := b0
+	nonce := uint64(0)
+	for i := 0; i < blocks; i++ {
+		var (
+			builder  = new(block.Builder).ParentID(parent.Header().ID()).Timestamp(parent.Header().Timestamp() + 10)
+			receipts tx.Receipts
+		)
+		for j := 0; j < txsPerBlock; j++ {
+			nonce++
+			trx := new(tx.Builder).Nonce(nonce).Clause(tx.NewClause(nil).WithData(make([]byte, 100))).Build()
+			sig, _ := crypto.Sign(trx.SigningHash().Bytes(), pk)
+			builder.Transaction(trx.WithSignature(sig))
+			receipts = append(receipts, &tx.Receipt{Outputs: []*tx.Output{{Events: tx.Events{{Data: make([]byte, 100)}}}}})
+		}
+		b := builder.Build()
+		sig, _ := crypto.Sign(b.Header().SigningHash().Bytes(), pk)
+		b = b.WithSignature(sig)
+
+		assert.Nil(tb, repo.AddBlock(b, receipts, 0))
+		assert.Nil(tb, repo.SetBestBlockID(b.Header().ID()))
+		parent = b
+	}
+	return b0
+}
+
+func TestPrefetch(t *testing.T) {
+	db := muxdb.NewMem()
+	b0 := newPrefetchTestDB(t, db, 50, 2)
+
+	repo, err := NewRepository(db, b0)
+	assert.Nil(t, err)
+	repo.SetPrefetch(16)
+
+	isCached := func(num uint32) bool {
+		id, err := repo.NewBestChain().GetBlockID(num)
+		assert.Nil(t, err)
+		return repo.caches.summaries.Contains(id)
+	}
+	waitIdle := func() {
+		repo.prefetcher.lock.Lock()
+		done := repo.prefetcher.done
+		repo.prefetcher.lock.Unlock()
+		if done != nil {
+			<-done
+		}
+	}
+
+	// random access not prefetched
+	for _, num := range []uint32{10, 3, 12, 5} {
+		_, err := repo.NewBestChain().GetBlock(num)
+		assert.Nil(t, err)
+	}
+	waitIdle()
+	assert.False(t, isCached(13))
+	assert.False(t, isCached(6))
+
+	// sequential access
+	chain := repo.NewBestChain()
+	for num := uint32(20); num <= 23; num++ {
+		blk, err := chain.GetBlock(num)
+		assert.Nil(t, err)
+		_, err = repo.GetBlockReceipts(blk.Header().ID())
+		assert.Nil(t, err)
+	}
+	waitIdle()
+	assert.True(t, isCached(24))
+	assert.True(t, isCached(23+16))
+	assert.False(t, isCached(23+17))
+
+	// txs and receipts are warmed too
+	id, _ := chain.GetBlockID(30)
+	summary, _ := repo.GetBlockSummary(id)
+	for i := range summary.Txs {
+		key := makeTxKey(id, txInfix)
+		key.SetIndex(uint64(i))
+		assert.True(t, repo.caches.txs.Contains(key))
+		key = makeTxKey(id, receiptInfix)
+		key.SetIndex(uint64(i))
+		assert.True(t, repo.caches.receipts.Contains(key))
+	}
+
+	// stops at the best block
+	for num := uint32(45); num <= 48; num++ {
+		_, err := chain.GetBlock(num)
+		assert.Nil(t, err)
+	}
+	waitIdle()
+	assert.True(t, isCached(49))
+
+	repo.SetPrefetch(0)
+	assert.Nil(t, repo.prefetcher)
+}
+
+// BenchmarkSequentialRead measures per-block latency of reading blocks and receipts sequentially from
+// cold caches, like an explorer backend does, which encodes each block and then waits on its own IO, e.g.
+// writing to its database. The wait is simulated by consumerDelay and excluded from the timing.
+func BenchmarkSequentialRead(b *testing.B) {
+	const (
+		blocks        = 1000
+		txsPerBlock   = 10
+		consumerDelay = 100 * time.Microsecond
+	)
+	db, err := muxdb.Open(b.TempDir(), &muxdb.Options{
+		TrieNodeCacheSizeMB:        16,
+		TrieRootCacheCapacity:      256,
+		TrieCachedNodeTTL:          30,
+		TrieLeafBankSlotCapacity:   256,
+		TrieDedupedPartitionFactor: math.MaxUint32,
+		TrieHistPartitionFactor:    1000,
+		OpenFilesCacheCapacity:     64,
+		ReadCacheMB:                1,
+		WriteBufferMB:              4,
+	})
+	assert.Nil(b, err)
+	defer db.Close()
+	b0 := newPrefetchTestDB(b, db, blocks, txsPerBlock)
+
+	for _, prefetch := range []uint32{0, 64} {
+		name := "no-prefetch"
+		if prefetch > 0 {
+			name = "prefetch"
+		}
+		b.Run(name, func(b *testing.B) {
+			var (
+				repo  *Repository
+				chain *Chain
+			)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				num := uint32(i%blocks) + 1
+				if num == 1 {
+					// reopen for cold caches
+					b.StopTimer()
+					repo, err = NewRepository(db, b0)
+					assert.Nil(b, err)
+					repo.SetPrefetch(prefetch)
+					chain = repo.NewBestChain()
+					b.StartTimer()
+				}
+				blk, err := chain.GetBlock(num)
+				if err != nil {
+					b.Fatal(err)
+				}
+				receipts, err := repo.GetBlockReceipts(blk.Header().ID())
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := rlp.EncodeToBytes(blk); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := rlp.EncodeToBytes(receipts); err != nil {
+					b.Fatal(err)
+				}
+				for _, trx := range blk.Transactions() {
+					trx.ID()
+				}
+
+				b.StopTimer()
+				time.Sleep(consumerDelay)
+				b.StartTimer()
+			}
+		})
+	}
+}
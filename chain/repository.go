@@ -53,6 +53,7 @@ type Repository struct {
 		txs       *cache
 		receipts  *cache
 	}
+	prefetcher *prefetcher
 }
 
 // NewRepository create an instance of repository.
@@ -125,6 +126,12 @@ func (r *Repository) ChainTag() byte {
 	return r.tag
 }
 
+// OverrideChainTag overrides the chain tag derived from the genesis id, to isolate chains sharing the same genesis.
+// It should be called before the repository is used.
+func (r *Repository) OverrideChainTag(tag byte) {
+	r.tag = tag
+}
+
 // GenesisBlock returns genesis block.
 func (r *Repository) GenesisBlock() *block.Block {
 	return r.genesis
@@ -328,7 +335,11 @@ func (r *Repository) GetBlockSummary(id thor.Bytes32) (summary *BlockSummary, er
 	}); err != nil {
 		return
 	}
-	return cached.(*BlockSummary), nil
+	summary = cached.(*BlockSummary)
+	if r.prefetcher != nil {
+		r.prefetcher.access(summary.Header.Number())
+	}
+	return summary, nil
 }
 
 func (r *Repository) getTransaction(key txKey) (*tx.Transaction, error) {
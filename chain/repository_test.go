@@ -99,6 +99,14 @@ func TestRepository(t *testing.T) {
 	}
 }
 
+func TestOverrideChainTag(t *testing.T) {
+	_, repo := newTestRepo()
+
+	tag := repo.GenesisBlock().Header().ID()[31] + 1
+	repo.OverrideChainTag(tag)
+	assert.Equal(t, tag, repo.ChainTag())
+}
+
 func TestConflicts(t *testing.T) {
 	_, repo := newTestRepo()
 	b0 := repo.GenesisBlock()
@@ -0,0 +1,94 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/cmd/thor/p2p"
+	"github.com/vechain/thor/v2/p2psrv"
+)
+
+// loadAllowedPeersFile loads node IDs from the file, one per line. Empty lines and lines
+// starting with '#' are ignored.
+func loadAllowedPeersFile(path string) ([]*discover.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read allowed peers file")
+	}
+	var nodes []*discover.Node
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		node, err := discover.ParseNode(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse allowed peer [%v]", line)
+		}
+		nodes = append(nodes, node)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "read allowed peers file")
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New("no allowed peers in file")
+	}
+	return nodes, nil
+}
+
+// watchAllowedPeersFile reloads the allowed peers from the file on SIGHUP until the returned stop func called.
+// The current allowed peers are kept if the reload fails.
+func watchAllowedPeersFile(path string, p2pCommunicator *p2p.P2P) (stop func()) {
+	reload := func() error {
+		nodes, err := loadAllowedPeersFile(path)
+		if err != nil {
+			return err
+		}
+		added, removed, err := p2pCommunicator.SetAllowedPeers(nodes)
+		if err != nil {
+			return err
+		}
+		log.Info("allowed peers reloaded", "added", nodeIDs(added), "removed", nodeIDs(removed))
+		return nil
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sigCh:
+				if err := reload(); err != nil {
+					log.Warn("failed to reload allowed peers, keep using the current ones", "err", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
+
+func nodeIDs(nodes p2psrv.Nodes) []string {
+	ids := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.ID.TerminalString())
+	}
+	return ids
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/bft"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/consensus"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+	"gopkg.in/cheggaaa/pb.v1"
+)
+
+// chainFileEntry is the item of a chain file, which is a sequence of RLP encoded entries in block number order.
+type chainFileEntry struct {
+	Block    *block.Block
+	Receipts tx.Receipts
+}
+
+// exportChain writes trunk blocks in range [from, to] along with their receipts to w.
+func exportChain(ctx context.Context, repo *chain.Repository, from, to uint32, w io.Writer) error {
+	pb := pb.New64(int64(to - from + 1)).
+		SetMaxWidth(90).
+		Start()
+	defer func() { pb.NotPrint = true }()
+
+	bw := bufio.NewWriter(w)
+	best := repo.NewBestChain()
+	for num := from; num <= to; num++ {
+		b, err := best.GetBlock(num)
+		if err != nil {
+			return errors.Wrapf(err, "get block #%v", num)
+		}
+		receipts, err := repo.GetBlockReceipts(b.Header().ID())
+		if err != nil {
+			return errors.Wrapf(err, "get receipts of block #%v", num)
+		}
+		if err := rlp.Encode(bw, &chainFileEntry{b, receipts}); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		pb.Add64(1)
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	pb.Finish()
+	return nil
+}
+
+// importChain appends blocks read from r onto the best chain. Each block is fully processed by consensus,
+// and must be a child of the best block. Already known blocks are skipped.
+// It returns the count of imported and skipped blocks.
+func importChain(
+	ctx context.Context,
+	repo *chain.Repository,
+	stater *state.Stater,
+	bftEngine *bft.BFTEngine,
+	forkConfig thor.ForkConfig,
+	r io.Reader,
+) (imported int, skipped int, err error) {
+	cons := consensus.New(repo, stater, forkConfig)
+	stream := rlp.NewStream(bufio.NewReader(r), 0)
+	for {
+		var entry chainFileEntry
+		if err := stream.Decode(&entry); err != nil {
+			if err == io.EOF {
+				return imported, skipped, nil
+			}
+			return imported, skipped, errors.Wrap(err, "decode chain file")
+		}
+		header := entry.Block.Header()
+
+		if _, err := repo.GetBlockSummary(header.ID()); err != nil {
+			if !repo.IsNotFound(err) {
+				return imported, skipped, err
+			}
+		} else {
+			skipped++
+			continue
+		}
+
+		best := repo.BestBlockSummary()
+		if header.ParentID() != best.Header.ID() {
+			return imported, skipped, fmt.Errorf("parent hash mismatch at block #%v, parent %v, best %v",
+				header.Number(), header.ParentID(), best.Header.ID())
+		}
+		if ok, err := bftEngine.Accepts(header.ParentID()); err != nil {
+			return imported, skipped, errors.Wrap(err, "bft accepts")
+		} else if !ok {
+			return imported, skipped, fmt.Errorf("block #%v rejected by bft", header.Number())
+		}
+		if entry.Receipts.RootHash() != header.ReceiptsRoot() {
+			return imported, skipped, fmt.Errorf("receipts mismatch at block #%v", header.Number())
+		}
+
+		conflicts, err := repo.ScanConflicts(header.Number())
+		if err != nil {
+			return imported, skipped, err
+		}
+		stage, receipts, err := cons.Process(best, entry.Block, uint64(time.Now().Unix()), conflicts)
+		if err != nil {
+			return imported, skipped, errors.Wrapf(err, "process block #%v", header.Number())
+		}
+		if _, err := stage.Commit(); err != nil {
+			return imported, skipped, errors.Wrap(err, "commit state")
+		}
+		if err := repo.AddBlock(entry.Block, receipts, conflicts); err != nil {
+			return imported, skipped, errors.Wrap(err, "add block")
+		}
+		if header.Number() >= forkConfig.FINALITY {
+			if err := bftEngine.CommitBlock(header, false); err != nil {
+				return imported, skipped, errors.Wrap(err, "bft commits")
+			}
+		}
+		if err := repo.SetBestBlockID(header.ID()); err != nil {
+			return imported, skipped, err
+		}
+		imported++
+
+		select {
+		case <-ctx.Done():
+			return imported, skipped, ctx.Err()
+		default:
+		}
+	}
+}
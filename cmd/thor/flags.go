@@ -6,6 +6,8 @@
 package main
 
 import (
+	"time"
+
 	"github.com/inconshreveable/log15"
 	cli "gopkg.in/urfave/cli.v1"
 )
@@ -13,7 +15,7 @@ import (
 var (
 	networkFlag = cli.StringFlag{
 		Name:  "network",
-		Usage: "the network to join (main|test) or path to genesis file",
+		Usage: "the network to join (main|test) or path/URL to genesis file",
 	}
 	configDirFlag = cli.StringFlag{
 		Name:   "config-dir",
@@ -38,7 +40,20 @@ var (
 	apiAddrFlag = cli.StringFlag{
 		Name:  "api-addr",
 		Value: "localhost:8669",
-		Usage: "API service listening address",
+		Usage: "API service listening address, or unix:<path> to listen on a UNIX domain socket",
+	}
+	apiSocketModeFlag = cli.StringFlag{
+		Name:  "api-socket-mode",
+		Value: "0660",
+		Usage: "file permissions in octal of the API UNIX domain socket",
+	}
+	apiTLSCertFlag = cli.StringFlag{
+		Name:  "api-tls-cert",
+		Usage: "path to the TLS certificate file to serve API over HTTPS, reloaded on SIGHUP",
+	}
+	apiTLSKeyFlag = cli.StringFlag{
+		Name:  "api-tls-key",
+		Usage: "path to the TLS private key file to serve API over HTTPS, reloaded on SIGHUP",
 	}
 	apiCorsFlag = cli.StringFlag{
 		Name:  "api-cors",
@@ -50,11 +65,20 @@ var (
 		Value: 10000,
 		Usage: "API request timeout value in milliseconds",
 	}
+	apiShutdownTimeoutFlag = cli.DurationFlag{
+		Name:  "api-shutdown-timeout",
+		Value: 10 * time.Second,
+		Usage: "max time to wait for in-flight API requests on shutdown, before connections are force-closed",
+	}
 	apiCallGasLimitFlag = cli.Uint64Flag{
 		Name:  "api-call-gas-limit",
 		Value: 50000000,
 		Usage: "limit contract call gas",
 	}
+	apiTraceGasLimitFlag = cli.Uint64Flag{
+		Name:  "api-trace-gas-limit",
+		Usage: "limit gas of debug trace calls and bundle simulations (default: same as api-call-gas-limit)",
+	}
 	apiBacktraceLimitFlag = cli.Uint64Flag{
 		Name:  "api-backtrace-limit",
 		Value: 1000,
@@ -64,11 +88,60 @@ var (
 		Name:  "api-allow-custom-tracer",
 		Usage: "allow custom JS tracer to be used tracer API",
 	}
+	disableJSTracerFlag = cli.BoolFlag{
+		Name:  "disable-js-tracer",
+		Usage: "disable JS tracers for tracer API, including custom ones, native tracers are still available",
+	}
 	apiLogsLimitFlag = cli.Uint64Flag{
 		Name:  "api-logs-limit",
 		Value: 1000,
 		Usage: "limit the number of logs returned by /logs API",
 	}
+	apiAccountsBatchLimitFlag = cli.Uint64Flag{
+		Name:  "api-accounts-batch-limit",
+		Value: 100,
+		Usage: "limit the number of accounts retrieved by a single /accounts/batch request (unlimited if 0)",
+	}
+	apiSubBufferFlag = cli.Uint64Flag{
+		Name:  "api-sub-buffer",
+		Value: 100,
+		Usage: "max count of messages queued for each subscription, the slow consumer is disconnected once exceeded",
+	}
+	apiMaxRequestBodyFlag = cli.Uint64Flag{
+		Name:  "api-max-request-body",
+		Value: 512 * 1024,
+		Usage: "max size of API request body in bytes (no limit if set to 0)",
+	}
+	apiIdempotencyTTLFlag = cli.DurationFlag{
+		Name:  "api-idempotency-ttl",
+		Value: 10 * time.Minute,
+		Usage: "how long outcomes of tx submissions with Idempotency-Key header are kept for replay (disabled if set to 0)",
+	}
+	apiDisableCompressionFlag = cli.BoolFlag{
+		Name:  "api-disable-compression",
+		Usage: "disable gzip/deflate compression of API responses, which applies to those larger than 1KB",
+	}
+	apiReadyMaxLagFlag = cli.Uint64Flag{
+		Name:  "api-ready-max-lag",
+		Value: 6,
+		Usage: "max blocks behind the network head for the node to be reported ready by /health/ready",
+	}
+	apiRateLimitFlag = cli.StringFlag{
+		Name:  "api-rate-limit",
+		Usage: "limit API requests per client IP, in form of <requests-per-second>[,<burst>] (disabled if not set)",
+	}
+	apiTrustedProxiesFlag = cli.StringFlag{
+		Name:  "api-trusted-proxies",
+		Usage: "comma separated IPs or CIDRs of reverse proxies, whose X-Forwarded-For header is trusted to identify clients for rate limiting",
+	}
+	apiAdminAllowlistFlag = cli.StringFlag{
+		Name:  "api-admin-allowlist",
+		Usage: "comma separated IPs or CIDRs allowed to access /debug (unrestricted if not set), /node/txpool and /node/production (loopback if not set) endpoints, others get 403",
+	}
+	apiDisableFlag = cli.StringSliceFlag{
+		Name:  "api-disable",
+		Usage: "disable an API endpoint group (accounts|logs|blocks|transactions|transactions-post|debug|node|subscriptions), can be repeated",
+	}
 	enableAPILogsFlag = cli.BoolFlag{
 		Name:  "enable-api-logs",
 		Usage: "enables API requests logging",
@@ -78,6 +151,10 @@ var (
 		Value: uint64(log15.LvlInfo),
 		Usage: "log verbosity (0-9)",
 	}
+	verbosityModuleFlag = cli.StringFlag{
+		Name:  "verbosity-module",
+		Usage: "comma separated per-module log verbosity over the global one, e.g. txpool=4,p2psrv=5",
+	}
 	maxPeersFlag = cli.Uint64Flag{
 		Name:  "max-peers",
 		Usage: "maximum number of P2P network peers (P2P network disabled if set to 0)",
@@ -88,6 +165,14 @@ var (
 		Value: 11235,
 		Usage: "P2P network listening port",
 	}
+	p2pAdvertisePortFlag = cli.Uint64Flag{
+		Name:  "p2p-advertise-port",
+		Usage: "P2P network port advertised to other nodes, if it differs from the listening port, e.g. with --nat extip:<IP> behind a load balancer (default: the listening port)",
+	}
+	p2pNoDiscoveryFlag = cli.BoolFlag{
+		Name:  "p2p-no-discovery",
+		Usage: "disable P2P node discovery, only dial bootnodes and cached peers",
+	}
 	natFlag = cli.StringFlag{
 		Name:  "nat",
 		Value: "any",
@@ -97,19 +182,45 @@ var (
 		Name:  "bootnode",
 		Usage: "comma separated list of bootstrap node IDs",
 	}
+	bootNodeWarnAfterFlag = cli.DurationFlag{
+		Name:  "bootnode-warn-after",
+		Value: 5 * time.Minute,
+		Usage: "warn if none of the bootnodes is reachable for the period (disabled if set to 0)",
+	}
 	allowedPeersFlag = cli.StringFlag{
 		Name:   "allowed-peers",
 		Hidden: true,
-		Usage:  "comma separated list of node IDs that can be connected to",
+		Usage:  "comma separated list of node IDs that can be connected to, with discovery disabled",
+	}
+	allowedPeersFileFlag = cli.StringFlag{
+		Name:   "allowed-peers-file",
+		Hidden: true,
+		Usage:  "path to the file of node IDs that can be connected to, one per line, reloaded on SIGHUP, unlike --allowed-peers inbound peers not listed are rejected",
 	}
 	importMasterKeyFlag = cli.BoolFlag{
 		Name:  "import",
 		Usage: "import master key from keystore",
 	}
+	importHexKeyFlag = cli.StringFlag{
+		Name:  "import-key",
+		Usage: "import master key from hex encoded private key ('-' to read from stdin)",
+	}
 	exportMasterKeyFlag = cli.BoolFlag{
 		Name:  "export",
 		Usage: "export master key to keystore",
 	}
+	jsonOutputFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "print output in JSON format",
+	}
+	exportFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "number of the first block to export",
+	}
+	exportToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "number of the last block to export (default: the best block)",
+	}
 	targetGasLimitFlag = cli.Uint64Flag{
 		Name:  "target-gas-limit",
 		Value: 0,
@@ -119,6 +230,10 @@ var (
 		Name:  "pprof",
 		Usage: "turn on go-pprof",
 	}
+	pprofAddrFlag = cli.StringFlag{
+		Name:  "pprof-addr",
+		Usage: "serve go-pprof on a separate listening address instead of the API, e.g. localhost:6060",
+	}
 	skipLogsFlag = cli.BoolFlag{
 		Name:  "skip-logs",
 		Usage: "skip writing event|transfer logs (/logs API will be disabled)",
@@ -128,15 +243,71 @@ var (
 		Usage:  "verify log db at startup",
 		Hidden: true,
 	}
+	repairLogsFlag = cli.BoolFlag{
+		Name:  "repair-logs",
+		Usage: "verify log db at startup, and rewind it to the last consistent block on mismatch",
+	}
+	logDBJournalModeFlag = cli.StringFlag{
+		Name:  "logdb-journal-mode",
+		Usage: "journal mode of log db (delete|truncate|persist|memory|wal|off)",
+		Value: "wal",
+	}
+	logDBSynchronousFlag = cli.StringFlag{
+		Name:  "logdb-synchronous",
+		Usage: "synchronous level of log db commits (off|normal|full|extra), normal may lose the latest logs on power loss",
+		Value: "normal",
+	}
+	logDBCacheSizeFlag = cli.IntFlag{
+		Name:  "logdb-cache-size",
+		Usage: "page cache size of log db connections, in pages if positive, or in KiB if negative (SQLite default if 0)",
+	}
+	logDBWriteRetriesFlag = cli.IntFlag{
+		Name:  "logdb-write-retries",
+		Usage: "max retries of log db writes failed because the db is busy or locked (no retry if 0)",
+		Value: 5,
+	}
+	logDBWriteRetryDelayFlag = cli.DurationFlag{
+		Name:  "logdb-write-retry-delay",
+		Usage: "delay before the first retry of a log db write, doubled for each next retry",
+		Value: 20 * time.Millisecond,
+	}
+	logDBMaintenanceIntervalFlag = cli.DurationFlag{
+		Name:  "logdb-maintenance-interval",
+		Usage: "interval to checkpoint and truncate the log db WAL in the background, and vacuum if needed (disabled if 0)",
+	}
+	logDBVacuumFreePagesFlag = cli.IntFlag{
+		Name:  "logdb-vacuum-free-pages",
+		Usage: "min number of free pages left by deletes to vacuum the log db at the maintenance, blocking writes meanwhile (disabled if 0)",
+	}
+	logsMaxConcurrentFlag = cli.IntFlag{
+		Name:  "logs-max-concurrent",
+		Usage: "max number of log queries running at the same time, excess ones queue until the api timeout (unlimited if 0)",
+	}
 	cacheFlag = cli.Uint64Flag{
 		Name:  "cache",
 		Usage: "megabytes of ram allocated to trie nodes cache",
 		Value: 4096,
 	}
+	cacheAccountsFlag = cli.Uint64Flag{
+		Name:  "cache-accounts",
+		Usage: "megabytes of the trie nodes cache dedicated to the accounts trie, taken from --cache (shared if 0)",
+	}
+	cacheStorageFlag = cli.Uint64Flag{
+		Name:  "cache-storage",
+		Usage: "megabytes of the trie nodes cache dedicated to storage tries, taken from --cache (shared if 0)",
+	}
+	archivePrefetchFlag = cli.Uint64Flag{
+		Name:  "archive-prefetch",
+		Usage: "number of blocks to read ahead in background on sequential historical block access, max 256 (disabled if 0)",
+	}
 	disablePrunerFlag = cli.BoolFlag{
 		Name:  "disable-pruner",
 		Usage: "disable state pruner to keep all history",
 	}
+	pruneIntervalFlag = cli.DurationFlag{
+		Name:  "prune-interval",
+		Usage: "minimum interval between two state prunes, e.g. 6h (no limit if set to 0)",
+	}
 	enableMetricsFlag = cli.BoolFlag{
 		Name:  "enable-metrics",
 		Usage: "enables metrics collection",
@@ -157,6 +328,10 @@ var (
 		Value: 10,
 		Usage: "choose a custom block interval for solo mode (seconds)",
 	}
+	blockIntervalJitterFlag = cli.Uint64Flag{
+		Name:  "block-interval-jitter",
+		Usage: "max random delay added to each block production in solo mode, less than block-interval (seconds)",
+	}
 	persistFlag = cli.BoolFlag{
 		Name:  "persist",
 		Usage: "blockchain data storage option, if set data will be saved to disk",
@@ -166,6 +341,10 @@ var (
 		Value: 40_000_000,
 		Usage: "block gas limit(adaptive if set to 0)",
 	}
+	gasLimitAdaptiveFlag = cli.BoolFlag{
+		Name:  "gas-limit-adaptive",
+		Usage: "adjust block gas limit per block by the bounded delta like the mainnet, towards gas-limit",
+	}
 	txPoolLimitFlag = cli.Uint64Flag{
 		Name:  "txpool-limit",
 		Value: 10000,
@@ -176,8 +355,29 @@ var (
 		Value: 16,
 		Usage: "set tx limit per account in pool",
 	}
+	txPoolMaxLifetimeFlag = cli.DurationFlag{
+		Name:  "txpool-max-lifetime",
+		Value: 20 * time.Minute,
+		Usage: "set max lifetime of tx in pool, parsed by time.ParseDuration (e.g. 20m, 1h30m)",
+	}
 	genesisFlag = cli.StringFlag{
 		Name:  "genesis",
-		Usage: "path to genesis file, if not set, the default devnet genesis will be used",
+		Usage: "path/URL to genesis file, if not set, the default devnet genesis will be used",
+	}
+	genesisHashFlag = cli.StringFlag{
+		Name:  "genesis-hash",
+		Usage: "the expected genesis block ID, startup aborts on mismatch",
+	}
+	genesisTimestampFlag = cli.Uint64Flag{
+		Name:  "genesis-timestamp",
+		Usage: "override the timestamp (unix seconds) of the default devnet genesis block",
+	}
+	chainTagFlag = cli.Uint64Flag{
+		Name:  "chain-tag",
+		Usage: "override the chain tag (0-255) derived from the genesis block ID, for replay protection between instances",
+	}
+	mintFlag = cli.StringSliceFlag{
+		Name:  "mint",
+		Usage: "pre-fund an account with VET and VTHO in the default devnet genesis as <address>:<amount>, amount in whole tokens, can be repeated",
 	}
 )
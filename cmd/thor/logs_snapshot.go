@@ -0,0 +1,136 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/logdb"
+)
+
+// logsSnapshotTailBlocks is the count of the newest blocks of a log db snapshot to be verified before import.
+const logsSnapshotTailBlocks = 1000
+
+// verifyLogsSnapshotTail verifies the newest block of the log db snapshot is on the best chain, and logs of
+// the last `tail` blocks up to it are consistent with the chain. It returns the number of the newest block.
+func verifyLogsSnapshotTail(ctx context.Context, repo *chain.Repository, snapshot *logdb.LogDB, tail uint32) (uint32, error) {
+	newestID, err := snapshot.NewestBlockID()
+	if err != nil {
+		return 0, err
+	}
+	newestNum := block.Number(newestID)
+	if newestNum == 0 {
+		return 0, errors.New("empty snapshot")
+	}
+
+	best := repo.NewBestChain()
+	if has, err := best.HasBlock(newestID); err != nil {
+		return 0, err
+	} else if !has {
+		return 0, fmt.Errorf("newest block %v of the snapshot not found on the best chain", newestID)
+	}
+
+	from := uint32(1)
+	if newestNum > tail {
+		from = newestNum - tail + 1
+	}
+	for num := from; num <= newestNum; num++ {
+		b, err := best.GetBlock(num)
+		if err != nil {
+			return 0, err
+		}
+		receipts, err := repo.GetBlockReceipts(b.Header().ID())
+		if err != nil {
+			return 0, err
+		}
+		rng := &logdb.Range{From: num, To: num}
+		events, err := snapshot.FilterEvents(ctx, &logdb.EventFilter{Range: rng})
+		if err != nil {
+			return 0, err
+		}
+		transfers, err := snapshot.FilterTransfers(ctx, &logdb.TransferFilter{Range: rng})
+		if err != nil {
+			return 0, err
+		}
+		if err := verifyLogDBPerBlock(b, receipts, events, transfers); err != nil {
+			if mismatch, ok := err.(*logsMismatchError); ok {
+				return 0, fmt.Errorf("logs inconsistent with the chain at block #%v", mismatch.blockNum)
+			}
+			return 0, err
+		}
+	}
+	return newestNum, nil
+}
+
+// importLogsSnapshot replaces the log db in instanceDir with the snapshot file at src, after its tail verified.
+// The snapshot is copied and verified aside, so the existing log db is untouched on failure.
+// It returns the number of the newest block of the snapshot.
+func importLogsSnapshot(ctx context.Context, repo *chain.Repository, src, instanceDir string) (uint32, error) {
+	var (
+		path    = filepath.Join(instanceDir, "logs.db")
+		tmpPath = path + ".import"
+	)
+	removeDBFiles := func(path string) error {
+		for _, p := range []string{path, path + "-wal", path + "-shm"} {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := removeDBFiles(tmpPath); err != nil {
+		return 0, err
+	}
+	if err := copyFile(src, tmpPath); err != nil {
+		return 0, errors.Wrap(err, "copy snapshot")
+	}
+
+	newestNum, err := func() (uint32, error) {
+		snapshot, err := logdb.New(tmpPath)
+		if err != nil {
+			return 0, errors.Wrap(err, "open snapshot")
+		}
+		defer snapshot.Close()
+		return verifyLogsSnapshotTail(ctx, repo, snapshot, logsSnapshotTailBlocks)
+	}()
+	if err != nil {
+		_ = removeDBFiles(tmpPath)
+		return 0, errors.Wrap(err, "verify snapshot")
+	}
+
+	if err := removeDBFiles(path); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, err
+	}
+	return newestNum, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
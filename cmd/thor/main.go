@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"time"
@@ -20,7 +21,11 @@ import (
 	"github.com/pborman/uuid"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/blocks"
+	"github.com/vechain/thor/v2/api/utils"
 	"github.com/vechain/thor/v2/bft"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/cmd/thor/node"
 	"github.com/vechain/thor/v2/cmd/thor/optimizer"
 	"github.com/vechain/thor/v2/cmd/thor/solo"
@@ -30,6 +35,7 @@ import (
 	"github.com/vechain/thor/v2/muxdb"
 	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tracers"
 	"github.com/vechain/thor/v2/txpool"
 	"gopkg.in/urfave/cli.v1"
 
@@ -52,6 +58,9 @@ var (
 	}
 )
 
+// the count of txs whose recovered signers are cached by the transactions API
+const apiSignerCacheSize = 8192
+
 func fullVersion() string {
 	versionMeta := "release"
 	if gitTag == "" {
@@ -68,32 +77,69 @@ func main() {
 		Copyright: fmt.Sprintf("2018-%s VeChain Foundation <https://vechain.org/>", copyrightYear),
 		Flags: []cli.Flag{
 			networkFlag,
+			genesisHashFlag,
 			configDirFlag,
 			masterKeyStdinFlag,
 			dataDirFlag,
 			cacheFlag,
+			cacheAccountsFlag,
+			cacheStorageFlag,
+			archivePrefetchFlag,
 			beneficiaryFlag,
 			targetGasLimitFlag,
 			apiAddrFlag,
+			apiSocketModeFlag,
+			apiTLSCertFlag,
+			apiTLSKeyFlag,
 			apiCorsFlag,
 			apiTimeoutFlag,
+			apiShutdownTimeoutFlag,
 			apiCallGasLimitFlag,
+			apiTraceGasLimitFlag,
 			apiBacktraceLimitFlag,
 			apiAllowCustomTracerFlag,
+			disableJSTracerFlag,
 			enableAPILogsFlag,
 			apiLogsLimitFlag,
+			apiAccountsBatchLimitFlag,
+			apiSubBufferFlag,
+			apiMaxRequestBodyFlag,
+			apiIdempotencyTTLFlag,
+			apiDisableCompressionFlag,
+			apiReadyMaxLagFlag,
+			apiRateLimitFlag,
+			apiTrustedProxiesFlag,
+			apiAdminAllowlistFlag,
+			apiDisableFlag,
 			verbosityFlag,
+			verbosityModuleFlag,
 			maxPeersFlag,
 			p2pPortFlag,
+			p2pAdvertisePortFlag,
+			p2pNoDiscoveryFlag,
 			natFlag,
 			bootNodeFlag,
+			bootNodeWarnAfterFlag,
 			allowedPeersFlag,
+			allowedPeersFileFlag,
 			skipLogsFlag,
+			logDBJournalModeFlag,
+			logDBSynchronousFlag,
+			logDBCacheSizeFlag,
+			logDBWriteRetriesFlag,
+			logDBWriteRetryDelayFlag,
+			logDBMaintenanceIntervalFlag,
+			logDBVacuumFreePagesFlag,
+			logsMaxConcurrentFlag,
 			pprofFlag,
+			pprofAddrFlag,
 			verifyLogsFlag,
+			repairLogsFlag,
 			disablePrunerFlag,
+			pruneIntervalFlag,
 			enableMetricsFlag,
 			metricsAddrFlag,
+			txPoolMaxLifetimeFlag,
 		},
 		Action: defaultAction,
 		Commands: []cli.Command{
@@ -102,27 +148,60 @@ func main() {
 				Usage: "client runs in solo mode for test & dev",
 				Flags: []cli.Flag{
 					genesisFlag,
+					genesisHashFlag,
+					genesisTimestampFlag,
+					mintFlag,
+					chainTagFlag,
 					dataDirFlag,
 					cacheFlag,
+					cacheAccountsFlag,
+					cacheStorageFlag,
 					apiAddrFlag,
+					apiSocketModeFlag,
+					apiTLSCertFlag,
+					apiTLSKeyFlag,
 					apiCorsFlag,
 					apiTimeoutFlag,
+					apiShutdownTimeoutFlag,
 					apiCallGasLimitFlag,
+					apiTraceGasLimitFlag,
 					apiBacktraceLimitFlag,
 					apiAllowCustomTracerFlag,
+					disableJSTracerFlag,
 					enableAPILogsFlag,
 					apiLogsLimitFlag,
+					apiAccountsBatchLimitFlag,
+					apiSubBufferFlag,
+					apiMaxRequestBodyFlag,
+					apiIdempotencyTTLFlag,
+					apiDisableCompressionFlag,
+					apiDisableFlag,
 					onDemandFlag,
 					blockInterval,
+					blockIntervalJitterFlag,
 					persistFlag,
 					gasLimitFlag,
+					gasLimitAdaptiveFlag,
 					verbosityFlag,
+					verbosityModuleFlag,
 					pprofFlag,
+					pprofAddrFlag,
 					verifyLogsFlag,
+					repairLogsFlag,
 					skipLogsFlag,
+					logDBJournalModeFlag,
+					logDBSynchronousFlag,
+					logDBCacheSizeFlag,
+					logDBWriteRetriesFlag,
+					logDBWriteRetryDelayFlag,
+					logDBMaintenanceIntervalFlag,
+					logDBVacuumFreePagesFlag,
+					logsMaxConcurrentFlag,
 					txPoolLimitFlag,
 					txPoolLimitPerAccountFlag,
+					txPoolMaxLifetimeFlag,
 					disablePrunerFlag,
+					pruneIntervalFlag,
 					enableMetricsFlag,
 					metricsAddrFlag,
 				},
@@ -134,10 +213,107 @@ func main() {
 				Flags: []cli.Flag{
 					configDirFlag,
 					importMasterKeyFlag,
+					importHexKeyFlag,
 					exportMasterKeyFlag,
+					jsonOutputFlag,
 				},
 				Action: masterKeyAction,
 			},
+			{
+				Name:      "genesis-hash",
+				Usage:     "compute the genesis block ID of a custom genesis file, without starting the node",
+				ArgsUsage: "<file>",
+				Flags: []cli.Flag{
+					jsonOutputFlag,
+				},
+				Action: genesisHashAction,
+			},
+			{
+				Name:  "db-compact",
+				Usage: "compact the main database to reclaim disk space, the node must be stopped",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					disablePrunerFlag,
+					verbosityFlag,
+				},
+				Action: dbCompactAction,
+			},
+			{
+				Name:  "verify-logs",
+				Usage: "verify the log db is consistent with the chain, without starting the node",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					disablePrunerFlag,
+					verbosityFlag,
+				},
+				Action: verifyLogsAction,
+			},
+			{
+				Name:      "inspect-block",
+				Usage:     "dump a block with its transactions and receipts in JSON, without starting the node",
+				ArgsUsage: "<block id or number>",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					disablePrunerFlag,
+					verbosityFlag,
+				},
+				Action: inspectBlockAction,
+			},
+			{
+				Name:      "export-chain",
+				Usage:     "export a range of trunk blocks along with receipts into a file, without starting the node",
+				ArgsUsage: "<file>",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					disablePrunerFlag,
+					verbosityFlag,
+					exportFromFlag,
+					exportToFlag,
+				},
+				Action: exportChainAction,
+			},
+			{
+				Name:      "import-chain",
+				Usage:     "import blocks exported by export-chain onto the best chain, the node must be stopped",
+				ArgsUsage: "<file>",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					cacheFlag,
+					cacheAccountsFlag,
+					cacheStorageFlag,
+					disablePrunerFlag,
+					verbosityFlag,
+				},
+				Action: importChainAction,
+			},
+			{
+				Name:      "export-logs",
+				Usage:     "export a snapshot of the log db into a file, to be imported by other nodes",
+				ArgsUsage: "<file>",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					verbosityFlag,
+				},
+				Action: exportLogsAction,
+			},
+			{
+				Name:      "import-logs",
+				Usage:     "replace the log db with a snapshot exported by export-logs, the node must be stopped",
+				ArgsUsage: "<file>",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					disablePrunerFlag,
+					verbosityFlag,
+				},
+				Action: importLogsAction,
+			},
 		},
 	}
 
@@ -157,6 +333,11 @@ func defaultAction(ctx *cli.Context) error {
 		return errors.Wrap(err, "parse verbosity flag")
 	}
 	initLogger(log15.Lvl(lvl))
+	moduleLevels, err := parseVerbosityModule(ctx.String(verbosityModuleFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse verbosity-module flag")
+	}
+	initModuleLogger(log15.Lvl(lvl), moduleLevels)
 
 	// enable metrics as soon as possible
 	metricsURL := ""
@@ -170,6 +351,18 @@ func defaultAction(ctx *cli.Context) error {
 		defer func() { log.Info("stopping metrics server..."); close() }()
 	}
 
+	pprofOn := ctx.Bool(pprofFlag.Name)
+	if addr := ctx.String(pprofAddrFlag.Name); addr != "" {
+		url, close, err := startPprofServer(addr)
+		if err != nil {
+			return fmt.Errorf("unable to start pprof server - %w", err)
+		}
+		// served separately, never on the API
+		pprofOn = false
+		log.Info("pprof server started", "url", url)
+		defer func() { log.Info("stopping pprof server..."); close() }()
+	}
+
 	gene, forkConfig, err := selectGenesis(ctx)
 	if err != nil {
 		return err
@@ -179,7 +372,7 @@ func defaultAction(ctx *cli.Context) error {
 		return err
 	}
 
-	mainDB, err := openMainDB(ctx, instanceDir)
+	mainDB, err := openMainDB(ctx, instanceDir, false)
 	if err != nil {
 		return err
 	}
@@ -187,7 +380,7 @@ func defaultAction(ctx *cli.Context) error {
 
 	skipLogs := ctx.Bool(skipLogsFlag.Name)
 
-	logDB, err := openLogDB(instanceDir)
+	logDB, err := openLogDB(ctx, instanceDir)
 	if err != nil {
 		return err
 	}
@@ -197,6 +390,11 @@ func defaultAction(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	prefetch := ctx.Uint64(archivePrefetchFlag.Name)
+	if prefetch > chain.MaxPrefetchBlocks {
+		return fmt.Errorf("invalid %s %d, should be 0-%d", archivePrefetchFlag.Name, prefetch, chain.MaxPrefetchBlocks)
+	}
+	repo.SetPrefetch(uint32(prefetch))
 
 	master, err := loadNodeMaster(ctx)
 	if err != nil {
@@ -206,12 +404,15 @@ func defaultAction(ctx *cli.Context) error {
 	printStartupMessage1(gene, repo, master, instanceDir, forkConfig)
 
 	if !skipLogs {
-		if err := syncLogDB(exitSignal, repo, logDB, ctx.Bool(verifyLogsFlag.Name)); err != nil {
+		if err := syncLogDB(exitSignal, repo, logDB, ctx.Bool(verifyLogsFlag.Name), ctx.Bool(repairLogsFlag.Name)); err != nil {
 			return err
 		}
 	}
 
 	txpoolOpt := defaultTxPoolOptions
+	if txpoolOpt.MaxLifetime, err = readTxPoolMaxLifetime(ctx, thor.BlockInterval); err != nil {
+		return err
+	}
 	txPool := txpool.New(repo, state.NewStater(mainDB), txpoolOpt)
 	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
 
@@ -225,23 +426,69 @@ func defaultAction(ctx *cli.Context) error {
 		return errors.Wrap(err, "init bft engine")
 	}
 
+	rateLimiter, err := parseAPIRateLimit(ctx.String(apiRateLimitFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse api-rate-limit flag")
+	}
+	trustedProxies, err := parseIPNets(ctx.String(apiTrustedProxiesFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse api-trusted-proxies flag")
+	}
+	adminAllowlist, err := parseIPNets(ctx.String(apiAdminAllowlistFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse api-admin-allowlist flag")
+	}
+
+	apiDisabledEndpoints := ctx.StringSlice(apiDisableFlag.Name)
+	if err := api.ValidateEndpointGroups(apiDisabledEndpoints); err != nil {
+		return errors.Wrap(err, "parse api-disable flag")
+	}
+	if ctx.Bool(disableJSTracerFlag.Name) {
+		tracers.DefaultDirectory.DisableJS()
+	}
+	apiSubBuffer, err := readIntFromUInt64Flag(ctx.Uint64(apiSubBufferFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse api-sub-buffer flag")
+	}
+	apiMaxRequestBody, err := readIntFromUInt64Flag(ctx.Uint64(apiMaxRequestBodyFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse api-max-request-body flag")
+	}
+
+	production := &node.Production{}
 	apiHandler, apiCloser := api.New(
 		repo,
 		state.NewStater(mainDB),
 		txPool,
 		logDB,
 		bftEngine,
-		p2pCommunicator.Communicator(),
+		p2pCommunicator,
 		forkConfig,
-		ctx.String(apiCorsFlag.Name),
-		uint32(ctx.Uint64(apiBacktraceLimitFlag.Name)),
-		ctx.Uint64(apiCallGasLimitFlag.Name),
-		ctx.Bool(pprofFlag.Name),
-		skipLogs,
-		ctx.Bool(apiAllowCustomTracerFlag.Name),
-		ctx.Bool(enableAPILogsFlag.Name),
-		ctx.Bool(enableMetricsFlag.Name),
-		ctx.Uint64(apiLogsLimitFlag.Name),
+		api.Options{
+			AllowedOrigins:     ctx.String(apiCorsFlag.Name),
+			BacktraceLimit:     uint32(ctx.Uint64(apiBacktraceLimitFlag.Name)),
+			CallGasLimit:       ctx.Uint64(apiCallGasLimitFlag.Name),
+			TraceGasLimit:      ctx.Uint64(apiTraceGasLimitFlag.Name),
+			PprofOn:            pprofOn,
+			SkipLogs:           skipLogs,
+			AllowCustomTracer:  ctx.Bool(apiAllowCustomTracerFlag.Name),
+			EnableReqLogger:    ctx.Bool(enableAPILogsFlag.Name),
+			EnableMetrics:      ctx.Bool(enableMetricsFlag.Name),
+			LogsLimit:          ctx.Uint64(apiLogsLimitFlag.Name),
+			RateLimiter:        rateLimiter,
+			TrustedProxies:     trustedProxies,
+			DisabledEndpoints:  apiDisabledEndpoints,
+			SubsMsgQueueSize:   apiSubBuffer,
+			BlockInterval:      thor.BlockInterval,
+			MaxRequestBody:     int64(apiMaxRequestBody),
+			SignerCacheSize:    apiSignerCacheSize,
+			IdempotencyTTL:     ctx.Duration(apiIdempotencyTTLFlag.Name),
+			DisableCompression: ctx.Bool(apiDisableCompressionFlag.Name),
+			ReadyMaxLag:        uint32(ctx.Uint64(apiReadyMaxLagFlag.Name)),
+			AccountsBatchLimit: ctx.Uint64(apiAccountsBatchLimitFlag.Name),
+			AdminAllowlist:     adminAllowlist,
+			Production:         production,
+		},
 	)
 	defer func() { log.Info("closing API..."); apiCloser() }()
 
@@ -257,8 +504,14 @@ func defaultAction(ctx *cli.Context) error {
 		return err
 	}
 	defer p2pCommunicator.Stop()
+	if path := ctx.String(allowedPeersFileFlag.Name); path != "" {
+		defer watchAllowedPeersFile(path, p2pCommunicator)()
+	}
 
-	optimizer := optimizer.New(mainDB, repo, !ctx.Bool(disablePrunerFlag.Name))
+	optimizer := optimizer.New(mainDB, repo, optimizer.Options{
+		Prune:            !ctx.Bool(disablePrunerFlag.Name),
+		MinPruneInterval: ctx.Duration(pruneIntervalFlag.Name),
+	})
 	defer func() { log.Info("stopping optimizer..."); optimizer.Stop() }()
 
 	return node.New(
@@ -272,7 +525,8 @@ func defaultAction(ctx *cli.Context) error {
 		p2pCommunicator.Communicator(),
 		ctx.Uint64(targetGasLimitFlag.Name),
 		skipLogs,
-		forkConfig).Run(exitSignal)
+		forkConfig,
+		production).Run(exitSignal)
 }
 
 func soloAction(ctx *cli.Context) error {
@@ -284,6 +538,11 @@ func soloAction(ctx *cli.Context) error {
 		return errors.Wrap(err, "parse verbosity flag")
 	}
 	initLogger(log15.Lvl(lvl))
+	moduleLevels, err := parseVerbosityModule(ctx.String(verbosityModuleFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse verbosity-module flag")
+	}
+	initModuleLogger(log15.Lvl(lvl), moduleLevels)
 
 	// enable metrics as soon as possible
 	metricsURL := ""
@@ -297,6 +556,18 @@ func soloAction(ctx *cli.Context) error {
 		defer func() { log.Info("stopping metrics server..."); close() }()
 	}
 
+	pprofOn := ctx.Bool(pprofFlag.Name)
+	if addr := ctx.String(pprofAddrFlag.Name); addr != "" {
+		url, close, err := startPprofServer(addr)
+		if err != nil {
+			return fmt.Errorf("unable to start pprof server - %w", err)
+		}
+		// served separately, never on the API
+		pprofOn = false
+		log.Info("pprof server started", "url", url)
+		defer func() { log.Info("stopping pprof server..."); close() }()
+	}
+
 	var (
 		gene       *genesis.Genesis
 		forkConfig thor.ForkConfig
@@ -304,15 +575,31 @@ func soloAction(ctx *cli.Context) error {
 
 	flagGenesis := ctx.String(genesisFlag.Name)
 	if flagGenesis == "" {
-		gene = genesis.NewDevnet()
+		mints, err := parseMints(ctx.StringSlice(mintFlag.Name))
+		if err != nil {
+			return errors.Wrap(err, "parse mint flag")
+		}
+		gene = genesis.NewDevnetWithConfig(genesis.DevConfig{
+			LaunchTime: ctx.Uint64(genesisTimestampFlag.Name),
+			Mints:      mints,
+		})
 		forkConfig = thor.ForkConfig{} // Devnet forks from the start
 	} else {
+		if ctx.IsSet(genesisTimestampFlag.Name) {
+			return fmt.Errorf("flag %s and %s are exclusive", genesisTimestampFlag.Name, genesisFlag.Name)
+		}
+		if ctx.IsSet(mintFlag.Name) {
+			return fmt.Errorf("flag %s and %s are exclusive", mintFlag.Name, genesisFlag.Name)
+		}
 		var err error
 		gene, forkConfig, err = parseGenesisFile(flagGenesis)
 		if err != nil {
 			return err
 		}
 	}
+	if err := verifyGenesisHash(ctx, gene); err != nil {
+		return err
+	}
 
 	var mainDB *muxdb.MuxDB
 	var logDB *logdb.LogDB
@@ -322,12 +609,12 @@ func soloAction(ctx *cli.Context) error {
 		if instanceDir, err = makeInstanceDir(ctx, gene); err != nil {
 			return err
 		}
-		if mainDB, err = openMainDB(ctx, instanceDir); err != nil {
+		if mainDB, err = openMainDB(ctx, instanceDir, false); err != nil {
 			return err
 		}
 		defer func() { log.Info("closing main database..."); mainDB.Close() }()
 
-		if logDB, err = openLogDB(instanceDir); err != nil {
+		if logDB, err = openLogDB(ctx, instanceDir); err != nil {
 			return err
 		}
 		defer func() { log.Info("closing log database..."); logDB.Close() }()
@@ -341,15 +628,31 @@ func soloAction(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if ctx.IsSet(chainTagFlag.Name) {
+		chainTag := ctx.Uint64(chainTagFlag.Name)
+		if chainTag > math.MaxUint8 {
+			return fmt.Errorf("invalid %s %d, should be 0-255", chainTagFlag.Name, chainTag)
+		}
+		repo.OverrideChainTag(byte(chainTag))
+	}
 
 	skipLogs := ctx.Bool(skipLogsFlag.Name)
 
 	if !skipLogs {
-		if err := syncLogDB(exitSignal, repo, logDB, ctx.Bool(verifyLogsFlag.Name)); err != nil {
+		if err := syncLogDB(exitSignal, repo, logDB, ctx.Bool(verifyLogsFlag.Name), ctx.Bool(repairLogsFlag.Name)); err != nil {
 			return err
 		}
 	}
 
+	blockInterval := ctx.Uint64(blockInterval.Name)
+	if blockInterval == 0 {
+		return errors.New("block-interval cannot be zero")
+	}
+	blockIntervalJitter := ctx.Uint64(blockIntervalJitterFlag.Name)
+	if blockIntervalJitter >= blockInterval {
+		return errors.New("block-interval-jitter should be less than block-interval")
+	}
+
 	txPoolOption := defaultTxPoolOptions
 	txPoolOption.Limit, err = readIntFromUInt64Flag(ctx.Uint64(txPoolLimitFlag.Name))
 	if err != nil {
@@ -359,11 +662,31 @@ func soloAction(ctx *cli.Context) error {
 	if err != nil {
 		return errors.Wrap(err, "parse txpool-limit-per-account flag")
 	}
+	if txPoolOption.MaxLifetime, err = readTxPoolMaxLifetime(ctx, blockInterval); err != nil {
+		return err
+	}
 
 	txPool := txpool.New(repo, state.NewStater(mainDB), txPoolOption)
 	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
 
+	apiDisabledEndpoints := ctx.StringSlice(apiDisableFlag.Name)
+	if err := api.ValidateEndpointGroups(apiDisabledEndpoints); err != nil {
+		return errors.Wrap(err, "parse api-disable flag")
+	}
+	if ctx.Bool(disableJSTracerFlag.Name) {
+		tracers.DefaultDirectory.DisableJS()
+	}
+	apiSubBuffer, err := readIntFromUInt64Flag(ctx.Uint64(apiSubBufferFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse api-sub-buffer flag")
+	}
+	apiMaxRequestBody, err := readIntFromUInt64Flag(ctx.Uint64(apiMaxRequestBodyFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse api-max-request-body flag")
+	}
+
 	bftEngine := solo.NewBFTEngine(repo)
+	// solo mode is not rate limited, has no network head to lag behind and its block production is not switchable
 	apiHandler, apiCloser := api.New(
 		repo,
 		state.NewStater(mainDB),
@@ -372,15 +695,26 @@ func soloAction(ctx *cli.Context) error {
 		bftEngine,
 		&solo.Communicator{},
 		forkConfig,
-		ctx.String(apiCorsFlag.Name),
-		uint32(ctx.Uint64(apiBacktraceLimitFlag.Name)),
-		ctx.Uint64(apiCallGasLimitFlag.Name),
-		ctx.Bool(pprofFlag.Name),
-		skipLogs,
-		ctx.Bool(apiAllowCustomTracerFlag.Name),
-		ctx.Bool(enableAPILogsFlag.Name),
-		ctx.Bool(enableMetricsFlag.Name),
-		ctx.Uint64(apiLogsLimitFlag.Name),
+		api.Options{
+			AllowedOrigins:     ctx.String(apiCorsFlag.Name),
+			BacktraceLimit:     uint32(ctx.Uint64(apiBacktraceLimitFlag.Name)),
+			CallGasLimit:       ctx.Uint64(apiCallGasLimitFlag.Name),
+			TraceGasLimit:      ctx.Uint64(apiTraceGasLimitFlag.Name),
+			PprofOn:            pprofOn,
+			SkipLogs:           skipLogs,
+			AllowCustomTracer:  ctx.Bool(apiAllowCustomTracerFlag.Name),
+			EnableReqLogger:    ctx.Bool(enableAPILogsFlag.Name),
+			EnableMetrics:      ctx.Bool(enableMetricsFlag.Name),
+			LogsLimit:          ctx.Uint64(apiLogsLimitFlag.Name),
+			DisabledEndpoints:  apiDisabledEndpoints,
+			SubsMsgQueueSize:   apiSubBuffer,
+			BlockInterval:      blockInterval,
+			MaxRequestBody:     int64(apiMaxRequestBody),
+			SignerCacheSize:    apiSignerCacheSize,
+			IdempotencyTTL:     ctx.Duration(apiIdempotencyTTLFlag.Name),
+			DisableCompression: ctx.Bool(apiDisableCompressionFlag.Name),
+			AccountsBatchLimit: ctx.Uint64(apiAccountsBatchLimitFlag.Name),
+		},
 	)
 	defer func() { log.Info("closing API..."); apiCloser() }()
 
@@ -393,50 +727,89 @@ func soloAction(ctx *cli.Context) error {
 		srvCloser()
 	}()
 
-	blockInterval := ctx.Uint64(blockInterval.Name)
-	if blockInterval == 0 {
-		return errors.New("block-interval cannot be zero")
-	}
-
 	printSoloStartupMessage(gene, repo, instanceDir, apiURL, forkConfig, metricsURL)
 
-	optimizer := optimizer.New(mainDB, repo, !ctx.Bool(disablePrunerFlag.Name))
+	optimizer := optimizer.New(mainDB, repo, optimizer.Options{
+		Prune:            !ctx.Bool(disablePrunerFlag.Name),
+		MinPruneInterval: ctx.Duration(pruneIntervalFlag.Name),
+	})
 	defer func() { log.Info("stopping optimizer..."); optimizer.Stop() }()
 
 	return solo.New(repo,
 		state.NewStater(mainDB),
 		logDB,
 		txPool,
-		ctx.Uint64(gasLimitFlag.Name),
-		ctx.Bool(onDemandFlag.Name),
-		skipLogs,
-		blockInterval,
-		forkConfig).Run(exitSignal)
+		solo.Options{
+			GasLimit:            ctx.Uint64(gasLimitFlag.Name),
+			GasLimitAdaptive:    ctx.Bool(gasLimitAdaptiveFlag.Name),
+			OnDemand:            ctx.Bool(onDemandFlag.Name),
+			SkipLogs:            skipLogs,
+			BlockInterval:       blockInterval,
+			BlockIntervalJitter: blockIntervalJitter,
+			ForkConfig:          forkConfig,
+		}).Run(exitSignal)
 }
 
 func masterKeyAction(ctx *cli.Context) error {
 	hasImportFlag := ctx.Bool(importMasterKeyFlag.Name)
+	hasImportKeyFlag := ctx.IsSet(importHexKeyFlag.Name)
 	hasExportFlag := ctx.Bool(exportMasterKeyFlag.Name)
+	jsonOutput := ctx.Bool(jsonOutputFlag.Name)
 	if hasImportFlag && hasExportFlag {
 		return fmt.Errorf("flag %s and %s are exclusive", importMasterKeyFlag.Name, exportMasterKeyFlag.Name)
 	}
+	if hasImportKeyFlag && (hasImportFlag || hasExportFlag) {
+		return fmt.Errorf("flag %s and %s|%s are exclusive", importHexKeyFlag.Name, importMasterKeyFlag.Name, exportMasterKeyFlag.Name)
+	}
 
 	keyPath, err := masterKeyPath(ctx)
 	if err != nil {
 		return err
 	}
 
+	if hasImportKeyFlag {
+		hexKey := ctx.String(importHexKeyFlag.Name)
+		if hexKey == "-" {
+			if !jsonOutput && isatty.IsTerminal(os.Stdin.Fd()) {
+				fmt.Println("Input hex private key (end with ^d):")
+			}
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+			hexKey = string(data)
+		}
+
+		key, err := parseHexPrivateKey(hexKey)
+		if err != nil {
+			return errors.WithMessage(err, "import-key")
+		}
+		if err := crypto.SaveECDSA(keyPath, key); err != nil {
+			return err
+		}
+		address := thor.Address(crypto.PubkeyToAddress(key.PublicKey))
+		if jsonOutput {
+			return printJSON(&masterKeyOutput{Address: address})
+		}
+		fmt.Println("Master key imported:", address)
+		return nil
+	}
+
 	if !hasImportFlag && !hasExportFlag {
 		masterKey, err := loadOrGeneratePrivateKey(keyPath)
 		if err != nil {
 			return err
 		}
-		fmt.Println("Master:", thor.Address(crypto.PubkeyToAddress(masterKey.PublicKey)))
+		address := thor.Address(crypto.PubkeyToAddress(masterKey.PublicKey))
+		if jsonOutput {
+			return printJSON(&masterKeyOutput{Address: address})
+		}
+		fmt.Println("Master:", address)
 		return nil
 	}
 
 	if hasImportFlag {
-		if isatty.IsTerminal(os.Stdin.Fd()) {
+		if !jsonOutput && isatty.IsTerminal(os.Stdin.Fd()) {
 			fmt.Println("Input JSON keystore (end with ^d):")
 		}
 		keyjson, err := io.ReadAll(os.Stdin)
@@ -460,6 +833,9 @@ func masterKeyAction(ctx *cli.Context) error {
 		if err := crypto.SaveECDSA(keyPath, key.PrivateKey); err != nil {
 			return err
 		}
+		if jsonOutput {
+			return printJSON(&masterKeyOutput{Address: thor.Address(key.Address)})
+		}
 		fmt.Println("Master key imported:", thor.Address(key.Address))
 		return nil
 	}
@@ -494,6 +870,12 @@ func masterKeyAction(ctx *cli.Context) error {
 		if err != nil {
 			return err
 		}
+		if jsonOutput {
+			return printJSON(&masterKeyOutput{
+				Keystore: keyjson,
+				Address:  thor.Address(crypto.PubkeyToAddress(masterKey.PublicKey)),
+			})
+		}
 		if isatty.IsTerminal(os.Stdout.Fd()) {
 			fmt.Println("=== JSON keystore ===")
 		}
@@ -502,3 +884,397 @@ func masterKeyAction(ctx *cli.Context) error {
 	}
 	return nil
 }
+
+func genesisHashAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return errors.New("genesis file required")
+	}
+	gene, _, err := parseGenesisFile(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	id := gene.ID()
+	chainTag := id[len(id)-1]
+	if ctx.Bool(jsonOutputFlag.Name) {
+		return printJSON(&genesisHashOutput{ID: id, ChainTag: chainTag})
+	}
+	fmt.Println("Genesis ID:", id)
+	fmt.Printf("Chain Tag: 0x%02x\n", chainTag)
+	return nil
+}
+
+func dbCompactAction(ctx *cli.Context) error {
+	lvl, err := readIntFromUInt64Flag(ctx.Uint64(verbosityFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse verbosity flag")
+	}
+	initLogger(log15.Lvl(lvl))
+
+	gene, _, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := instanceDirPath(ctx, gene)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(instanceDir, "main.db")
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("main database [%v] not found", path)
+		}
+		return err
+	}
+
+	before, err := dirSize(path)
+	if err != nil {
+		return err
+	}
+
+	mainDB, err := openMainDB(ctx, instanceDir, false)
+	if err != nil {
+		if isLockedErr(err) {
+			return fmt.Errorf("main database [%v] is locked, stop the running node first", path)
+		}
+		return err
+	}
+
+	fmt.Println(">> Compacting main database <<")
+	start := time.Now()
+	if err := mainDB.Compact(); err != nil {
+		mainDB.Close()
+		return errors.Wrap(err, "compact main database")
+	}
+	if err := mainDB.Close(); err != nil {
+		return errors.Wrap(err, "close main database")
+	}
+
+	after, err := dirSize(path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Compacted in %v, size: %v -> %v\n", time.Since(start).Round(time.Second), before, after)
+	return nil
+}
+
+func verifyLogsAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	lvl, err := readIntFromUInt64Flag(ctx.Uint64(verbosityFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse verbosity flag")
+	}
+	initLogger(log15.Lvl(lvl))
+
+	gene, _, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := instanceDirPath(ctx, gene)
+	if err != nil {
+		return err
+	}
+
+	mainDB, repo, err := openReadOnlyChain(ctx, gene, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer mainDB.Close()
+
+	logDB, err := openReadOnlyLogDB(instanceDir)
+	if err != nil {
+		return err
+	}
+	defer logDB.Close()
+
+	startPos, err := seekLogDBSyncPosition(repo, logDB)
+	if err != nil {
+		return errors.Wrap(err, "seek log db sync position")
+	}
+	if startPos > 0 {
+		if err := verifyLogDB(exitSignal, startPos-1, repo, logDB); err != nil {
+			if mismatch, ok := err.(*logsMismatchError); ok {
+				return fmt.Errorf("log db inconsistent with the chain at block #%v", mismatch.blockNum)
+			}
+			return errors.Wrap(err, "verify log db")
+		}
+	}
+
+	fmt.Printf("Verified %v blocks, log db is consistent with the chain\n", startPos)
+	if bestNum := repo.BestBlockSummary().Header.Number(); bestNum > startPos {
+		// these blocks are to be synced, or have no logs
+		fmt.Printf("%v blocks after the newest logs not verified\n", bestNum-startPos)
+	}
+	return nil
+}
+
+func inspectBlockAction(ctx *cli.Context) error {
+	lvl, err := readIntFromUInt64Flag(ctx.Uint64(verbosityFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse verbosity flag")
+	}
+	initLogger(log15.Lvl(lvl))
+
+	if ctx.NArg() != 1 {
+		return errors.New("block id or number required")
+	}
+	revision, err := utils.ParseRevision(ctx.Args().First(), false)
+	if err != nil {
+		return errors.Wrap(err, "parse block id or number")
+	}
+
+	gene, forkConfig, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := instanceDirPath(ctx, gene)
+	if err != nil {
+		return err
+	}
+
+	mainDB, repo, err := openReadOnlyChain(ctx, gene, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer mainDB.Close()
+
+	bftEngine, err := bft.NewEngine(repo, mainDB, forkConfig, thor.Address{})
+	if err != nil {
+		return errors.Wrap(err, "init bft engine")
+	}
+
+	summary, err := utils.GetSummary(revision, repo, bftEngine)
+	if err != nil {
+		if repo.IsNotFound(err) {
+			return fmt.Errorf("block [%v] not found", ctx.Args().First())
+		}
+		return errors.Wrap(err, "get block")
+	}
+	id := summary.Header.ID()
+
+	txs, err := repo.GetBlockTransactions(id)
+	if err != nil {
+		return errors.Wrap(err, "get transactions")
+	}
+	receipts, err := repo.GetBlockReceipts(id)
+	if err != nil {
+		return errors.Wrap(err, "get receipts")
+	}
+
+	trunkID, err := repo.NewBestChain().GetBlockID(summary.Header.Number())
+	if err != nil && !repo.IsNotFound(err) {
+		return errors.Wrap(err, "get trunk block id")
+	}
+	isTrunk := trunkID == id
+	isFinalized := isTrunk && block.Number(bftEngine.Finalized()) >= summary.Header.Number()
+
+	data, err := json.MarshalIndent(blocks.BuildJSONExpandedBlock(summary, txs, receipts, isTrunk, isFinalized), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func exportChainAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	lvl, err := readIntFromUInt64Flag(ctx.Uint64(verbosityFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse verbosity flag")
+	}
+	initLogger(log15.Lvl(lvl))
+
+	if ctx.NArg() != 1 {
+		return errors.New("output file required")
+	}
+
+	gene, _, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := instanceDirPath(ctx, gene)
+	if err != nil {
+		return err
+	}
+
+	mainDB, repo, err := openReadOnlyChain(ctx, gene, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer mainDB.Close()
+
+	bestNum := repo.BestBlockSummary().Header.Number()
+	from, to := ctx.Uint64(exportFromFlag.Name), uint64(bestNum)
+	if ctx.IsSet(exportToFlag.Name) {
+		to = ctx.Uint64(exportToFlag.Name)
+	}
+	if to > uint64(bestNum) {
+		return fmt.Errorf("flag %s exceeds the best block #%v", exportToFlag.Name, bestNum)
+	}
+	if from > to {
+		return fmt.Errorf("flag %s is greater than flag %s", exportFromFlag.Name, exportToFlag.Name)
+	}
+
+	path := ctx.Args().First()
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "create output file")
+	}
+	defer f.Close()
+
+	fmt.Printf(">> Exporting blocks #%v - #%v <<\n", from, to)
+	if err := exportChain(exitSignal, repo, uint32(from), uint32(to), f); err != nil {
+		return errors.Wrap(err, "export chain")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "close output file")
+	}
+	fmt.Printf("Exported %v blocks to %v\n", to-from+1, path)
+	return nil
+}
+
+func importChainAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	lvl, err := readIntFromUInt64Flag(ctx.Uint64(verbosityFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse verbosity flag")
+	}
+	initLogger(log15.Lvl(lvl))
+
+	if ctx.NArg() != 1 {
+		return errors.New("input file required")
+	}
+	f, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return errors.Wrap(err, "open input file")
+	}
+	defer f.Close()
+
+	gene, forkConfig, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return err
+	}
+
+	mainDB, err := openMainDB(ctx, instanceDir, false)
+	if err != nil {
+		if isLockedErr(err) {
+			return fmt.Errorf("main database [%v] is locked, stop the running node first", filepath.Join(instanceDir, "main.db"))
+		}
+		return err
+	}
+	defer mainDB.Close()
+
+	logDB, err := openLogDB(ctx, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer logDB.Close()
+
+	repo, err := initChainRepository(gene, mainDB, logDB)
+	if err != nil {
+		return err
+	}
+	bftEngine, err := bft.NewEngine(repo, mainDB, forkConfig, thor.Address{})
+	if err != nil {
+		return errors.Wrap(err, "init bft engine")
+	}
+
+	fmt.Println(">> Importing blocks <<")
+	// logs of imported blocks are synced by the node on next startup
+	imported, skipped, err := importChain(exitSignal, repo, state.NewStater(mainDB), bftEngine, forkConfig, f)
+	fmt.Printf("Imported %v blocks, skipped %v known blocks, best block #%v\n",
+		imported, skipped, repo.BestBlockSummary().Header.Number())
+	if err != nil {
+		return errors.Wrap(err, "import chain")
+	}
+	return nil
+}
+
+func exportLogsAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	lvl, err := readIntFromUInt64Flag(ctx.Uint64(verbosityFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse verbosity flag")
+	}
+	initLogger(log15.Lvl(lvl))
+
+	if ctx.NArg() != 1 {
+		return errors.New("output file required")
+	}
+	path := ctx.Args().First()
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("output file [%v] already exists", path)
+	}
+
+	gene, _, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := instanceDirPath(ctx, gene)
+	if err != nil {
+		return err
+	}
+	logDB, err := openReadOnlyLogDB(instanceDir)
+	if err != nil {
+		return err
+	}
+	defer logDB.Close()
+
+	fmt.Println(">> Exporting log db <<")
+	if err := logDB.Export(exitSignal, path); err != nil {
+		return errors.Wrap(err, "export log db")
+	}
+	newestID, err := logDB.NewestBlockID()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported logs up to block #%v to %v\n", block.Number(newestID), path)
+	return nil
+}
+
+func importLogsAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	lvl, err := readIntFromUInt64Flag(ctx.Uint64(verbosityFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "parse verbosity flag")
+	}
+	initLogger(log15.Lvl(lvl))
+
+	if ctx.NArg() != 1 {
+		return errors.New("input file required")
+	}
+
+	gene, _, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := instanceDirPath(ctx, gene)
+	if err != nil {
+		return err
+	}
+
+	// the read-only main db also ensures the node is stopped
+	mainDB, repo, err := openReadOnlyChain(ctx, gene, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer mainDB.Close()
+
+	fmt.Println(">> Importing log db snapshot <<")
+	newestNum, err := importLogsSnapshot(exitSignal, repo, ctx.Args().First(), instanceDir)
+	if err != nil {
+		return errors.Wrap(err, "import log db snapshot")
+	}
+	fmt.Printf("Imported logs up to block #%v, the rest will be synced on the next startup\n", newestNum)
+	return nil
+}
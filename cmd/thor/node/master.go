@@ -9,12 +9,16 @@ import (
 	"crypto/ecdsa"
 
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/vechain/thor/v2/packer"
 	"github.com/vechain/thor/v2/thor"
 )
 
 type Master struct {
 	PrivateKey  *ecdsa.PrivateKey
 	Beneficiary *thor.Address
+	// BeneficiaryFunc is optional, consulted when proposing each block. If it returns the zero address,
+	// it falls back to Beneficiary, or the endorsor of the master if Beneficiary is not set.
+	BeneficiaryFunc packer.BeneficiaryFunc
 }
 
 func (m *Master) Address() thor.Address {
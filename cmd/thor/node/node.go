@@ -58,6 +58,7 @@ type Node struct {
 	targetGasLimit uint64
 	skipLogs       bool
 	forkConfig     thor.ForkConfig
+	production     *Production
 
 	logDBFailed bool
 	bandwidth   bandwidth.Bandwidth
@@ -78,9 +79,13 @@ func New(
 	targetGasLimit uint64,
 	skipLogs bool,
 	forkConfig thor.ForkConfig,
+	production *Production,
 ) *Node {
+	p := packer.New(repo, stater, master.Address(), master.Beneficiary, forkConfig)
+	p.SetBeneficiaryFunc(master.BeneficiaryFunc)
+
 	return &Node{
-		packer:         packer.New(repo, stater, master.Address(), master.Beneficiary, forkConfig),
+		packer:         p,
 		cons:           consensus.New(repo, stater, forkConfig),
 		master:         master,
 		repo:           repo,
@@ -92,6 +97,7 @@ func New(
 		targetGasLimit: targetGasLimit,
 		skipLogs:       skipLogs,
 		forkConfig:     forkConfig,
+		production:     production,
 	}
 }
 
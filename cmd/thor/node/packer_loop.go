@@ -41,6 +41,15 @@ func (n *Node) packerLoop(ctx context.Context) {
 	n.packer.SetTargetGasLimit(n.targetGasLimit)
 
 	for {
+		if !n.production.Enabled() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+				continue
+			}
+		}
+
 		now := uint64(time.Now().Unix())
 
 		if n.targetGasLimit == 0 {
@@ -71,9 +80,15 @@ func (n *Node) packerLoop(ctx context.Context) {
 			authorized = true
 			log.Info("prepared to pack block")
 		}
+		if flow.IsBeforeParent() {
+			log.Warn("local time is before the best block, check the system clock", "now", now, "best", flow.ParentHeader().Timestamp())
+		}
 		log.Debug("scheduled to pack block", "after", time.Duration(flow.When()-now)*time.Second)
 
 		for {
+			if !n.production.Enabled() {
+				goto RE_SCHEDULE
+			}
 			if uint64(time.Now().Unix())+thor.BlockInterval/2 > flow.When() {
 				// time to pack block
 				// blockInterval/2 early to allow more time for processing txs
@@ -0,0 +1,30 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import "sync/atomic"
+
+// Production switches the block production of the node on and off, without stopping the process.
+// The zero value is enabled.
+type Production struct {
+	paused atomic.Bool
+}
+
+// Enabled returns whether the block production is enabled.
+func (p *Production) Enabled() bool {
+	return !p.paused.Load()
+}
+
+// SetEnabled pauses or resumes the block production.
+func (p *Production) SetEnabled(enabled bool) {
+	if p.paused.Swap(!enabled) == enabled {
+		if enabled {
+			log.Info("block production resumed")
+		} else {
+			log.Info("block production paused")
+		}
+	}
+}
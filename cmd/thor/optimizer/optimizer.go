@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/rlp"
@@ -30,26 +31,43 @@ const (
 	statusKey      = "status"
 )
 
+// Options options for the optimizer.
+type Options struct {
+	Prune            bool            // whether to prune tries
+	MinPruneInterval time.Duration   // the minimum interval between two prunes, 0 means no limit
+	Progress         chan<- Progress // optional, receives the progress after each prune, dropped if not ready to receive
+}
+
+// Progress is the progress of the optimizer.
+type Progress struct {
+	LastPrunedBlock uint32 // tries are pruned up to this block number (excluded)
+	NodesReclaimed  int    // count of trie nodes deleted by the last prune
+	Running         bool   // whether the optimizer is running
+}
+
 // Optimizer is a background task to optimize tries.
 type Optimizer struct {
-	db     *muxdb.MuxDB
-	repo   *chain.Repository
-	ctx    context.Context
-	cancel func()
-	goes   co.Goes
+	db       *muxdb.MuxDB
+	repo     *chain.Repository
+	opts     Options
+	ctx      context.Context
+	cancel   func()
+	goes     co.Goes
+	progress atomic.Value
 }
 
 // New creates and starts the optimizer.
-func New(db *muxdb.MuxDB, repo *chain.Repository, prune bool) *Optimizer {
+func New(db *muxdb.MuxDB, repo *chain.Repository, opts Options) *Optimizer {
 	ctx, cancel := context.WithCancel(context.Background())
 	o := &Optimizer{
 		db:     db,
 		repo:   repo,
+		opts:   opts,
 		ctx:    ctx,
 		cancel: cancel,
 	}
 	o.goes.Go(func() {
-		if err := o.loop(prune); err != nil {
+		if err := o.loop(); err != nil {
 			if err != context.Canceled && errors.Cause(err) != context.Canceled {
 				log.Warn("optimizer interrupted", "error", err)
 			}
@@ -58,6 +76,25 @@ func New(db *muxdb.MuxDB, repo *chain.Repository, prune bool) *Optimizer {
 	return o
 }
 
+// Status returns the current progress of the optimizer.
+func (p *Optimizer) Status() Progress {
+	if progress, ok := p.progress.Load().(Progress); ok {
+		return progress
+	}
+	return Progress{}
+}
+
+// setProgress updates the progress and notifies the progress channel if any.
+func (p *Optimizer) setProgress(progress Progress) {
+	p.progress.Store(progress)
+	if p.opts.Progress != nil {
+		select {
+		case p.opts.Progress <- progress:
+		default:
+		}
+	}
+}
+
 // Stop stops the optimizer.
 func (p *Optimizer) Stop() {
 	p.cancel()
@@ -65,7 +102,7 @@ func (p *Optimizer) Stop() {
 }
 
 // loop is the main loop.
-func (p *Optimizer) loop(prune bool) error {
+func (p *Optimizer) loop() error {
 	log.Info("optimizer started")
 
 	const (
@@ -83,6 +120,13 @@ func (p *Optimizer) loop(prune bool) error {
 		return errors.Wrap(err, "load status")
 	}
 
+	progress := Progress{LastPrunedBlock: status.PruneBase, Running: true}
+	p.setProgress(progress)
+	defer func() {
+		progress.Running = false
+		p.setProgress(progress)
+	}()
+
 	for {
 		// select target
 		target := status.Base + period
@@ -99,12 +143,19 @@ func (p *Optimizer) loop(prune bool) error {
 		}
 
 		// prune index/account/storage tries
-		if prune && target > pruneReserved {
+		// it's deferred to later rounds if the last prune is too recent
+		if p.opts.Prune && target > pruneReserved && status.pruneDue(time.Now(), p.opts.MinPruneInterval) {
 			if pruneTarget := target - pruneReserved; pruneTarget >= status.PruneBase+prunePeriod {
-				if err := p.pruneTries(targetChain, status.PruneBase, pruneTarget); err != nil {
+				reclaimed, err := p.pruneTries(targetChain, status.PruneBase, pruneTarget)
+				if err != nil {
 					return errors.Wrap(err, "prune tries")
 				}
 				status.PruneBase = pruneTarget
+				status.LastPruneTime = time.Now().Unix()
+
+				progress.LastPrunedBlock = pruneTarget
+				progress.NodesReclaimed = reclaimed
+				p.setProgress(progress)
 			}
 		}
 
@@ -218,10 +269,10 @@ func (p *Optimizer) dumpTrieNodes(targetChain *chain.Chain, base, target uint32)
 	return nil
 }
 
-// pruneTries prunes index/account/storage tries in the range [base, target).
-func (p *Optimizer) pruneTries(targetChain *chain.Chain, base, target uint32) error {
+// pruneTries prunes index/account/storage tries in the range [base, target), and returns the count of deleted nodes.
+func (p *Optimizer) pruneTries(targetChain *chain.Chain, base, target uint32) (int, error) {
 	if err := p.dumpTrieNodes(targetChain, base, target); err != nil {
-		return errors.Wrap(err, "dump trie nodes")
+		return 0, errors.Wrap(err, "dump trie nodes")
 	}
 
 	cleanBase := base
@@ -229,10 +280,11 @@ func (p *Optimizer) pruneTries(targetChain *chain.Chain, base, target uint32) er
 		// keeps genesis state history like the previous version.
 		cleanBase = 1
 	}
-	if err := p.db.CleanTrieHistory(p.ctx, cleanBase, target); err != nil {
-		return errors.Wrap(err, "clean trie history")
+	n, err := p.db.CleanTrieHistory(p.ctx, cleanBase, target)
+	if err != nil {
+		return 0, errors.Wrap(err, "clean trie history")
 	}
-	return nil
+	return n, nil
 }
 
 // awaitUntilSteady waits until the target block number becomes almost final(steady),
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -103,6 +104,28 @@ func TestStatus(t *testing.T) {
 	assert.Equal(t, uint32(2), s.PruneBase)
 }
 
+func TestStatusPruneDue(t *testing.T) {
+	store := muxdb.NewMem().NewStore("test")
+	now := time.Now()
+
+	// never pruned
+	s := &status{}
+	assert.True(t, s.pruneDue(now, time.Hour))
+
+	s.LastPruneTime = now.Unix()
+	assert.Nil(t, s.Save(store))
+
+	// the last prune time survives restarts
+	s2 := &status{}
+	assert.Nil(t, s2.Load(store))
+	assert.Equal(t, now.Unix(), s2.LastPruneTime)
+
+	// a second prune inside the interval is skipped
+	assert.False(t, s2.pruneDue(now.Add(30*time.Minute), time.Hour))
+	assert.True(t, s2.pruneDue(now.Add(time.Hour), time.Hour))
+	assert.True(t, s2.pruneDue(now, 0))
+}
+
 func TestNewOptimizer(t *testing.T) {
 	log15.Root().SetHandler(log15.DiscardHandler())
 
@@ -112,7 +135,7 @@ func TestNewOptimizer(t *testing.T) {
 	b0, _, _, _ := gene.Build(stater)
 	repo, _ := chain.NewRepository(db, b0)
 
-	op := New(db, repo, false)
+	op := New(db, repo, Options{})
 	op.Stop()
 }
 
@@ -181,7 +204,7 @@ func TestProcessDump(t *testing.T) {
 
 	repo.SetBestBlockID(parentID)
 
-	op := New(db, repo, false)
+	op := New(db, repo, Options{})
 	op.Stop()
 
 	var s status
@@ -203,12 +226,18 @@ func TestProcessDump(t *testing.T) {
 	}
 	repo.SetBestBlockID(parentID)
 
-	op = New(db, repo, true)
+	progress := make(chan Progress, 2)
+	op = New(db, repo, Options{Prune: true, Progress: progress})
 	op.Stop()
 
 	assert.Nil(t, s.Load(op.db.NewStore(propsStoreName)))
 	assert.Equal(t, uint32(4000), s.Base)
 
+	// not pruned yet, since it's within the reserved range
+	assert.Equal(t, Progress{Running: true}, <-progress)
+	assert.Equal(t, Progress{Running: false}, <-progress)
+	assert.Equal(t, Progress{Running: false}, op.Status())
+
 	closeDB()
 }
 
@@ -341,7 +370,7 @@ func TestDumpAndPrune(t *testing.T) {
 	err = op.dumpStateLeaves(repo.NewBestChain(), 0, block.Number(parentID)+1)
 	assert.Nil(t, err)
 
-	err = op.pruneTries(repo.NewBestChain(), 0, block.Number(parentID)+1)
+	_, err = op.pruneTries(repo.NewBestChain(), 0, block.Number(parentID)+1)
 	assert.Nil(t, err)
 
 	closeDB()
@@ -7,13 +7,20 @@ package optimizer
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/vechain/thor/v2/kv"
 )
 
 type status struct {
-	Base      uint32
-	PruneBase uint32
+	Base          uint32
+	PruneBase     uint32
+	LastPruneTime int64 // unix timestamp of the last prune, kept so that restarts don't reset the prune interval
+}
+
+// pruneDue returns whether the given interval has elapsed since the last prune.
+func (s *status) pruneDue(now time.Time, minInterval time.Duration) bool {
+	return now.Sub(time.Unix(s.LastPruneTime, 0)) >= minInterval
 }
 
 func (s *status) Load(getter kv.Getter) error {
@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -32,14 +34,18 @@ func New(
 	communicator *comm.Communicator,
 	privateKey *ecdsa.PrivateKey,
 	instanceDir string,
-	nat nat.Interface,
+	natIface nat.Interface,
 	version string,
 	maxPeers int,
 	listenPort int,
+	advertisedPort int,
 	listenAddr string,
 	allowedPeers []*discover.Node,
+	rejectUnallowed bool,
 	cachedPeers []*discover.Node,
 	bootstrapNodes []*discover.Node,
+	noDiscovery bool,
+	bootstrapWarnAfter time.Duration,
 ) *P2P {
 	// known peers will be loaded/stored from/in this file
 	peersCachePath := filepath.Join(instanceDir, "peers.cache")
@@ -54,7 +60,8 @@ func New(
 		ListenAddr:          listenAddr,
 		DiscoveryNodes:      fallbackDiscoveryNodes,
 		RemoteDiscoveryList: remoteDiscoveryNodesList,
-		NAT:                 nat,
+		NAT:                 natIface,
+		AdvertisedPort:      advertisedPort,
 	}
 
 	// allowed peers flag will only allow p2psrv to connect to the designated peers
@@ -62,26 +69,56 @@ func New(
 		opts.NoDiscovery = true // disable discovery
 		opts.DiscoveryNodes = nil
 		opts.KnownNodes = allowedPeers
+		if rejectUnallowed {
+			// inbound peers not in the list are rejected too, and the list can be replaced later
+			opts.AllowedNodes = allowedPeers
+		}
 	} else {
 		// bootstrap nodes flag will overwrite the default discovery nodes and also disable remote discovery
 		if len(bootstrapNodes) > 0 {
 			opts.RemoteDiscoveryList = ""        // disable remote discovery
 			opts.DiscoveryNodes = bootstrapNodes // overwrite the default discovery nodes
 			opts.KnownNodes = bootstrapNodes     // supplied bootstrap nodes can potentially be p2p node, add to the known nodes
+			opts.TrackedNodes = bootstrapNodes   // track their health to diagnose connectivity
+			opts.TrackedNodesWarnAfter = bootstrapWarnAfter
 		}
 
 		// cached peers will be appended to existing or flag-set bootstrap nodes
 		if len(cachedPeers) > 0 {
 			opts.KnownNodes = dedupNodeSlice(opts.KnownNodes, cachedPeers)
 		}
+
+		// no discovery flag keeps dialing the bootstrap and cached nodes only
+		if noDiscovery {
+			opts.NoDiscovery = true
+			opts.DiscoveryNodes = nil
+			opts.RemoteDiscoveryList = ""
+		}
 	}
 
 	return &P2P{
 		comm:           communicator,
 		p2pSrv:         p2psrv.New(opts),
 		peersCachePath: peersCachePath,
-		enode:          fmt.Sprintf("enode://%x@[extip]:%v", discover.PubkeyID(&privateKey.PublicKey).Bytes(), listenPort),
+		enode:          makeEnode(privateKey, natIface, listenPort, advertisedPort),
+	}
+}
+
+// makeEnode makes the enode url advertised to other nodes. The external IP is only known in advance
+// if it's explicitly given by extip NAT, otherwise it's a placeholder.
+func makeEnode(privateKey *ecdsa.PrivateKey, natIface nat.Interface, listenPort, advertisedPort int) string {
+	id := discover.PubkeyID(&privateKey.PublicKey)
+	port := listenPort
+	if advertisedPort > 0 {
+		port = advertisedPort
+	}
+	// the extip NAT type is unexported, recognize it by its name, other NATs may block on ExternalIP
+	if natIface != nil && strings.HasPrefix(natIface.String(), "ExtIP(") {
+		if ip, err := natIface.ExternalIP(); err == nil {
+			return discover.NewNode(id, ip, uint16(port), uint16(port)).String()
+		}
 	}
+	return fmt.Sprintf("enode://%x@[extip]:%v", id.Bytes(), port)
 }
 
 func (p *P2P) Start() error {
@@ -112,6 +149,27 @@ func (p *P2P) Stop() {
 	}
 }
 
+// SetAllowedPeers replaces the allowed peers, if started with allowed peers.
+// It returns the added and removed peers.
+func (p *P2P) SetAllowedPeers(peers []*discover.Node) (added, removed p2psrv.Nodes, err error) {
+	return p.p2pSrv.SetAllowedNodes(peers)
+}
+
+// PeersStats returns the stats of connected peers.
+func (p *P2P) PeersStats() []*comm.PeerStats {
+	return p.comm.PeersStats()
+}
+
+// Synced returns a channel closed once the initial synchronization passed.
+func (p *P2P) Synced() <-chan struct{} {
+	return p.comm.Synced()
+}
+
+// BootstrapNodesHealth returns the connection health of the bootstrap nodes given by flag.
+func (p *P2P) BootstrapNodesHealth() []*p2psrv.NodeHealth {
+	return p.p2pSrv.TrackedNodes()
+}
+
 func (p *P2P) Communicator() *comm.Communicator {
 	return p.comm
 }
@@ -9,9 +9,14 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"fmt"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/p2p/nat"
 	"github.com/vechain/thor/v2/p2psrv"
 	"github.com/vechain/thor/v2/test/datagen"
 
@@ -28,8 +33,10 @@ func TestNewThorP2P(t *testing.T) {
 		maxPeers               int
 		listenAddr             string
 		allowedPeers           []*discover.Node
+		rejectUnallowed        bool
 		cachedPeers            []*discover.Node
 		bootstrapNodes         []*discover.Node
+		noDiscovery            bool
 		expectedKnownNodes     p2psrv.Nodes
 		expectedDiscoveryNodes p2psrv.Nodes
 	}{
@@ -52,6 +59,14 @@ func TestNewThorP2P(t *testing.T) {
 			bootstrapNodes:     []*discover.Node{{ID: discover.NodeID{200}}},
 			expectedKnownNodes: p2psrv.Nodes{{ID: discover.NodeID{1}}, {ID: discover.NodeID{2}}},
 		},
+		{
+			name:               "Instance with allowed peers rejecting unallowed",
+			maxPeers:           datagen.RandInt(),
+			listenAddr:         datagen.RandHostPort(),
+			allowedPeers:       []*discover.Node{{ID: discover.NodeID{1}}, {ID: discover.NodeID{2}}},
+			rejectUnallowed:    true,
+			expectedKnownNodes: p2psrv.Nodes{{ID: discover.NodeID{1}}, {ID: discover.NodeID{2}}},
+		},
 		{
 			name:                   "Cached peers append with default fallback nodes",
 			maxPeers:               datagen.RandInt(),
@@ -79,6 +94,16 @@ func TestNewThorP2P(t *testing.T) {
 			expectedDiscoveryNodes: []*discover.Node{{ID: discover.NodeID{3}}, {ID: discover.NodeID{33}}, {ID: discover.NodeID{33}}, {ID: discover.NodeID{3}}},
 			expectedKnownNodes:     p2psrv.Nodes{{ID: discover.NodeID{3}}, {ID: discover.NodeID{33}}, {ID: discover.NodeID{2}}, {ID: discover.NodeID{5}}},
 		},
+		{
+			name:                   "No discovery keeps bootstrap and cached nodes to dial",
+			maxPeers:               datagen.RandInt(),
+			listenAddr:             datagen.RandHostPort(),
+			cachedPeers:            []*discover.Node{{ID: discover.NodeID{2}}},
+			bootstrapNodes:         []*discover.Node{{ID: discover.NodeID{3}}},
+			noDiscovery:            true,
+			expectedDiscoveryNodes: nil,
+			expectedKnownNodes:     p2psrv.Nodes{{ID: discover.NodeID{3}}, {ID: discover.NodeID{2}}},
+		},
 	}
 
 	for _, tc := range tests {
@@ -92,17 +117,57 @@ func TestNewThorP2P(t *testing.T) {
 				"1.0",
 				tc.maxPeers,
 				123,
+				0,
 				tc.listenAddr,
 				tc.allowedPeers,
+				tc.rejectUnallowed,
 				tc.cachedPeers,
 				tc.bootstrapNodes,
+				tc.noDiscovery,
+				time.Minute,
 			)
 
 			assert.Equal(t, thor.p2pSrv.Options().KnownNodes, tc.expectedKnownNodes)
+			if tc.rejectUnallowed {
+				assert.Equal(t, p2psrv.Nodes(tc.allowedPeers), thor.p2pSrv.Options().AllowedNodes)
+			} else {
+				assert.Nil(t, thor.p2pSrv.Options().AllowedNodes)
+			}
 			assert.Equal(t, thor.p2pSrv.Options().DiscoveryNodes, tc.expectedDiscoveryNodes)
+			if len(tc.allowedPeers) == 0 {
+				assert.Equal(t, p2psrv.Nodes(tc.bootstrapNodes), thor.p2pSrv.Options().TrackedNodes)
+			}
 			assert.NotNil(t, thor, "P2P instance should not be nil")
+			assert.Equal(t, thor.p2pSrv.Options().NoDiscovery, tc.noDiscovery || len(tc.allowedPeers) > 0)
 			assert.Equal(t, thor.p2pSrv.Options().MaxPeers, tc.maxPeers)
 			assert.Equal(t, thor.p2pSrv.Options().ListenAddr, tc.listenAddr)
 		})
 	}
 }
+
+func TestEnode(t *testing.T) {
+	privateKey, _ := crypto.GenerateKey()
+	id := discover.PubkeyID(&privateKey.PublicKey)
+
+	tests := []struct {
+		name           string
+		nat            nat.Interface
+		advertisedPort int
+		expected       string
+	}{
+		{"No NAT", nil, 0, fmt.Sprintf("enode://%x@[extip]:11235", id.Bytes())},
+		{"Advertised port", nil, 30303, fmt.Sprintf("enode://%x@[extip]:30303", id.Bytes())},
+		{"Non extip NAT", nat.Any(), 0, fmt.Sprintf("enode://%x@[extip]:11235", id.Bytes())},
+		{"Extip NAT", nat.ExtIP(net.ParseIP("1.2.3.4")), 0, fmt.Sprintf("enode://%x@1.2.3.4:11235", id.Bytes())},
+		{"Extip NAT and advertised port", nat.ExtIP(net.ParseIP("1.2.3.4")), 30303, fmt.Sprintf("enode://%x@1.2.3.4:30303", id.Bytes())},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(nil, privateKey, "/tmp/thor-instance", tc.nat, "1.0", 25, 11235, tc.advertisedPort, ":11235", nil, false, nil, nil, false, 0)
+
+			assert.Equal(t, tc.expected, p.Enode())
+			assert.Equal(t, tc.advertisedPort, p.p2pSrv.Options().AdvertisedPort)
+		})
+	}
+}
@@ -44,26 +44,35 @@ type Solo struct {
 	txPool        *txpool.TxPool
 	packer        *packer.Packer
 	logDB         *logdb.LogDB
-	gasLimit      uint64
+	gasLimit      uint64 // 0 to let the packer adjust the gas limit per block
+	suggested     bool   // whether the target gas limit follows the bandwidth
 	bandwidth     bandwidth.Bandwidth
 	blockInterval uint64
+	jitter        uint64
 	onDemand      bool
 	skipLogs      bool
 }
 
+// Options options for solo.
+type Options struct {
+	GasLimit            uint64 // fixed block gas limit, or the target in adaptive mode. Adaptive to the bandwidth if 0
+	GasLimitAdaptive    bool   // adjust the gas limit per block by the bounded delta, as the mainnet does
+	OnDemand            bool
+	SkipLogs            bool
+	BlockInterval       uint64
+	BlockIntervalJitter uint64
+	ForkConfig          thor.ForkConfig
+}
+
 // New returns Solo instance
 func New(
 	repo *chain.Repository,
 	stater *state.Stater,
 	logDB *logdb.LogDB,
 	txPool *txpool.TxPool,
-	gasLimit uint64,
-	onDemand bool,
-	skipLogs bool,
-	blockInterval uint64,
-	forkConfig thor.ForkConfig,
+	opts Options,
 ) *Solo {
-	return &Solo{
+	s := &Solo{
 		repo:   repo,
 		stater: stater,
 		txPool: txPool,
@@ -72,13 +81,24 @@ func New(
 			stater,
 			genesis.DevAccounts()[0].Address,
 			&genesis.DevAccounts()[0].Address,
-			forkConfig),
+			opts.ForkConfig),
 		logDB:         logDB,
-		gasLimit:      gasLimit,
-		blockInterval: blockInterval,
-		skipLogs:      skipLogs,
-		onDemand:      onDemand,
+		blockInterval: opts.BlockInterval,
+		jitter:        opts.BlockIntervalJitter,
+		skipLogs:      opts.SkipLogs,
+		onDemand:      opts.OnDemand,
+	}
+
+	switch {
+	case opts.GasLimit == 0:
+		s.suggested = true
+	case opts.GasLimitAdaptive:
+		// the gas limit moves towards the target by the bounded delta per block, as the mainnet does
+		s.packer.SetTargetGasLimit(opts.GasLimit)
+	default:
+		s.gasLimit = opts.GasLimit
 	}
+	return s
 }
 
 // Run runs the packer for solo
@@ -103,14 +123,30 @@ func (s *Solo) Run(ctx context.Context) error {
 	return nil
 }
 
+// randJitter returns a random delay in seconds within [0, jitter].
+func (s *Solo) randJitter() uint64 {
+	if s.jitter == 0 {
+		return 0
+	}
+	return uint64(rand.Int63n(int64(s.jitter) + 1)) // nolint:gosec
+}
+
 func (s *Solo) loop(ctx context.Context) {
+	// the time to pack the block of the current interval, 0 if packed.
+	// timestamps keep increasing, since the jitter is less than the interval.
+	var packTime uint64
 	for {
 		select {
 		case <-ctx.Done():
 			log.Info("stopping interval packing service......")
 			return
 		case <-time.After(time.Duration(1) * time.Second):
-			if left := uint64(time.Now().Unix()) % s.blockInterval; left == 0 {
+			now := uint64(time.Now().Unix())
+			if now%s.blockInterval == 0 && packTime == 0 {
+				packTime = now + s.randJitter()
+			}
+			if packTime != 0 && now >= packTime {
+				packTime = 0
 				if err := s.packing(s.txPool.Executables(), false); err != nil {
 					log.Error("failed to pack block", "err", err)
 				}
@@ -137,9 +173,8 @@ func (s *Solo) packing(pendingTxs tx.Transactions, onDemand bool) error {
 		}
 	}()
 
-	if s.gasLimit == 0 {
-		suggested := s.bandwidth.SuggestGasLimit()
-		s.packer.SetTargetGasLimit(suggested)
+	if s.suggested {
+		s.packer.SetTargetGasLimit(s.bandwidth.SuggestGasLimit())
 	}
 
 	flow, err := s.packer.Mock(best, now, s.gasLimit)
@@ -247,7 +282,7 @@ func (s *Solo) init(ctx context.Context) error {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(time.Duration(int64(s.blockInterval)-time.Now().Unix()%int64(s.blockInterval)) * time.Second):
+		case <-time.After(time.Duration(int64(s.blockInterval)-time.Now().Unix()%int64(s.blockInterval)+int64(s.randJitter())) * time.Second):
 		}
 	}
 
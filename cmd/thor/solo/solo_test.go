@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/builtin"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/genesis"
@@ -21,7 +22,7 @@ import (
 	"github.com/vechain/thor/v2/txpool"
 )
 
-func newSolo() *Solo {
+func newSolo(opts Options) *Solo {
 	db := muxdb.NewMem()
 	stater := state.NewStater(db)
 	gene := genesis.NewDevnet()
@@ -30,11 +31,11 @@ func newSolo() *Solo {
 	repo, _ := chain.NewRepository(db, b)
 	mempool := txpool.New(repo, stater, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute})
 
-	return New(repo, stater, logDb, mempool, 0, true, false, thor.BlockInterval, thor.ForkConfig{})
+	return New(repo, stater, logDb, mempool, opts)
 }
 
 func TestInitSolo(t *testing.T) {
-	solo := newSolo()
+	solo := newSolo(Options{OnDemand: true, BlockInterval: thor.BlockInterval})
 
 	// init solo -> this should mine a block with the gas price tx
 	err := solo.init(context.Background())
@@ -47,3 +48,45 @@ func TestInitSolo(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, baseGasPrice, currentBGP)
 }
+
+func TestRandJitter(t *testing.T) {
+	solo := newSolo(Options{BlockInterval: thor.BlockInterval})
+	assert.Equal(t, uint64(0), solo.randJitter())
+
+	solo.jitter = 3
+	seen := make(map[uint64]bool)
+	for i := 0; i < 1000; i++ {
+		j := solo.randJitter()
+		assert.True(t, j <= solo.jitter)
+		seen[j] = true
+	}
+	assert.Equal(t, 4, len(seen))
+}
+
+func TestGasLimit(t *testing.T) {
+	pack := func(solo *Solo) *block.Header {
+		assert.Nil(t, solo.packing(nil, false))
+		return solo.repo.BestBlockSummary().Header
+	}
+	initial := thor.InitialGasLimit
+
+	// fixed
+	solo := newSolo(Options{GasLimit: 20_000_000})
+	assert.Equal(t, uint64(20_000_000), pack(solo).GasLimit())
+	assert.Equal(t, uint64(20_000_000), pack(solo).GasLimit())
+
+	// adaptive towards the target by the bounded delta
+	solo = newSolo(Options{GasLimit: 20_000_000, GasLimitAdaptive: true})
+	first := pack(solo)
+	assert.Equal(t, initial+initial/thor.GasLimitBoundDivisor, first.GasLimit())
+	assert.Equal(t, first.GasLimit()+first.GasLimit()/thor.GasLimitBoundDivisor, pack(solo).GasLimit())
+
+	// adaptive to the bandwidth
+	solo = newSolo(Options{})
+	best := pack(solo)
+	assert.Equal(t, initial, best.GasLimit())
+
+	heavy := new(block.Builder).GasLimit(best.GasLimit()).GasUsed(best.GasLimit()).Build().Header()
+	solo.bandwidth.Update(heavy, time.Millisecond)
+	assert.Equal(t, best.GasLimit()+best.GasLimit()/thor.GasLimitBoundDivisor, pack(solo).GasLimit())
+}
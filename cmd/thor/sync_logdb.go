@@ -22,14 +22,34 @@ import (
 	"gopkg.in/cheggaaa/pb.v1"
 )
 
-func syncLogDB(ctx context.Context, repo *chain.Repository, logDB *logdb.LogDB, verify bool) error {
+// logsMismatchError is returned when logs of a block are found inconsistent with the chain.
+type logsMismatchError struct {
+	blockNum uint32
+}
+
+func (e *logsMismatchError) Error() string {
+	return "incorrect logs"
+}
+
+// syncLogDB syncs the log db with the chain. If repair is set, the log db is verified, and
+// once mismatched logs are detected, it's truncated to the last consistent block and synced from there.
+func syncLogDB(ctx context.Context, repo *chain.Repository, logDB *logdb.LogDB, verify, repair bool) error {
 	startPos, err := seekLogDBSyncPosition(repo, logDB)
 	if err != nil {
 		return errors.Wrap(err, "seek log db sync position")
 	}
-	if verify && startPos > 0 {
+	if (verify || repair) && startPos > 0 {
 		if err := verifyLogDB(ctx, startPos-1, repo, logDB); err != nil {
-			return errors.Wrap(err, "verify log db")
+			mismatch, ok := err.(*logsMismatchError)
+			if !repair || !ok {
+				return errors.Wrap(err, "verify log db")
+			}
+			if err := repairLogDB(logDB, mismatch.blockNum); err != nil {
+				return errors.Wrap(err, "repair log db")
+			}
+			if startPos, err = seekLogDBSyncPosition(repo, logDB); err != nil {
+				return errors.Wrap(err, "seek log db sync position")
+			}
 		}
 	}
 
@@ -107,6 +127,33 @@ func syncLogDB(ctx context.Context, repo *chain.Repository, logDB *logdb.LogDB,
 	return pumpErr
 }
 
+// repairLogDB deletes logs since the given block number (included).
+func repairLogDB(logDB *logdb.LogDB, blockNum uint32) error {
+	before, err := logDB.Stats()
+	if err != nil {
+		return err
+	}
+
+	w := logDB.NewWriter()
+	if err := w.Truncate(blockNum); err != nil {
+		return err
+	}
+	if err := w.Commit(); err != nil {
+		return err
+	}
+
+	after, err := logDB.Stats()
+	if err != nil {
+		return err
+	}
+	log.Warn("log db rewound to last consistent block",
+		"block", blockNum-1,
+		"deletedEvents", before.EventCount-after.EventCount,
+		"deletedTransfers", before.TransferCount-after.TransferCount,
+	)
+	return nil
+}
+
 func seekLogDBSyncPosition(repo *chain.Repository, logDB *logdb.LogDB) (uint32, error) {
 	best := repo.BestBlockSummary().Header
 	if best.Number() == 0 {
@@ -331,12 +378,12 @@ func verifyLogDBPerBlock(
 	if !reflect.DeepEqual(eventLogs, expectedEvLogs) {
 		fmt.Println("\nDiff event logs")
 		fmt.Println(jsonDiff(expectedEvLogs, eventLogs))
-		return errors.New("incorrect logs")
+		return &logsMismatchError{n}
 	}
 	if !reflect.DeepEqual(transferLogs, expectedTrLogs) {
 		fmt.Println("\nDiff transfer logs")
 		fmt.Println(jsonDiff(expectedTrLogs, transferLogs))
-		return errors.New("incorrect logs")
+		return &logsMismatchError{n}
 	}
 	return nil
 }
@@ -7,21 +7,28 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/ecdsa"
+	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math"
+	"math/big"
 	"net"
 	"net/http"
+	"net/http/pprof"
 	"os"
 	"os/signal"
 	"os/user"
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -38,6 +45,7 @@ import (
 	"github.com/mattn/go-isatty"
 	"github.com/mattn/go-tty"
 	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/api"
 	"github.com/vechain/thor/v2/api/doc"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/cmd/thor/node"
@@ -60,6 +68,9 @@ import (
 
 var devNetGenesisID = genesis.NewDevnet().ID()
 
+// genesisFetchTimeout is the timeout of fetching the genesis file by URL.
+const genesisFetchTimeout = 30 * time.Second
+
 func initLogger(lvl log15.Lvl) {
 	log15.Root().SetHandler(log15.LvlFilterHandler(lvl, log15.StderrHandler))
 	// set go-ethereum log lvl to Warn
@@ -68,6 +79,68 @@ func initLogger(lvl log15.Lvl) {
 	ethlog.Root().SetHandler(ethLogHandler)
 }
 
+// logModules are the module names of loggers, which are set as the "pkg" context by log15.New.
+var logModules = []string{
+	"api", "comm", "logdb", "muxdb.trie", "node", "optimizer", "p2psrv", "rpc", "solo", "subscriptions", "txpool",
+}
+
+// parseVerbosityModule parses the comma separated per-module log levels, e.g. "txpool=4,p2psrv=5".
+func parseVerbosityModule(value string) (map[string]log15.Lvl, error) {
+	levels := make(map[string]log15.Lvl)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		module, lvlStr, ok := strings.Cut(item, "=")
+		module = strings.TrimSpace(module)
+		if !ok || module == "" {
+			return nil, fmt.Errorf("invalid item %q, should be <module>=<level>", item)
+		}
+		lvl, err := strconv.ParseUint(strings.TrimSpace(lvlStr), 10, 8)
+		if err != nil || lvl > 9 {
+			return nil, fmt.Errorf("invalid level %q of module %v, should be 0-9", lvlStr, module)
+		}
+		levels[module] = log15.Lvl(lvl)
+	}
+	return levels, nil
+}
+
+// initModuleLogger applies the per-module log levels over the global one, for loggers of the modules.
+// Unknown modules are warned, but not failed.
+func initModuleLogger(lvl log15.Lvl, levels map[string]log15.Lvl) {
+	if len(levels) == 0 {
+		return
+	}
+	log15.Root().SetHandler(log15.FilterHandler(func(r *log15.Record) bool {
+		maxLvl := lvl
+		for i := 0; i+1 < len(r.Ctx); i += 2 {
+			if r.Ctx[i] == "pkg" {
+				if module, ok := r.Ctx[i+1].(string); ok {
+					if l, ok := levels[module]; ok {
+						maxLvl = l
+					}
+				}
+				break
+			}
+		}
+		return r.Lvl <= maxLvl
+	}, log15.StderrHandler))
+
+	for module := range levels {
+		known := false
+		for _, m := range logModules {
+			if m == module {
+				known = true
+				break
+			}
+		}
+		if !known {
+			log.Warn("unknown log module", "module", module, "known", strings.Join(logModules, ","))
+		}
+	}
+}
+
 func loadOrGeneratePrivateKey(path string) (*ecdsa.PrivateKey, error) {
 	key, err := crypto.LoadECDSA(path)
 	if err == nil {
@@ -88,6 +161,26 @@ func loadOrGeneratePrivateKey(path string) (*ecdsa.PrivateKey, error) {
 	return key, nil
 }
 
+// parseHexPrivateKey parses a hex encoded secp256k1 private key, with optional 0x prefix.
+func parseHexPrivateKey(s string) (*ecdsa.PrivateKey, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "0x")
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 32 {
+		return nil, fmt.Errorf("invalid private key length %v, want 32 bytes", len(b))
+	}
+	key, err := crypto.ToECDSA(b)
+	if err != nil {
+		return nil, err
+	}
+	if !crypto.S256().IsOnCurve(key.PublicKey.X, key.PublicKey.Y) {
+		return nil, errors.New("invalid curve point")
+	}
+	return key, nil
+}
+
 func defaultConfigDir() string {
 	if home := homeDir(); home != "" {
 		return filepath.Join(home, ".org.vechain.thor")
@@ -121,6 +214,35 @@ func homeDir() string {
 	return ""
 }
 
+// expandPath expands env vars and the leading `~` or `~user` of the path, and makes it absolute.
+// An absolute path after expanding is returned as is.
+func expandPath(path string) (string, error) {
+	path = os.ExpandEnv(path)
+	if strings.HasPrefix(path, "~") {
+		name, rest := path[1:], ""
+		if i := strings.IndexAny(name, "/"+string(filepath.Separator)); i >= 0 {
+			name, rest = name[:i], name[i+1:]
+		}
+		var home string
+		if name == "" {
+			if home = homeDir(); home == "" {
+				return "", errors.New("unable to infer home dir")
+			}
+		} else {
+			usr, err := user.Lookup(name)
+			if err != nil {
+				return "", err
+			}
+			home = usr.HomeDir
+		}
+		path = filepath.Join(home, rest)
+	}
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	return filepath.Abs(path)
+}
+
 func handleExitSignal() context.Context {
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
@@ -134,14 +256,6 @@ func handleExitSignal() context.Context {
 	return ctx
 }
 
-// middleware to limit request body size.
-func requestBodyLimit(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		r.Body = http.MaxBytesReader(w, r.Body, 200*1024)
-		h.ServeHTTP(w, r)
-	})
-}
-
 // middleware to verify 'x-genesis-id' header in request, and set to response headers.
 func handleXGenesisID(h http.Handler, genesisID thor.Bytes32) http.Handler {
 	const headerKey = "x-genesis-id"
@@ -171,6 +285,18 @@ func handleXThorestVersion(h http.Handler) http.Handler {
 	})
 }
 
+// middleware to count in-flight requests, excluding upgraded ones like websocket subscriptions,
+// which are hijacked and not waited by http.Server.Shutdown.
+func handleInFlight(h http.Handler, inFlight *atomic.Int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") == "" {
+			inFlight.Add(1)
+			defer inFlight.Add(-1)
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
 // middleware for http request timeout.
 func handleAPITimeout(h http.Handler, timeout time.Duration) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -181,6 +307,28 @@ func handleAPITimeout(h http.Handler, timeout time.Duration) http.Handler {
 	})
 }
 
+// masterKeyOutput is the JSON output of master-key command.
+type masterKeyOutput struct {
+	Keystore json.RawMessage `json:"keystore,omitempty"`
+	Address  thor.Address    `json:"address"`
+}
+
+// genesisHashOutput is the JSON output of genesis-hash command.
+type genesisHashOutput struct {
+	ID       thor.Bytes32 `json:"id"`
+	ChainTag byte         `json:"chainTag"`
+}
+
+// printJSON prints v to stdout in JSON format.
+func printJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(string(data))
+	return err
+}
+
 func readPasswordFromNewTTY(prompt string) (string, error) {
 	t, err := tty.Open()
 	if err != nil {
@@ -202,26 +350,115 @@ func selectGenesis(ctx *cli.Context) (*genesis.Genesis, thor.ForkConfig, error)
 		return nil, thor.ForkConfig{}, errors.New("network flag not specified")
 	}
 
+	var (
+		gene       *genesis.Genesis
+		forkConfig thor.ForkConfig
+		err        error
+	)
 	switch network {
 	case "test":
-		gene := genesis.NewTestnet()
-		return gene, thor.GetForkConfig(gene.ID()), nil
+		gene = genesis.NewTestnet()
+		forkConfig = thor.GetForkConfig(gene.ID())
 	case "main":
-		gene := genesis.NewMainnet()
-		return gene, thor.GetForkConfig(gene.ID()), nil
+		gene = genesis.NewMainnet()
+		forkConfig = thor.GetForkConfig(gene.ID())
 	default:
-		return parseGenesisFile(network)
+		if gene, forkConfig, err = parseGenesisFile(network); err != nil {
+			return nil, thor.ForkConfig{}, err
+		}
 	}
+	if err := forkConfig.Validate(); err != nil {
+		return nil, thor.ForkConfig{}, errors.Wrap(err, "invalid fork config")
+	}
+	if err := verifyGenesisHash(ctx, gene); err != nil {
+		return nil, thor.ForkConfig{}, err
+	}
+	return gene, forkConfig, nil
+}
+
+// verifyGenesisHash checks the genesis ID against the genesis-hash flag if set.
+func verifyGenesisHash(ctx *cli.Context, gene *genesis.Genesis) error {
+	value := ctx.String(genesisHashFlag.Name)
+	if value == "" {
+		return nil
+	}
+	expected, err := thor.ParseBytes32(value)
+	if err != nil {
+		return errors.Wrap(err, "parse genesis-hash flag")
+	}
+	if gene.ID() != expected {
+		return fmt.Errorf("genesis hash mismatch, expected %v, got %v", expected, gene.ID())
+	}
+	return nil
 }
 
-func parseGenesisFile(filePath string) (*genesis.Genesis, thor.ForkConfig, error) {
-	file, err := os.Open(filePath)
+// duplicateForks returns the forks declared more than once in the forkConfig of the genesis file,
+// which would be silently overridden by the last declaration otherwise.
+func duplicateForks(data []byte) []string {
+	var gen struct {
+		ForkConfig json.RawMessage `json:"forkConfig"`
+	}
+	if err := json.Unmarshal(data, &gen); err != nil || len(gen.ForkConfig) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(gen.ForkConfig))
+	if tok, err := decoder.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	var (
+		seen = make(map[string]int)
+		dups []string
+	)
+	for decoder.More() {
+		tok, err := decoder.Token()
+		if err != nil {
+			return dups
+		}
+		key, _ := tok.(string)
+		// field names are matched case-insensitively by the json decoder
+		name := strings.ToUpper(key)
+		if seen[name]++; seen[name] == 2 {
+			dups = append(dups, name)
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return dups
+		}
+	}
+	return dups
+}
+
+// parseGenesisFile parses the genesis file from the local path, or fetches it if a http(s) URL given.
+func parseGenesisFile(uri string) (*genesis.Genesis, thor.ForkConfig, error) {
+	var reader io.Reader
+	if strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
+		client := &http.Client{Timeout: genesisFetchTimeout}
+		res, err := client.Get(uri)
+		if err != nil {
+			return nil, thor.ForkConfig{}, errors.Wrap(err, "fetch genesis file")
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return nil, thor.ForkConfig{}, fmt.Errorf("fetch genesis file: unexpected status %v", res.Status)
+		}
+		reader = res.Body
+	} else {
+		file, err := os.Open(uri)
+		if err != nil {
+			return nil, thor.ForkConfig{}, errors.Wrap(err, "open genesis file")
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	data, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, thor.ForkConfig{}, errors.Wrap(err, "open genesis file")
+		return nil, thor.ForkConfig{}, errors.Wrap(err, "read genesis file")
 	}
-	defer file.Close()
 
-	decoder := json.NewDecoder(file)
+	decoder := json.NewDecoder(bytes.NewReader(data))
 	decoder.DisallowUnknownFields()
 
 	var forkConfig = thor.NoFork
@@ -232,6 +469,10 @@ func parseGenesisFile(filePath string) (*genesis.Genesis, thor.ForkConfig, error
 		return nil, thor.ForkConfig{}, errors.Wrap(err, "decode genesis file")
 	}
 
+	if dups := duplicateForks(data); len(dups) > 0 {
+		return nil, thor.ForkConfig{}, fmt.Errorf("invalid fork config: duplicate activations of %v", strings.Join(dups, ", "))
+	}
+
 	customGen, err := genesis.NewCustomNet(&gen)
 	if err != nil {
 		return nil, thor.ForkConfig{}, errors.Wrap(err, "build genesis")
@@ -245,39 +486,60 @@ func makeConfigDir(ctx *cli.Context) (string, error) {
 	if dir == "" {
 		return "", fmt.Errorf("unable to infer default config dir, use -%s to specify", configDirFlag.Name)
 	}
+	dir, err := expandPath(dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "expand config dir [%v]", ctx.String(configDirFlag.Name))
+	}
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return "", errors.Wrapf(err, "create config dir [%v]", dir)
 	}
 	return dir, nil
 }
 
-func makeInstanceDir(ctx *cli.Context, gene *genesis.Genesis) (string, error) {
+func instanceDirPath(ctx *cli.Context, gene *genesis.Genesis) (string, error) {
 	dataDir := ctx.String(dataDirFlag.Name)
 	if dataDir == "" {
 		return "", fmt.Errorf("unable to infer default data dir, use -%s to specify", dataDirFlag.Name)
 	}
+	dataDir, err := expandPath(dataDir)
+	if err != nil {
+		return "", errors.Wrapf(err, "expand data dir [%v]", ctx.String(dataDirFlag.Name))
+	}
 
 	suffix := ""
 	if ctx.Bool(disablePrunerFlag.Name) {
 		suffix = "-full"
 	}
 
-	instanceDir := filepath.Join(dataDir, fmt.Sprintf("instance-%x-v3", gene.ID().Bytes()[24:])+suffix)
+	return filepath.Join(dataDir, fmt.Sprintf("instance-%x-v3", gene.ID().Bytes()[24:])+suffix), nil
+}
+
+func makeInstanceDir(ctx *cli.Context, gene *genesis.Genesis) (string, error) {
+	instanceDir, err := instanceDirPath(ctx, gene)
+	if err != nil {
+		return "", err
+	}
 	if err := os.MkdirAll(instanceDir, 0700); err != nil {
 		return "", errors.Wrapf(err, "create instance dir [%v]", instanceDir)
 	}
 	return instanceDir, nil
 }
 
-func openMainDB(ctx *cli.Context, dir string) (*muxdb.MuxDB, error) {
+func openMainDB(ctx *cli.Context, dir string, readOnly bool) (*muxdb.MuxDB, error) {
 	cacheMB := normalizeCacheSize(ctx.Int(cacheFlag.Name))
 	log.Debug("cache size(MB)", "size", cacheMB)
 
+	cachePartitions, err := trieCachePartitions(ctx, cacheMB)
+	if err != nil {
+		return nil, err
+	}
+
 	fdCache := suggestFDCache()
 	log.Debug("fd cache", "n", fdCache)
 
 	opts := muxdb.Options{
 		TrieNodeCacheSizeMB:        cacheMB,
+		TrieNodeCachePartitions:    cachePartitions,
 		TrieRootCacheCapacity:      256,
 		TrieCachedNodeTTL:          30, // 5min
 		TrieLeafBankSlotCapacity:   256,
@@ -286,6 +548,7 @@ func openMainDB(ctx *cli.Context, dir string) (*muxdb.MuxDB, error) {
 		OpenFilesCacheCapacity:     fdCache,
 		ReadCacheMB:                256, // rely on os page cache other than huge db read cache.
 		WriteBufferMB:              128,
+		ReadOnly:                   readOnly,
 	}
 
 	// go-ethereum stuff
@@ -310,6 +573,49 @@ func openMainDB(ctx *cli.Context, dir string) (*muxdb.MuxDB, error) {
 	return db, nil
 }
 
+// trieCachePartitions returns sizes(MB) of the trie nodes cache dedicated to the accounts trie and storage tries,
+// which are carved out of the total cacheMB. Tries without a dedicated part share the rest.
+func trieCachePartitions(ctx *cli.Context, cacheMB int) (map[string]int, error) {
+	var (
+		accountsMB = ctx.Int(cacheAccountsFlag.Name)
+		storageMB  = ctx.Int(cacheStorageFlag.Name)
+		partitions = make(map[string]int)
+	)
+	if accountsMB+storageMB > cacheMB {
+		return nil, fmt.Errorf("--%v and --%v (%vMB in total) exceed the cache size %vMB",
+			cacheAccountsFlag.Name, cacheStorageFlag.Name, accountsMB+storageMB, cacheMB)
+	}
+	if accountsMB > 0 {
+		partitions[state.AccountTrieName] = accountsMB
+	}
+	if storageMB > 0 {
+		partitions[state.StorageTrieNamePrefix] = storageMB
+	}
+	log.Debug("trie cache partitions(MB)", "accounts", accountsMB, "storage", storageMB)
+	return partitions, nil
+}
+
+// isLockedErr returns whether the error is caused by the db lock file held by another process.
+func isLockedErr(err error) bool {
+	errno, ok := errors.Cause(err).(syscall.Errno)
+	return ok && (errno == syscall.EAGAIN || errno == syscall.EWOULDBLOCK)
+}
+
+// dirSize returns the total size of files under the dir.
+func dirSize(dir string) (thor.StorageSize, error) {
+	var size int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return thor.StorageSize(size), err
+}
+
 func normalizeCacheSize(sizeMB int) int {
 	if sizeMB < 128 {
 		sizeMB = 128
@@ -353,9 +659,66 @@ func suggestFDCache() int {
 	return n
 }
 
-func openLogDB(dir string) (*logdb.LogDB, error) {
+// openReadOnlyChain opens the existing main database in read-only mode, along with the chain repository.
+func openReadOnlyChain(ctx *cli.Context, gene *genesis.Genesis, instanceDir string) (*muxdb.MuxDB, *chain.Repository, error) {
+	path := filepath.Join(instanceDir, "main.db")
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, fmt.Errorf("main database [%v] not found", path)
+		}
+		return nil, nil, err
+	}
+
+	mainDB, err := openMainDB(ctx, instanceDir, true)
+	if err != nil {
+		if isLockedErr(err) {
+			return nil, nil, fmt.Errorf("main database [%v] is locked, stop the running node first", path)
+		}
+		return nil, nil, err
+	}
+
+	// build genesis in memory, since the main database is read-only
+	genesisBlock, _, _, err := gene.Build(state.NewStater(muxdb.NewMem()))
+	if err != nil {
+		mainDB.Close()
+		return nil, nil, errors.Wrap(err, "build genesis block")
+	}
+	repo, err := chain.NewRepository(mainDB, genesisBlock)
+	if err != nil {
+		mainDB.Close()
+		return nil, nil, errors.Wrap(err, "initialize block chain")
+	}
+	return mainDB, repo, nil
+}
+
+func openLogDB(ctx *cli.Context, dir string) (*logdb.LogDB, error) {
 	path := filepath.Join(dir, "logs.db")
-	db, err := logdb.New(path)
+	db, err := logdb.NewWithConfig(path, logdb.Config{
+		JournalMode:          ctx.String(logDBJournalModeFlag.Name),
+		Synchronous:          ctx.String(logDBSynchronousFlag.Name),
+		CacheSize:            ctx.Int(logDBCacheSizeFlag.Name),
+		MaxConcurrentQueries: ctx.Int(logsMaxConcurrentFlag.Name),
+		WriteRetries:         ctx.Int(logDBWriteRetriesFlag.Name),
+		WriteRetryDelay:      ctx.Duration(logDBWriteRetryDelayFlag.Name),
+		MaintenanceInterval:  ctx.Duration(logDBMaintenanceIntervalFlag.Name),
+		VacuumFreePages:      ctx.Int(logDBVacuumFreePagesFlag.Name),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "open log database [%v]", path)
+	}
+	return db, nil
+}
+
+// openReadOnlyLogDB opens the existing log database in read-only mode, for offline checks which never write.
+func openReadOnlyLogDB(dir string) (*logdb.LogDB, error) {
+	path := filepath.Join(dir, "logs.db")
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("log database [%v] not found", path)
+		}
+		return nil, err
+	}
+	db, err := logdb.NewWithConfig(path, logdb.Config{ReadOnly: true})
 	if err != nil {
 		return nil, errors.Wrapf(err, "open log database [%v]", path)
 	}
@@ -398,6 +761,30 @@ func beneficiary(ctx *cli.Context) (*thor.Address, error) {
 	return &addr, nil
 }
 
+// parseMints parses values of the mint flag in form of <address>:<amount>, where amount is in whole tokens.
+func parseMints(values []string) ([]genesis.DevMint, error) {
+	mints := make([]genesis.DevMint, 0, len(values))
+	for _, v := range values {
+		addrStr, amountStr, ok := strings.Cut(strings.TrimSpace(v), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid entry %q, want <address>:<amount>", v)
+		}
+		addr, err := thor.ParseAddress(addrStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid address of entry %q", v)
+		}
+		amount, ok := new(big.Int).SetString(amountStr, 10)
+		if !ok || amount.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid amount of entry %q", v)
+		}
+		mints = append(mints, genesis.DevMint{
+			Address: addr,
+			Amount:  amount.Mul(amount, big.NewInt(1e18)),
+		})
+	}
+	return mints, nil
+}
+
 func masterKeyPath(ctx *cli.Context) (string, error) {
 	configDir, err := makeConfigDir(ctx)
 	if err != nil {
@@ -427,6 +814,10 @@ func loadNodeMasterFromStdin() (*ecdsa.PrivateKey, error) {
 	return crypto.HexToECDSA(strings.TrimSpace(input))
 }
 
+// masterKeyEnv is the environment variable of the hex encoded master key, which is used only if
+// the master key file is absent.
+const masterKeyEnv = "THOR_MASTER_KEY"
+
 func loadNodeMaster(ctx *cli.Context) (*node.Master, error) {
 	var key *ecdsa.PrivateKey
 	var err error
@@ -442,9 +833,18 @@ func loadNodeMaster(ctx *cli.Context) (*node.Master, error) {
 		if err != nil {
 			return nil, err
 		}
-		key, err = loadOrGeneratePrivateKey(path)
-		if err != nil {
-			return nil, errors.Wrap(err, "load or generate master key")
+		if _, statErr := os.Stat(path); os.IsNotExist(statErr) && os.Getenv(masterKeyEnv) != "" {
+			// never saved to the key file
+			key, err = parseHexPrivateKey(os.Getenv(masterKeyEnv))
+			if err != nil {
+				return nil, errors.Wrapf(err, "parse master key from env %v", masterKeyEnv)
+			}
+			log.Warn("master key loaded from env, which is less secure than the key file", "env", masterKeyEnv)
+		} else {
+			key, err = loadOrGeneratePrivateKey(path)
+			if err != nil {
+				return nil, errors.Wrap(err, "load or generate master key")
+			}
 		}
 	}
 
@@ -479,6 +879,14 @@ func newP2PCommunicator(ctx *cli.Context, repo *chain.Repository, txPool *txpool
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse allowed peers - %w", err)
 	}
+	if path := ctx.String(allowedPeersFileFlag.Name); path != "" {
+		if len(allowedPeers) > 0 {
+			return nil, fmt.Errorf("--%v and --%v are exclusive", allowedPeersFlag.Name, allowedPeersFileFlag.Name)
+		}
+		if allowedPeers, err = loadAllowedPeersFile(path); err != nil {
+			return nil, err
+		}
+	}
 
 	bootnodePeers, err := parseNodeList(strings.TrimSpace(ctx.String(bootNodeFlag.Name)))
 	if err != nil {
@@ -502,16 +910,35 @@ func newP2PCommunicator(ctx *cli.Context, repo *chain.Repository, txPool *txpool
 		fullVersion(),
 		ctx.Int(maxPeersFlag.Name),
 		ctx.Int(p2pPortFlag.Name),
+		ctx.Int(p2pAdvertisePortFlag.Name),
 		fmt.Sprintf(":%v", ctx.Int(p2pPortFlag.Name)),
 		allowedPeers,
+		ctx.String(allowedPeersFileFlag.Name) != "",
 		cachedPeers,
 		bootnodePeers,
+		ctx.Bool(p2pNoDiscoveryFlag.Name),
+		ctx.Duration(bootNodeWarnAfterFlag.Name),
 	), nil
 }
 
 func startAPIServer(ctx *cli.Context, handler http.Handler, genesisID thor.Bytes32) (string, func(), error) {
+	var (
+		certFile = ctx.String(apiTLSCertFlag.Name)
+		keyFile  = ctx.String(apiTLSKeyFlag.Name)
+		certs    *certReloader
+	)
+	if (certFile == "") != (keyFile == "") {
+		return "", nil, fmt.Errorf("both --%v and --%v should be set to enable TLS", apiTLSCertFlag.Name, apiTLSKeyFlag.Name)
+	}
+	if certFile != "" {
+		var err error
+		if certs, err = newCertReloader(certFile, keyFile); err != nil {
+			return "", nil, err
+		}
+	}
+
 	addr := ctx.String(apiAddrFlag.Name)
-	listener, err := net.Listen("tcp", addr)
+	listener, err := listenAPI(addr, ctx.String(apiSocketModeFlag.Name))
 	if err != nil {
 		return "", nil, errors.Wrapf(err, "listen API addr [%v]", addr)
 	}
@@ -521,18 +948,88 @@ func startAPIServer(ctx *cli.Context, handler http.Handler, genesisID thor.Bytes
 	}
 	handler = handleXGenesisID(handler, genesisID)
 	handler = handleXThorestVersion(handler)
-	handler = requestBodyLimit(handler)
+	var inFlight atomic.Int64
+	handler = handleInFlight(handler, &inFlight)
 	srv := &http.Server{Handler: handler, ReadHeaderTimeout: time.Second, ReadTimeout: 5 * time.Second}
+
+	scheme := "http"
+	stopWatch := func() {}
+	if certs != nil {
+		scheme = "https"
+		srv.TLSConfig = &tls.Config{
+			GetCertificate: certs.getCertificate,
+			MinVersion:     tls.VersionTLS12,
+		}
+		stopWatch = certs.watchSIGHUP()
+	}
+
 	var goes co.Goes
 	goes.Go(func() {
-		srv.Serve(listener)
+		if certs != nil {
+			srv.ServeTLS(listener, "", "")
+		} else {
+			srv.Serve(listener)
+		}
 	})
-	return "http://" + listener.Addr().String() + "/", func() {
-		srv.Close()
+	shutdownTimeout := ctx.Duration(apiShutdownTimeoutFlag.Name)
+	url := scheme + "://" + listener.Addr().String() + "/"
+	if listener.Addr().Network() == "unix" {
+		url = scheme + "+unix://" + listener.Addr().String()
+	}
+	return url, func() {
+		stopWatch()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Warn("API server shutdown timed out, force closing", "inflight", inFlight.Load())
+			srv.Close()
+		}
 		goes.Wait()
 	}, nil
 }
 
+// listenAPI listens on the TCP address, or the UNIX domain socket if the address is prefixed with "unix:".
+// A stale socket left by an unclean exit is removed, and the socket file is chmod-ed to the given mode.
+func listenAPI(addr string, mode string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, "unix:")
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	if path == "" {
+		return nil, errors.New("empty socket path")
+	}
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || perm > 0777 {
+		return nil, fmt.Errorf("invalid --%v %q", apiSocketModeFlag.Name, mode)
+	}
+
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%v exists and is not a socket", path)
+		}
+		// refuse to take over the socket if it's still being served
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("socket %v is in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, errors.Wrap(err, "remove stale socket")
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, os.FileMode(perm)); err != nil {
+		listener.Close()
+		return nil, errors.Wrap(err, "chmod socket")
+	}
+	return listener, nil
+}
+
 func startMetricsServer(addr string) (string, func(), error) {
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -554,6 +1051,36 @@ func startMetricsServer(addr string) (string, func(), error) {
 	}, nil
 }
 
+// startPprofServer serves go-pprof on the given address, apart from the API.
+func startPprofServer(addr string) (string, func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "listen pprof addr [%v]", addr)
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			log.Warn("pprof server is reachable from non-local hosts", "addr", addr)
+		}
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	router.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	router.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	router.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	router.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
+
+	srv := &http.Server{Handler: router, ReadHeaderTimeout: time.Second}
+	var goes co.Goes
+	goes.Go(func() {
+		srv.Serve(listener)
+	})
+	return "http://" + listener.Addr().String() + "/debug/pprof/", func() {
+		srv.Close()
+		goes.Wait()
+	}, nil
+}
+
 func printStartupMessage1(
 	gene *genesis.Genesis,
 	repo *chain.Repository,
@@ -653,6 +1180,7 @@ func printSoloStartupMessage(
 
 	info := fmt.Sprintf(`Starting %v
     Network     [ %v %v ]    
+    Chain tag   [ 0x%02x ]
     Best block  [ %v #%v @%v ]
     Forks       [ %v ]
     Data dir    [ %v ]
@@ -661,6 +1189,7 @@ func printSoloStartupMessage(
 `,
 		common.MakeName("Thor solo", fullVersion()),
 		gene.ID(), gene.Name(),
+		repo.ChainTag(),
 		bestBlock.Header.ID(), bestBlock.Header.Number(), time.Unix(int64(bestBlock.Header.Timestamp()), 0),
 		forkConfig,
 		dataDir,
@@ -712,6 +1241,73 @@ func parseNodeList(list string) ([]*discover.Node, error) {
 	return nodes, nil
 }
 
+// parseAPIRateLimit parses the rate limit in form of <requests-per-second>[,<burst>].
+// The burst defaults to the requests per second if absent.
+func parseAPIRateLimit(value string) (api.RateLimiter, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	rateStr, burstStr, hasBurst := strings.Cut(value, ",")
+	rate, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse requests per second")
+	}
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return nil, fmt.Errorf("invalid requests per second %v", rateStr)
+	}
+
+	burst := int(math.Ceil(rate))
+	if hasBurst {
+		if burst, err = strconv.Atoi(strings.TrimSpace(burstStr)); err != nil {
+			return nil, errors.Wrap(err, "parse burst")
+		}
+		if burst <= 0 {
+			return nil, fmt.Errorf("invalid burst %v", burstStr)
+		}
+	}
+	return api.NewIPRateLimiter(rate, burst), nil
+}
+
+// parseIPNets parses the comma separated list of CIDRs, in which a bare IP is taken as a single
+// address network. nil returned if the list is empty.
+func parseIPNets(value string) ([]*net.IPNet, error) {
+	var allowlist []*net.IPNet
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP %v", s)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			allowlist = append(allowlist, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		allowlist = append(allowlist, ipNet)
+	}
+	return allowlist, nil
+}
+
+// readTxPoolMaxLifetime reads the txpool max lifetime flag, which should be no less than one block interval (seconds).
+func readTxPoolMaxLifetime(ctx *cli.Context, blockInterval uint64) (time.Duration, error) {
+	lifetime := ctx.Duration(txPoolMaxLifetimeFlag.Name)
+	if lifetime < time.Duration(blockInterval)*time.Second {
+		return 0, fmt.Errorf("invalid %s flag %v, should be no less than block interval %ds", txPoolMaxLifetimeFlag.Name, lifetime, blockInterval)
+	}
+	return lifetime, nil
+}
+
 func readIntFromUInt64Flag(val uint64) (int, error) {
 	i := int(val)
 
@@ -56,9 +56,30 @@ func DevAccounts() []DevAccount {
 	return accs
 }
 
+// DevConfig is the config to customize the devnet genesis.
+type DevConfig struct {
+	LaunchTime uint64    // the genesis block timestamp, the default one is used if 0
+	Mints      []DevMint // extra allocations on top of the dev accounts
+}
+
+// DevMint is an extra allocation of the devnet genesis. Both VET and VTHO balances of the address
+// are increased by the amount.
+type DevMint struct {
+	Address thor.Address
+	Amount  *big.Int // in wei
+}
+
 // NewDevnet create genesis for solo mode.
 func NewDevnet() *Genesis {
+	return NewDevnetWithConfig(DevConfig{})
+}
+
+// NewDevnetWithConfig create genesis for solo mode with the given config.
+func NewDevnetWithConfig(config DevConfig) *Genesis {
 	launchTime := uint64(1526400000) // 'Wed May 16 2018 00:00:00 GMT+0800 (CST)'
+	if config.LaunchTime != 0 {
+		launchTime = config.LaunchTime
+	}
 
 	executor := DevAccounts()[0].Address
 	soloBlockSigner := DevAccounts()[0]
@@ -97,6 +118,24 @@ func NewDevnet() *Genesis {
 				tokenSupply.Add(tokenSupply, bal)
 				energySupply.Add(energySupply, bal)
 			}
+			for _, m := range config.Mints {
+				bal, err := state.GetBalance(m.Address)
+				if err != nil {
+					return err
+				}
+				if err := state.SetBalance(m.Address, new(big.Int).Add(bal, m.Amount)); err != nil {
+					return err
+				}
+				energy, err := state.GetEnergy(m.Address, launchTime)
+				if err != nil {
+					return err
+				}
+				if err := state.SetEnergy(m.Address, new(big.Int).Add(energy, m.Amount), launchTime); err != nil {
+					return err
+				}
+				tokenSupply.Add(tokenSupply, m.Amount)
+				energySupply.Add(energySupply, m.Amount)
+			}
 			return builtin.Energy.Native(state, launchTime).SetInitialSupply(tokenSupply, energySupply)
 		}).
 		Call(
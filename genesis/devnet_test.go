@@ -6,10 +6,14 @@
 package genesis_test
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/builtin"
 	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
 )
 
@@ -35,3 +39,58 @@ func TestNewDevnet(t *testing.T) {
 	assert.NotEqual(t, thor.Bytes32{}, genesisObj.ID(), "Genesis ID should be valid")
 	assert.Equal(t, "devnet", genesisObj.Name(), "Genesis name should be 'devnet'")
 }
+
+// TestNewDevnetWithConfig checks if the launch time of the devnet genesis can be overridden
+func TestNewDevnetWithConfig(t *testing.T) {
+	launchTime := uint64(1700000000)
+	genesisObj := genesis.NewDevnetWithConfig(genesis.DevConfig{LaunchTime: launchTime})
+
+	blk, _, _, err := genesisObj.Build(state.NewStater(muxdb.NewMem()))
+	assert.Nil(t, err)
+	assert.Equal(t, launchTime, blk.Header().Timestamp(), "Genesis timestamp should be overridden")
+	assert.NotEqual(t, genesis.NewDevnet().ID(), genesisObj.ID(), "Genesis ID should differ from the default devnet")
+
+	// zero value falls back to the default launch time
+	assert.Equal(t, genesis.NewDevnet().ID(), genesis.NewDevnetWithConfig(genesis.DevConfig{}).ID())
+}
+
+// TestNewDevnetWithMints checks if extra allocations are applied on top of the dev accounts
+func TestNewDevnetWithMints(t *testing.T) {
+	addr := thor.BytesToAddress([]byte("minted"))
+	devAddr := genesis.DevAccounts()[0].Address
+	amount := big.NewInt(1e18)
+
+	genesisObj := genesis.NewDevnetWithConfig(genesis.DevConfig{Mints: []genesis.DevMint{
+		{Address: addr, Amount: amount},
+		{Address: devAddr, Amount: amount},
+	}})
+	assert.NotEqual(t, genesis.NewDevnet().ID(), genesisObj.ID(), "Genesis ID should differ from the default devnet")
+
+	stater := state.NewStater(muxdb.NewMem())
+	blk, _, _, err := genesisObj.Build(stater)
+	assert.Nil(t, err)
+	st := stater.NewState(blk.Header().StateRoot(), 0, 0, 0)
+
+	balanceOf := func(addr thor.Address) (*big.Int, *big.Int) {
+		bal, err := st.GetBalance(addr)
+		assert.Nil(t, err)
+		energy, err := st.GetEnergy(addr, blk.Header().Timestamp())
+		assert.Nil(t, err)
+		return bal, energy
+	}
+
+	bal, energy := balanceOf(addr)
+	assert.Equal(t, amount, bal)
+	assert.Equal(t, amount, energy)
+
+	devBal, _ := new(big.Int).SetString("1000000001000000000000000000", 10)
+	bal, energy = balanceOf(devAddr)
+	assert.Equal(t, devBal, bal)
+	assert.Equal(t, devBal, energy)
+
+	// the total supply includes the mints
+	total, err := builtin.Energy.Native(st, blk.Header().Timestamp()).TokenTotalSupply()
+	assert.Nil(t, err)
+	want, _ := new(big.Int).SetString("10000000002000000000000000000", 10)
+	assert.Equal(t, want, total)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/vechain/thor/v2/thor"
+)
+
+// EventCursor iterates over the events of a query, backed by the underlying sql rows.
+type EventCursor struct {
+	ctx         context.Context
+	rows        *sql.Rows
+	release     func() // releases the query slot
+	releaseOnce sync.Once
+}
+
+// Next returns the next event. It returns false if there are no more events, and the cursor
+// is closed automatically in that case. The cursor is also closed once the context is done.
+func (c *EventCursor) Next() (*Event, bool, error) {
+	if err := c.ctx.Err(); err != nil {
+		_ = c.Close()
+		return nil, false, err
+	}
+
+	if !c.rows.Next() {
+		if err := c.rows.Err(); err != nil {
+			_ = c.Close()
+			return nil, false, err
+		}
+		// no more rows or closed
+		if err := c.ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		return nil, false, c.Close()
+	}
+
+	var (
+		seq         sequence
+		blockID     []byte
+		blockTime   uint64
+		txID        []byte
+		txOrigin    []byte
+		clauseIndex uint32
+		address     []byte
+		topics      [5][]byte
+		data        []byte
+	)
+	if err := c.rows.Scan(
+		&seq,
+		&blockID,
+		&blockTime,
+		&txID,
+		&txOrigin,
+		&clauseIndex,
+		&address,
+		&topics[0],
+		&topics[1],
+		&topics[2],
+		&topics[3],
+		&topics[4],
+		&data,
+	); err != nil {
+		_ = c.Close()
+		return nil, false, err
+	}
+	event := &Event{
+		BlockNumber: seq.BlockNumber(),
+		Index:       seq.Index(),
+		BlockID:     thor.BytesToBytes32(blockID),
+		BlockTime:   blockTime,
+		TxID:        thor.BytesToBytes32(txID),
+		TxOrigin:    thor.BytesToAddress(txOrigin),
+		ClauseIndex: clauseIndex,
+		Address:     thor.BytesToAddress(address),
+		Data:        data,
+	}
+	for i, topic := range topics {
+		if len(topic) > 0 {
+			h := thor.BytesToBytes32(topic)
+			event.Topics[i] = &h
+		}
+	}
+	return event, true, nil
+}
+
+// Close closes the cursor and releases the underlying rows. It's safe to call Close multiple times.
+func (c *EventCursor) Close() error {
+	err := c.rows.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
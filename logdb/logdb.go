@@ -8,9 +8,15 @@ package logdb
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	sqlite3 "github.com/mattn/go-sqlite3"
 	"github.com/vechain/thor/v2/block"
@@ -22,6 +28,9 @@ const (
 	refIDQuery = "(SELECT id FROM ref WHERE data=?)"
 )
 
+// ErrQueryQueueTimeout is returned when the context is done while the query is waiting for a query slot.
+var ErrQueryQueueTimeout = errors.New("logdb: too many concurrent queries, cancelled while waiting")
+
 type LogDB struct {
 	path          string
 	driverVersion string
@@ -29,11 +38,77 @@ type LogDB struct {
 	wconn         *sql.Conn
 	wconnSyncOff  *sql.Conn
 	stmtCache     *stmtCache
+	wstmtCache    *stmtCache    // statements bound to wconn
+	wstmtCacheOff *stmtCache    // statements bound to wconnSyncOff
+	querySlots    chan struct{} // nil if unlimited
+	writeRetry    retryPolicy
+	maintainStop  chan struct{} // nil if maintenance disabled
+	maintainDone  chan struct{}
+}
+
+// Config is the config of the underlying SQLite database. Empty fields mean the defaults.
+type Config struct {
+	// JournalMode is the journal mode, "wal" by default.
+	JournalMode string
+	// Synchronous is the synchronous level of commits, "normal" by default. In WAL mode, "normal" keeps
+	// the db consistent, but the latest commits may be rolled back following a power loss or OS crash,
+	// while "full" makes commits durable at the cost of write speed. The writer created by
+	// NewWriterSyncOff is not affected, which is always "off".
+	Synchronous string
+	// CacheSize is the page cache size of each connection, in pages if positive, or in KiB if negative.
+	// The SQLite default is used if 0.
+	CacheSize int
+	// MaxConcurrentQueries is the max number of log queries running at the same time. Excess queries
+	// wait for a slot until their contexts are done. Unlimited if 0.
+	MaxConcurrentQueries int
+	// WriteRetries is the max number of retries of a write, including the commit, which failed because
+	// the database is busy or locked by other connections. No retry if 0.
+	WriteRetries int
+	// WriteRetryDelay is the delay before the first retry of a write, doubled for each next retry.
+	// 10ms if 0.
+	WriteRetryDelay time.Duration
+	// MaintenanceInterval is the interval to checkpoint the WAL and truncate it in the background, which
+	// otherwise grows unbounded under continuous reads. Disabled if 0.
+	MaintenanceInterval time.Duration
+	// VacuumFreePages is the min number of free pages, left by deletes e.g. on reorgs, to vacuum the
+	// database at the maintenance, so that the file shrinks. Vacuum is skipped if the database is busy,
+	// and blocks writes while running. Disabled if 0.
+	VacuumFreePages int
+	// ReadOnly opens the existing db in read-only mode, e.g. for offline checks. Neither the schema is
+	// created nor the maintenance runs, and writes fail. Other settings but MaxConcurrentQueries and
+	// CacheSize are ignored.
+	ReadOnly bool
 }
 
 // New create or open log db at given path.
 func New(path string) (logDB *LogDB, err error) {
-	db, err := sql.Open("sqlite3", path+"?_journal=wal&cache=shared")
+	return NewWithConfig(path, Config{})
+}
+
+// NewWithConfig create or open log db at given path, with the given config.
+func NewWithConfig(path string, config Config) (logDB *LogDB, err error) {
+	params := url.Values{}
+	params.Set("cache", "shared")
+	dsn := path
+	if config.ReadOnly {
+		// the mode param is passed to sqlite only in URI form
+		params.Set("mode", "ro")
+		dsn = "file:" + path
+	} else {
+		if config.JournalMode != "" {
+			params.Set("_journal", config.JournalMode)
+		} else {
+			params.Set("_journal", "wal")
+		}
+		if config.Synchronous != "" {
+			params.Set("_sync", config.Synchronous)
+		}
+	}
+	if config.CacheSize != 0 {
+		params.Set("_cache_size", strconv.Itoa(config.CacheSize))
+	}
+
+	db, err := sql.Open("sqlite3", dsn+"?"+params.Encode())
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +118,12 @@ func New(path string) (logDB *LogDB, err error) {
 		}
 	}()
 
-	if _, err := db.Exec(refTableScheme + eventTableSchema + transferTableSchema); err != nil {
+	if config.ReadOnly {
+		// fails if not existing
+		if err := db.Ping(); err != nil {
+			return nil, err
+		}
+	} else if _, err := db.Exec(refTableScheme + eventTableSchema + transferTableSchema); err != nil {
 		return nil, err
 	}
 
@@ -61,15 +141,35 @@ func New(path string) (logDB *LogDB, err error) {
 		return nil, err
 	}
 
+	var querySlots chan struct{}
+	if config.MaxConcurrentQueries > 0 {
+		querySlots = make(chan struct{}, config.MaxConcurrentQueries)
+	}
+
+	writeRetry := retryPolicy{retries: config.WriteRetries, delay: config.WriteRetryDelay}
+	if writeRetry.delay <= 0 {
+		writeRetry.delay = defaultWriteRetryDelay
+	}
+
 	driverVer, _, _ := sqlite3.Version()
-	return &LogDB{
+	logDB = &LogDB{
 		path:          path,
 		driverVersion: driverVer,
 		db:            db,
 		wconn:         wconn1,
 		wconnSyncOff:  wconn2,
 		stmtCache:     newStmtCache(db),
-	}, nil
+		wstmtCache:    newStmtCache(wconn1),
+		wstmtCacheOff: newStmtCache(wconn2),
+		querySlots:    querySlots,
+		writeRetry:    writeRetry,
+	}
+	if config.MaintenanceInterval > 0 && !config.ReadOnly {
+		logDB.maintainStop = make(chan struct{})
+		logDB.maintainDone = make(chan struct{})
+		go logDB.maintainLoop(config.MaintenanceInterval, config.VacuumFreePages)
+	}
+	return logDB, nil
 }
 
 // NewMem create a log db in ram.
@@ -79,6 +179,12 @@ func NewMem() (*LogDB, error) {
 
 // Close close the log db.
 func (db *LogDB) Close() (err error) {
+	if db.maintainStop != nil {
+		close(db.maintainStop)
+		<-db.maintainDone
+	}
+	db.wstmtCache.Clear()
+	db.wstmtCacheOff.Clear()
 	err = db.wconn.Close()
 	if err1 := db.wconnSyncOff.Close(); err == nil {
 		err = err1
@@ -95,6 +201,28 @@ func (db *LogDB) Path() string {
 }
 
 func (db *LogDB) FilterEvents(ctx context.Context, filter *EventFilter) ([]*Event, error) {
+	cursor, err := db.FilterEventsStream(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close() }()
+
+	var events []*Event
+	for {
+		event, ok, err := cursor.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return events, nil
+		}
+		events = append(events, event)
+	}
+}
+
+// FilterEventsStream queries events like FilterEvents, but yields them one by one through the returned cursor,
+// so that the result set is never fully loaded into memory. The cursor must be closed after use.
+func (db *LogDB) FilterEventsStream(ctx context.Context, filter *EventFilter) (*EventCursor, error) {
 	const query = `SELECT e.seq, r0.data, e.blockTime, r1.data, r2.data, e.clauseIndex, r3.data, r4.data, r5.data, r6.data, r7.data, r8.data, e.data
 FROM (%v) e
 	LEFT JOIN ref r0 ON e.blockID = r0.id
@@ -125,6 +253,11 @@ FROM (%v) e
 		}
 	}
 
+	if filter.Origin != nil {
+		subQuery += " AND txOrigin = " + refIDQuery
+		args = append(args, filter.Origin.Bytes())
+	}
+
 	if len(filter.CriteriaSet) > 0 {
 		subQuery += " AND ("
 
@@ -139,6 +272,20 @@ FROM (%v) e
 		subQuery += ")"
 	}
 
+	// keyset pagination
+	if filter.Options != nil && filter.Options.After != nil {
+		after, err := filter.Options.After.sequence()
+		if err != nil {
+			return nil, err
+		}
+		if filter.Order == DESC {
+			subQuery += " AND seq < ?"
+		} else {
+			subQuery += " AND seq > ?"
+		}
+		args = append(args, after)
+	}
+
 	// if there is limit option, set order inside subquery
 	if filter.Options != nil {
 		if filter.Order == DESC {
@@ -191,6 +338,24 @@ FROM (%v) t
 		}
 	}
 
+	// amounts are stored as minimal big-endian bytes, so compare lengths first, and then bytes for equal lengths.
+	if filter.MinAmount != nil {
+		if filter.MinAmount.Sign() < 0 {
+			return nil, errors.New("negative min amount")
+		}
+		min := filter.MinAmount.Bytes()
+		subQuery += " AND (length(amount) > ? OR (length(amount) = ? AND amount >= ?))"
+		args = append(args, len(min), len(min), min)
+	}
+	if filter.MaxAmount != nil {
+		if filter.MaxAmount.Sign() < 0 {
+			return nil, errors.New("negative max amount")
+		}
+		max := filter.MaxAmount.Bytes()
+		subQuery += " AND (length(amount) < ? OR (length(amount) = ? AND amount <= ?))"
+		args = append(args, len(max), len(max), max)
+	}
+
 	if len(filter.CriteriaSet) > 0 {
 		subQuery += " AND ("
 		for i, c := range filter.CriteriaSet {
@@ -204,6 +369,20 @@ FROM (%v) t
 		subQuery += ")"
 	}
 
+	// keyset pagination
+	if filter.Options != nil && filter.Options.After != nil {
+		after, err := filter.Options.After.sequence()
+		if err != nil {
+			return nil, err
+		}
+		if filter.Order == DESC {
+			subQuery += " AND seq < ?"
+		} else {
+			subQuery += " AND seq > ?"
+		}
+		args = append(args, after)
+	}
+
 	// if there is limit option, set order inside subquery
 	if filter.Options != nil {
 		if filter.Order == DESC {
@@ -228,74 +407,40 @@ FROM (%v) t
 	return db.queryTransfers(ctx, transferQuery, args...)
 }
 
-func (db *LogDB) queryEvents(ctx context.Context, query string, args ...interface{}) ([]*Event, error) {
-	rows, err := db.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, err
+// acquireQuerySlot waits for a query slot, and returns the func to release it.
+func (db *LogDB) acquireQuerySlot(ctx context.Context) (func(), error) {
+	if db.querySlots == nil {
+		return func() {}, nil
 	}
-	defer func() { _ = rows.Close() }()
+	select {
+	case db.querySlots <- struct{}{}:
+		return func() { <-db.querySlots }, nil
+	case <-ctx.Done():
+		return nil, ErrQueryQueueTimeout
+	}
+}
 
-	var events []*Event
-	for rows.Next() {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-		var (
-			seq         sequence
-			blockID     []byte
-			blockTime   uint64
-			txID        []byte
-			txOrigin    []byte
-			clauseIndex uint32
-			address     []byte
-			topics      [5][]byte
-			data        []byte
-		)
-		if err := rows.Scan(
-			&seq,
-			&blockID,
-			&blockTime,
-			&txID,
-			&txOrigin,
-			&clauseIndex,
-			&address,
-			&topics[0],
-			&topics[1],
-			&topics[2],
-			&topics[3],
-			&topics[4],
-			&data,
-		); err != nil {
-			return nil, err
-		}
-		event := &Event{
-			BlockNumber: seq.BlockNumber(),
-			Index:       seq.Index(),
-			BlockID:     thor.BytesToBytes32(blockID),
-			BlockTime:   blockTime,
-			TxID:        thor.BytesToBytes32(txID),
-			TxOrigin:    thor.BytesToAddress(txOrigin),
-			ClauseIndex: clauseIndex,
-			Address:     thor.BytesToAddress(address),
-			Data:        data,
-		}
-		for i, topic := range topics {
-			if len(topic) > 0 {
-				h := thor.BytesToBytes32(topic)
-				event.Topics[i] = &h
-			}
-		}
-		events = append(events, event)
+func (db *LogDB) queryEvents(ctx context.Context, query string, args ...interface{}) (*EventCursor, error) {
+	release, err := db.acquireQuerySlot(ctx)
+	if err != nil {
+		return nil, err
 	}
-	if err := rows.Err(); err != nil {
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		release()
 		return nil, err
 	}
-	return events, nil
+	// the slot is held until the cursor closed
+	return &EventCursor{ctx: ctx, rows: rows, release: release}, nil
 }
 
 func (db *LogDB) queryTransfers(ctx context.Context, query string, args ...interface{}) ([]*Transfer, error) {
+	release, err := db.acquireQuerySlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	rows, err := db.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
@@ -386,14 +531,59 @@ func (db *LogDB) HasBlockID(id thor.Bytes32) (bool, error) {
 	return count > 0, nil
 }
 
+// Stats returns the statistics of the log db.
+func (db *LogDB) Stats() (*Stats, error) {
+	var (
+		stats    Stats
+		min, max sql.NullInt64
+	)
+	// MIN/MAX with multiple arguments returns NULL if any argument is NULL, so COALESCE both ways
+	row := db.db.QueryRow(`WITH s AS (SELECT
+			(SELECT MIN(seq) FROM event) AS emin, (SELECT MAX(seq) FROM event) AS emax,
+			(SELECT MIN(seq) FROM transfer) AS tmin, (SELECT MAX(seq) FROM transfer) AS tmax)
+		SELECT
+			(SELECT COUNT(*) FROM event),
+			(SELECT COUNT(*) FROM transfer),
+			MIN(COALESCE(emin, tmin), COALESCE(tmin, emin)),
+			MAX(COALESCE(emax, tmax), COALESCE(tmax, emax))
+		FROM s`)
+	if err := row.Scan(&stats.EventCount, &stats.TransferCount, &min, &max); err != nil {
+		return nil, err
+	}
+	stats.EarliestBlock = sequence(min.Int64).BlockNumber()
+	stats.LatestBlock = sequence(max.Int64).BlockNumber()
+
+	if !strings.HasPrefix(db.path, "file::memory:") {
+		// the main db file and the write-ahead log
+		for _, path := range []string{db.path, db.path + "-wal"} {
+			info, err := os.Stat(path)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			stats.FileSizeBytes += info.Size()
+		}
+	}
+	return &stats, nil
+}
+
+// Export writes a consistent snapshot of the log db into a new database file at the given path,
+// which can be opened by New. The file must not exist.
+func (db *LogDB) Export(ctx context.Context, path string) error {
+	_, err := db.db.ExecContext(ctx, "VACUUM INTO ?", path)
+	return err
+}
+
 // NewWriter creates a log writer.
 func (db *LogDB) NewWriter() *Writer {
-	return &Writer{conn: db.wconn, stmtCache: db.stmtCache}
+	return &Writer{conn: db.wconn, stmtCache: db.wstmtCache, retry: db.writeRetry}
 }
 
 // NewWriterSyncOff creates a log writer which applied 'pragma synchronous = off'.
 func (db *LogDB) NewWriterSyncOff() *Writer {
-	return &Writer{conn: db.wconnSyncOff, stmtCache: db.stmtCache}
+	return &Writer{conn: db.wconnSyncOff, stmtCache: db.wstmtCacheOff, retry: db.writeRetry}
 }
 
 func topicValue(topics []thor.Bytes32, i int) []byte {
@@ -403,16 +593,20 @@ func topicValue(topics []thor.Bytes32, i int) []byte {
 	return nil
 }
 
-// Writer is the transactional log writer.
+// Writer is the transactional log writer. Transactions are managed by plain statements on the dedicated
+// connection, rather than sql.Tx, since a sql.Tx is rolled back once its commit fails, which then can't be
+// retried. Writes failed with transient errors are retried according to the retry policy.
 type Writer struct {
 	conn      *sql.Conn
 	stmtCache *stmtCache
+	retry     retryPolicy
 
-	tx               *sql.Tx
+	inTx             bool
 	uncommittedCount int
 }
 
 // Truncate truncates the database by deleting logs after blockNum (included).
+// The deletions are committed along with the writes, so that a reorg is applied atomically.
 func (w *Writer) Truncate(blockNum uint32) error {
 	seq := newSequence(blockNum, 0)
 	if err := w.exec("DELETE FROM event WHERE seq >= ?", seq); err != nil {
@@ -559,31 +753,36 @@ func (w *Writer) Write(b *block.Block, receipts tx.Receipts) error {
 
 // Commit commits accumulated logs.
 func (w *Writer) Commit() (err error) {
-	if w.tx == nil {
+	if !w.inTx {
 		return nil
 	}
 
 	defer func() {
 		if err == nil {
-			w.tx = nil
+			w.inTx = false
 			w.uncommittedCount = 0
 		}
 	}()
-	return w.tx.Commit()
+	// the transaction is kept open if the commit failed with busy error, so it's safe to retry
+	return w.retry.do(func() error {
+		_, err := w.conn.ExecContext(context.Background(), "COMMIT")
+		return err
+	})
 }
 
 // Rollback rollback all uncommitted logs.
 func (w *Writer) Rollback() (err error) {
-	if w.tx == nil {
+	if !w.inTx {
 		return nil
 	}
 	defer func() {
 		if err == nil {
-			w.tx = nil
+			w.inTx = false
 			w.uncommittedCount = 0
 		}
 	}()
-	return w.tx.Rollback()
+	_, err = w.conn.ExecContext(context.Background(), "ROLLBACK")
+	return err
 }
 
 // UncommittedCount returns the count of uncommitted logs.
@@ -592,12 +791,21 @@ func (w *Writer) UncommittedCount() int {
 }
 
 func (w *Writer) exec(query string, args ...interface{}) (err error) {
-	if w.tx == nil {
-		if w.tx, err = w.conn.BeginTx(context.Background(), nil); err != nil {
+	if !w.inTx {
+		// take the write lock immediately, so that later statements won't be blocked by other writers
+		if err = w.retry.do(func() error {
+			_, err := w.conn.ExecContext(context.Background(), "BEGIN IMMEDIATE")
+			return err
+		}); err != nil {
 			return
 		}
+		w.inTx = true
 	}
-	if _, err = w.tx.Stmt(w.stmtCache.MustPrepare(query)).Exec(args...); err != nil {
+	stmt := w.stmtCache.MustPrepare(query)
+	if err = w.retry.do(func() error {
+		_, err := stmt.Exec(args...)
+		return err
+	}); err != nil {
 		return
 	}
 	w.uncommittedCount++
@@ -147,6 +147,73 @@ func BenchmarkFakeDB_WriteBlocks(t *testing.B) {
 	}
 }
 
+// BenchmarkFakeDB_FilterEventsByTopic0 creates a temporary database with 1M events, and measures the performance of
+// filtering events by topic0 only, which is the typical query of event signature.
+func BenchmarkFakeDB_FilterEventsByTopic0(b *testing.B) {
+	db, err := createTempDB()
+	require.NoError(b, err)
+	defer db.Close()
+
+	var (
+		addresses = make([]thor.Address, 50)
+		topics    = make([]thor.Bytes32, 20)
+	)
+	for i := range addresses {
+		addresses[i] = thor.BytesToAddress([]byte{byte(i + 1)})
+	}
+	for i := range topics {
+		topics[i] = thor.BytesToBytes32([]byte{byte(i + 1)})
+	}
+
+	// 10K blocks with 100 events each
+	blk := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < 10_000; i++ {
+		blk = new(block.Builder).
+			ParentID(blk.Header().ID()).
+			Transaction(newTx()).
+			Build()
+
+		events := make(tx.Events, 0, 100)
+		for j := 0; j < 100; j++ {
+			events = append(events, &tx.Event{
+				Address: addresses[(i+j)%len(addresses)],
+				Topics:  []thor.Bytes32{topics[(i*100+j)%len(topics)]},
+			})
+		}
+		require.NoError(b, w.Write(blk, tx.Receipts{{Outputs: []*tx.Output{{Events: events}}}}))
+	}
+	require.NoError(b, w.Commit())
+
+	topicFilterCriteria := []*logdb.EventCriteria{
+		{
+			Topics: [5]*thor.Bytes32{&topics[0], nil, nil, nil, nil},
+		},
+	}
+
+	tests := []struct {
+		name string
+		arg  *logdb.EventFilter
+	}{
+		{"Topic0", &logdb.EventFilter{CriteriaSet: topicFilterCriteria, Options: &logdb.Options{Offset: 0, Limit: 100}}},
+		{"Topic0Desc", &logdb.EventFilter{CriteriaSet: topicFilterCriteria, Order: logdb.DESC, Options: &logdb.Options{Offset: 0, Limit: 100}}},
+		{"Topic0Range", &logdb.EventFilter{CriteriaSet: topicFilterCriteria, Range: &logdb.Range{From: 5_000, To: 6_000}, Options: &logdb.Options{Offset: 0, Limit: 100}}},
+	}
+
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				events, err := db.FilterEvents(context.Background(), tt.arg)
+				if err != nil {
+					b.Fatal(err)
+				}
+				assert.Len(b, events, 100)
+			}
+		})
+	}
+}
+
 // BenchmarkTestDB_HasBlockID opens a log.db file and measures the performance of the HasBlockID functionality of LogDB.
 // It uses unbounded event filtering to check for blocks existence using the HasBlockID
 func BenchmarkTestDB_HasBlockID(b *testing.B) {
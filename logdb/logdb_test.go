@@ -8,12 +8,18 @@ package logdb_test
 import (
 	"context"
 	"crypto/rand"
+	"encoding/json"
+	"math"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/builtin"
 	logdb "github.com/vechain/thor/v2/logdb"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/tx"
@@ -201,6 +207,12 @@ func TestEvents(t *testing.T) {
 			{"query all events with multi-criteria", &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{Address: &allEvents[1].Address}, {Topics: [5]*thor.Bytes32{allEvents[2].Topics[0]}}, {Topics: [5]*thor.Bytes32{allEvents[3].Topics[0]}}}}, allEvents.Filter(func(ev *logdb.Event) bool {
 				return ev.Address == allEvents[1].Address || *ev.Topics[0] == *allEvents[2].Topics[0] || *ev.Topics[0] == *allEvents[3].Topics[0]
 			})},
+			{"query all events with origin", &logdb.EventFilter{Origin: &allEvents[1].TxOrigin}, allEvents.Filter(func(ev *logdb.Event) bool {
+				return ev.TxOrigin == allEvents[1].TxOrigin
+			})},
+			{"query all events with origin and criteria", &logdb.EventFilter{Origin: &allEvents[1].TxOrigin, CriteriaSet: []*logdb.EventCriteria{{Address: &allEvents[1].Address}, {Address: &allEvents[2].Address}}}, allEvents.Filter(func(ev *logdb.Event) bool {
+				return ev.TxOrigin == allEvents[1].TxOrigin && (ev.Address == allEvents[1].Address || ev.Address == allEvents[2].Address)
+			})},
 		}
 
 		for _, tt := range tests {
@@ -431,3 +443,541 @@ func TestLogDB_HasBlockID(t *testing.T) {
 	}
 	assert.True(t, has)
 }
+
+func TestFilterEventsStream(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < 10; i++ {
+		b = new(block.Builder).
+			ParentID(b.Header().ID()).
+			Transaction(newTx()).
+			Build()
+		if err := w.Write(b, tx.Receipts{newEventOnlyReceipt()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Len(t, all, 10)
+
+	cursor, err := db.FilterEventsStream(context.Background(), &logdb.EventFilter{Order: logdb.DESC})
+	assert.Nil(t, err)
+
+	var got eventLogs
+	for {
+		ev, ok, err := cursor.Next()
+		assert.Nil(t, err)
+		if !ok {
+			break
+		}
+		got = append(got, ev)
+	}
+	assert.Equal(t, eventLogs(all).Reverse(), got)
+	assert.Nil(t, cursor.Close())
+
+	// canceled context stops the cursor
+	ctx, cancel := context.WithCancel(context.Background())
+	cursor, err = db.FilterEventsStream(ctx, nil)
+	assert.Nil(t, err)
+	_, ok, err := cursor.Next()
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	cancel()
+	_, ok, err = cursor.Next()
+	assert.False(t, ok)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, cursor.Close())
+}
+
+func TestLogDB_Stats(t *testing.T) {
+	db, err := logdb.New(filepath.Join(t.TempDir(), "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stats, err := db.Stats()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), stats.EventCount)
+	assert.Equal(t, uint64(0), stats.TransferCount)
+	assert.Equal(t, uint32(0), stats.EarliestBlock)
+	assert.Equal(t, uint32(0), stats.LatestBlock)
+
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < 10; i++ {
+		b = new(block.Builder).
+			ParentID(b.Header().ID()).
+			Transaction(newTx()).
+			Build()
+		receipts := tx.Receipts{newEventOnlyReceipt()}
+		if i >= 5 {
+			receipts = tx.Receipts{newTransferOnlyReceipt()}
+		}
+		if err := w.Write(b, receipts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err = db.Stats()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), stats.EventCount)
+	assert.Equal(t, uint64(5), stats.TransferCount)
+	assert.Equal(t, uint32(2), stats.EarliestBlock)
+	assert.Equal(t, uint32(11), stats.LatestBlock)
+	assert.True(t, stats.FileSizeBytes > 0)
+
+	memDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memDB.Close()
+	stats, err = memDB.Stats()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), stats.FileSizeBytes)
+}
+
+func TestLogDB_Export(t *testing.T) {
+	dir := t.TempDir()
+	db, err := logdb.New(filepath.Join(dir, "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < 10; i++ {
+		b = new(block.Builder).
+			ParentID(b.Header().ID()).
+			Transaction(newTx()).
+			Build()
+		if err := w.Write(b, tx.Receipts{newReceipt()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "snapshot.db")
+	assert.Nil(t, db.Export(context.Background(), path))
+	// not overwritten
+	assert.NotNil(t, db.Export(context.Background(), path))
+
+	snapshot, err := logdb.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snapshot.Close()
+
+	newest, err := snapshot.NewestBlockID()
+	assert.Nil(t, err)
+	assert.Equal(t, b.Header().ID(), newest)
+
+	want, err := db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	got, err := snapshot.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestWriterTruncate(t *testing.T) {
+	db, err := logdb.New(filepath.Join(t.TempDir(), "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var blocks []*block.Block
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < 10; i++ {
+		b = new(block.Builder).
+			ParentID(b.Header().ID()).
+			Transaction(newTx()).
+			Build()
+		if err := w.Write(b, tx.Receipts{newReceipt()}); err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, b)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing deleted until committed
+	from := blocks[5].Header().Number()
+	assert.Nil(t, w.Truncate(from))
+	assert.Nil(t, w.Rollback())
+	newest, err := db.NewestBlockID()
+	assert.Nil(t, err)
+	assert.Equal(t, blocks[9].Header().ID(), newest)
+
+	assert.Nil(t, w.Truncate(from))
+	assert.Nil(t, w.Commit())
+
+	newest, err = db.NewestBlockID()
+	assert.Nil(t, err)
+	assert.Equal(t, blocks[4].Header().ID(), newest)
+
+	events, err := db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(events))
+	transfers, err := db.FilterTransfers(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(transfers))
+	for i := range events {
+		assert.True(t, events[i].BlockNumber < from)
+		assert.True(t, transfers[i].BlockNumber < from)
+	}
+}
+
+func TestMaxConcurrentQueries(t *testing.T) {
+	db, err := logdb.NewWithConfig(filepath.Join(t.TempDir(), "logs.db"), logdb.Config{MaxConcurrentQueries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// the cursor holds the only slot until closed
+	cursor, err := db.FilterEventsStream(context.Background(), nil)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = db.FilterTransfers(ctx, nil)
+	assert.Equal(t, logdb.ErrQueryQueueTimeout, err)
+	_, err = db.FilterEvents(ctx, nil)
+	assert.Equal(t, logdb.ErrQueryQueueTimeout, err)
+
+	assert.Nil(t, cursor.Close())
+	assert.Nil(t, cursor.Close())
+
+	_, err = db.FilterTransfers(context.Background(), nil)
+	assert.Nil(t, err)
+
+	// the slot released once the cursor exhausted
+	cursor, err = db.FilterEventsStream(context.Background(), nil)
+	assert.Nil(t, err)
+	_, ok, err := cursor.Next()
+	assert.False(t, ok)
+	assert.Nil(t, err)
+	_, err = db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+}
+
+func TestNewWithConfig(t *testing.T) {
+	dir := t.TempDir()
+	db, err := logdb.NewWithConfig(filepath.Join(dir, "logs.db"), logdb.Config{
+		JournalMode: "delete",
+		Synchronous: "full",
+		CacheSize:   -4096,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	b := new(block.Builder).Build()
+	b = new(block.Builder).
+		ParentID(b.Header().ID()).
+		Transaction(newTx()).
+		Build()
+	w := db.NewWriter()
+	assert.Nil(t, w.Write(b, tx.Receipts{newReceipt()}))
+	assert.Nil(t, w.Commit())
+
+	newest, err := db.NewestBlockID()
+	assert.Nil(t, err)
+	assert.Equal(t, b.Header().ID(), newest)
+
+	_, err = logdb.NewWithConfig(filepath.Join(dir, "invalid.db"), logdb.Config{JournalMode: "invalid"})
+	assert.NotNil(t, err)
+	_, err = logdb.NewWithConfig(filepath.Join(dir, "invalid.db"), logdb.Config{Synchronous: "invalid"})
+	assert.NotNil(t, err)
+}
+
+func TestNewWithConfigReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs.db")
+
+	_, err := logdb.NewWithConfig(path, logdb.Config{ReadOnly: true})
+	assert.NotNil(t, err, "should fail if not existing")
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	db, err := logdb.New(path)
+	assert.Nil(t, err)
+	b := new(block.Builder).Build()
+	b = new(block.Builder).
+		ParentID(b.Header().ID()).
+		Transaction(newTx()).
+		Build()
+	w := db.NewWriter()
+	assert.Nil(t, w.Write(b, tx.Receipts{newReceipt()}))
+	assert.Nil(t, w.Commit())
+	assert.Nil(t, db.Close())
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+
+	db, err = logdb.NewWithConfig(path, logdb.Config{ReadOnly: true, MaintenanceInterval: time.Millisecond})
+	assert.Nil(t, err)
+	defer db.Close()
+
+	newest, err := db.NewestBlockID()
+	assert.Nil(t, err)
+	assert.Equal(t, b.Header().ID(), newest)
+
+	// export still works
+	assert.Nil(t, db.Export(context.Background(), filepath.Join(dir, "export.db")))
+
+	w = db.NewWriter()
+	err = w.Write(b, tx.Receipts{newReceipt()})
+	if err == nil {
+		err = w.Commit()
+	}
+	assert.NotNil(t, err, "writes should fail")
+
+	time.Sleep(10 * time.Millisecond)
+	after, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, info.ModTime(), after.ModTime())
+	assert.Equal(t, info.Size(), after.Size())
+}
+
+func TestFilterTransfersByAmount(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	maxAmount := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	amounts := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(255),
+		big.NewInt(256),
+		new(big.Int).Lsh(big.NewInt(1), 64),
+		maxAmount,
+	}
+
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for _, amount := range amounts {
+		b = new(block.Builder).
+			ParentID(b.Header().ID()).
+			Transaction(newTx()).
+			Build()
+		receipt := &tx.Receipt{
+			Outputs: []*tx.Output{{
+				Transfers: tx.Transfers{{
+					Sender:    randAddress(),
+					Recipient: randAddress(),
+					Amount:    amount,
+				}},
+			}},
+		}
+		if err := w.Write(b, tx.Receipts{receipt}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		min  *big.Int
+		max  *big.Int
+		want []*big.Int
+	}{
+		{"no bounds", nil, nil, amounts},
+		{"min zero", big.NewInt(0), nil, amounts},
+		{"max zero", nil, big.NewInt(0), amounts[:1]},
+		{"min 255", big.NewInt(255), nil, amounts[2:]},
+		{"above one byte", big.NewInt(256), nil, amounts[3:]},
+		{"max 255", nil, big.NewInt(255), amounts[:3]},
+		{"range", big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 64), amounts[1:5]},
+		{"min max value", maxAmount, nil, amounts[5:]},
+		{"max max value", nil, maxAmount, amounts},
+		{"beyond max value", new(big.Int).Add(maxAmount, big.NewInt(1)), nil, nil},
+		{"empty range", big.NewInt(256), big.NewInt(255), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers, err := db.FilterTransfers(context.Background(), &logdb.TransferFilter{
+				MinAmount: tt.min,
+				MaxAmount: tt.max,
+			})
+			assert.Nil(t, err)
+
+			var got []*big.Int
+			for _, tr := range transfers {
+				got = append(got, tr.Amount)
+			}
+			assert.Equal(t, len(tt.want), len(got))
+			for i := range tt.want {
+				assert.Equal(t, 0, tt.want[i].Cmp(got[i]))
+			}
+		})
+	}
+
+	_, err = db.FilterTransfers(context.Background(), &logdb.TransferFilter{MinAmount: big.NewInt(-1)})
+	assert.EqualError(t, err, "negative min amount")
+}
+
+func TestKeysetPagination(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < 10; i++ {
+		b = new(block.Builder).
+			ParentID(b.Header().ID()).
+			Transaction(newTx()).
+			Transaction(newTx()).
+			Build()
+		if err := w.Write(b, tx.Receipts{newReceipt(), newReceipt()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	allEvents, err := db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	allTransfers, err := db.FilterTransfers(context.Background(), nil)
+	assert.Nil(t, err)
+
+	for _, order := range []logdb.Order{logdb.ASC, logdb.DESC} {
+		wantEvents, wantTransfers := eventLogs(allEvents), transferLogs(allTransfers)
+		if order == logdb.DESC {
+			wantEvents, wantTransfers = wantEvents.Reverse(), wantTransfers.Reverse()
+		}
+
+		var (
+			gotEvents eventLogs
+			after     *logdb.LogCursor
+		)
+		for {
+			page, err := db.FilterEvents(context.Background(), &logdb.EventFilter{
+				Options: &logdb.Options{Limit: 3, After: after},
+				Order:   order,
+			})
+			assert.Nil(t, err)
+			if len(page) == 0 {
+				break
+			}
+			gotEvents = append(gotEvents, page...)
+			// round trip through the opaque string
+			after, err = logdb.ParseLogCursor(page[len(page)-1].Cursor().String())
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, wantEvents, gotEvents, order)
+
+		var gotTransfers transferLogs
+		after = nil
+		for {
+			page, err := db.FilterTransfers(context.Background(), &logdb.TransferFilter{
+				Options: &logdb.Options{Limit: 3, After: after},
+				Order:   order,
+			})
+			assert.Nil(t, err)
+			if len(page) == 0 {
+				break
+			}
+			gotTransfers = append(gotTransfers, page...)
+			after = page[len(page)-1].Cursor()
+		}
+		assert.Equal(t, wantTransfers, gotTransfers, order)
+	}
+
+	// cursor in JSON
+	var opts logdb.Options
+	assert.Nil(t, json.Unmarshal([]byte(`{"limit":1,"after":"`+allEvents[2].Cursor().String()+`"}`), &opts))
+	assert.Equal(t, allEvents[2].Cursor(), opts.After)
+	assert.NotNil(t, json.Unmarshal([]byte(`{"after":"invalid"}`), &opts))
+
+	_, err = db.FilterEvents(context.Background(), &logdb.EventFilter{
+		Options: &logdb.Options{Limit: 1, After: &logdb.LogCursor{LogIndex: math.MaxUint32}},
+	})
+	assert.EqualError(t, err, "cursor log index too large")
+}
+
+func TestFilterTokenTransfers(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ev, _ := builtin.Energy.ABI.EventByName("Transfer")
+	assert.Equal(t, ev.ID(), logdb.TokenTransferTopic)
+
+	var (
+		token  = randAddress()
+		alice  = randAddress()
+		bob    = randAddress()
+		amount = func(v int64) []byte { return thor.BytesToBytes32(big.NewInt(v).Bytes()).Bytes() }
+		topic  = func(addr thor.Address) thor.Bytes32 { return thor.BytesToBytes32(addr.Bytes()) }
+	)
+	receipt := &tx.Receipt{Outputs: []*tx.Output{{Events: tx.Events{
+		{Address: token, Topics: []thor.Bytes32{logdb.TokenTransferTopic, topic(alice), topic(bob)}, Data: amount(1)},
+		{Address: token, Topics: []thor.Bytes32{logdb.TokenTransferTopic, topic(bob), topic(alice)}, Data: amount(2)},
+		// other token
+		{Address: randAddress(), Topics: []thor.Bytes32{logdb.TokenTransferTopic, topic(alice), topic(bob)}, Data: amount(3)},
+		// erc721 layout
+		{Address: token, Topics: []thor.Bytes32{logdb.TokenTransferTopic, topic(alice), topic(bob), randBytes32()}},
+	}}}}
+
+	b := new(block.Builder).Build()
+	b = new(block.Builder).ParentID(b.Header().ID()).Transaction(newTx()).Build()
+	w := db.NewWriter()
+	assert.Nil(t, w.Write(b, tx.Receipts{receipt}))
+	assert.Nil(t, w.Commit())
+
+	transfers, err := db.FilterTokenTransfers(context.Background(), token, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(transfers))
+	assert.Equal(t, token, transfers[0].Token)
+	assert.Equal(t, alice, transfers[0].From)
+	assert.Equal(t, bob, transfers[0].To)
+	assert.Equal(t, big.NewInt(1), transfers[0].Amount)
+	assert.Equal(t, b.Header().ID(), transfers[0].BlockID)
+	assert.Equal(t, big.NewInt(2), transfers[1].Amount)
+
+	transfers, err = db.FilterTokenTransfers(context.Background(), token, &bob, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(transfers))
+	assert.Equal(t, big.NewInt(2), transfers[0].Amount)
+
+	transfers, err = db.FilterTokenTransfers(context.Background(), token, &bob, &bob, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(transfers))
+
+	transfers, err = db.FilterTokenTransfers(context.Background(), token, nil, &bob, &logdb.Range{From: 0, To: b.Header().Number() - 1})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(transfers))
+}
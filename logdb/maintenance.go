@@ -0,0 +1,83 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/inconshreveable/log15"
+)
+
+var log = log15.New("pkg", "logdb")
+
+// maintainLoop runs maintenance at every interval until the db is closed.
+func (db *LogDB) maintainLoop(interval time.Duration, vacuumFreePages int) {
+	defer close(db.maintainDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.maintainStop:
+			return
+		case <-ticker.C:
+			if err := db.maintain(context.Background(), vacuumFreePages); err != nil {
+				log.Warn("failed to maintain log db", "err", err)
+			}
+		}
+	}
+}
+
+// maintain vacuums the database if there are at least vacuumFreePages free pages, which are left by deletes,
+// and then checkpoints the WAL and truncates it. Vacuum is disabled if vacuumFreePages is 0.
+// It's opportunistic, that the lock is not waited for, and the work is skipped if the database is busy.
+// Writes are blocked while vacuuming, since VACUUM rewrites the whole database.
+func (db *LogDB) maintain(ctx context.Context, vacuumFreePages int) (err error) {
+	conn, err := db.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var busyTimeout int
+	if err := conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA busy_timeout=0"); err != nil {
+		return err
+	}
+	// the connection is back to the pool later, so restore it
+	defer func() {
+		if _, err1 := conn.ExecContext(ctx, "PRAGMA busy_timeout="+strconv.Itoa(busyTimeout)); err == nil {
+			err = err1
+		}
+	}()
+
+	if vacuumFreePages > 0 {
+		var freePages int
+		if err := conn.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&freePages); err != nil {
+			return err
+		}
+		if freePages >= vacuumFreePages {
+			start := time.Now()
+			if _, err := conn.ExecContext(ctx, "VACUUM"); err != nil {
+				if !isTransientErr(err) {
+					return err
+				}
+				log.Debug("log db busy, vacuum skipped")
+			} else {
+				log.Info("log db vacuumed", "freePages", freePages, "elapsed", time.Since(start))
+			}
+		}
+	}
+
+	// a busy checkpoint doesn't fail, but is reported in the result row
+	var busy, walPages, checkpointed int
+	return conn.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &walPages, &checkpointed)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.db")
+	db, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	freePages := func() (n int) {
+		assert.Nil(t, db.db.QueryRow("PRAGMA freelist_count").Scan(&n))
+		return
+	}
+	walSize := func() int64 {
+		info, err := os.Stat(path + "-wal")
+		assert.Nil(t, err)
+		return info.Size()
+	}
+
+	_, err = db.db.Exec("CREATE TABLE junk (data BLOB)")
+	assert.Nil(t, err)
+	for i := 0; i < 100; i++ {
+		_, err = db.db.Exec("INSERT INTO junk VALUES (zeroblob(10000))")
+		assert.Nil(t, err)
+	}
+	_, err = db.db.Exec("DELETE FROM junk")
+	assert.Nil(t, err)
+	free := freePages()
+	assert.True(t, free > 0)
+	assert.True(t, walSize() > 0)
+
+	// below the threshold, only checkpoint
+	assert.Nil(t, db.maintain(context.Background(), free+1))
+	assert.Equal(t, free, freePages())
+	assert.Equal(t, int64(0), walSize())
+
+	assert.Nil(t, db.maintain(context.Background(), free))
+	assert.Equal(t, 0, freePages())
+	assert.Equal(t, int64(0), walSize())
+
+	// busy timeout restored
+	var busyTimeout int
+	assert.Nil(t, db.db.QueryRow("PRAGMA busy_timeout").Scan(&busyTimeout))
+	assert.True(t, busyTimeout > 0)
+}
+
+func TestMaintainLoop(t *testing.T) {
+	db, err := NewWithConfig(filepath.Join(t.TempDir(), "logs.db"), Config{MaintenanceInterval: time.Millisecond, VacuumFreePages: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	assert.Nil(t, db.Close())
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"errors"
+	"time"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+// defaultWriteRetryDelay is the delay before the first retry of a write, if not configured.
+const defaultWriteRetryDelay = 10 * time.Millisecond
+
+// retryPolicy retries writes failed with transient errors, with exponential backoff.
+type retryPolicy struct {
+	retries int
+	delay   time.Duration
+}
+
+// do runs op, and retries it on transient errors at most p.retries times.
+func (p retryPolicy) do(op func() error) error {
+	delay := p.delay
+	for i := 0; ; i++ {
+		err := op()
+		if err == nil || i >= p.retries || !isTransientErr(err) {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// isTransientErr returns whether the error is caused by the database or a table being locked by
+// other connections, which is likely to pass later.
+func isTransientErr(err error) bool {
+	var e sqlite3.Error
+	if errors.As(err, &e) {
+		return e.Code == sqlite3.ErrBusy || e.Code == sqlite3.ErrLocked
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	busy := sqlite3.Error{Code: sqlite3.ErrBusy}
+	locked := sqlite3.Error{Code: sqlite3.ErrLocked}
+
+	// fails with the given errors in turn, then succeeds
+	failing := func(errs ...error) (func() error, *int) {
+		calls := 0
+		return func() error {
+			calls++
+			if calls <= len(errs) {
+				return errs[calls-1]
+			}
+			return nil
+		}, &calls
+	}
+
+	t.Run("retried until succeeded", func(t *testing.T) {
+		op, calls := failing(busy, locked, fmt.Errorf("wrapped: %w", busy))
+		start := time.Now()
+		assert.Nil(t, retryPolicy{retries: 3, delay: time.Millisecond}.do(op))
+		assert.Equal(t, 4, *calls)
+		// backoff 1ms + 2ms + 4ms
+		assert.True(t, time.Since(start) >= 7*time.Millisecond)
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		op, calls := failing(busy, busy, busy)
+		assert.Equal(t, busy, retryPolicy{retries: 2, delay: time.Millisecond}.do(op))
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("no retry", func(t *testing.T) {
+		op, calls := failing(busy)
+		assert.Equal(t, busy, retryPolicy{}.do(op))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("non transient error", func(t *testing.T) {
+		other := errors.New("other")
+		op, calls := failing(other)
+		assert.Equal(t, other, retryPolicy{retries: 3, delay: time.Millisecond}.do(op))
+		assert.Equal(t, 1, *calls)
+
+		op, calls = failing(sqlite3.Error{Code: sqlite3.ErrConstraint})
+		assert.NotNil(t, retryPolicy{retries: 3, delay: time.Millisecond}.do(op))
+		assert.Equal(t, 1, *calls)
+	})
+}
@@ -32,7 +32,8 @@ CREATE INDEX IF NOT EXISTS event_i0 ON event(address);
 CREATE INDEX IF NOT EXISTS event_i1 ON event(topic0, address);
 CREATE INDEX IF NOT EXISTS event_i2 ON event(topic1, topic0, address) WHERE topic1 IS NOT NULL;
 CREATE INDEX IF NOT EXISTS event_i3 ON event(topic2, topic0, address) WHERE topic2 IS NOT NULL;
-CREATE INDEX IF NOT EXISTS event_i4 ON event(topic3, topic0, address) WHERE topic3 IS NOT NULL;`
+CREATE INDEX IF NOT EXISTS event_i4 ON event(topic3, topic0, address) WHERE topic3 IS NOT NULL;
+CREATE INDEX IF NOT EXISTS event_i5 ON event(topic0) WHERE topic0 IS NOT NULL;`
 
 	// create transfers table
 	transferTableSchema = `CREATE TABLE IF NOT EXISTS transfer (
@@ -6,24 +6,30 @@
 package logdb
 
 import (
+	"context"
 	"database/sql"
 	"sync"
 )
 
+// preparer is either *sql.DB or *sql.Conn, to which prepared statements are bound.
+type preparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
 // to cache prepared sql statement, which maps query string to stmt.
 type stmtCache struct {
-	db *sql.DB
+	db preparer
 	m  sync.Map
 }
 
-func newStmtCache(db *sql.DB) *stmtCache {
+func newStmtCache(db preparer) *stmtCache {
 	return &stmtCache{db: db}
 }
 
 func (sc *stmtCache) Prepare(query string) (*sql.Stmt, error) {
 	cached, _ := sc.m.Load(query)
 	if cached == nil {
-		stmt, err := sc.db.Prepare(query)
+		stmt, err := sc.db.PrepareContext(context.Background(), query)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,96 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/vechain/thor/v2/thor"
+)
+
+// TokenTransferTopic is the topic0 of the VIP-180 Transfer event, which is
+// `Transfer(address indexed _from, address indexed _to, uint256 _value)`.
+var TokenTransferTopic = thor.Keccak256([]byte("Transfer(address,address,uint256)"))
+
+// TokenTransfer represents a VIP-180 token transfer, parsed from the Transfer event.
+type TokenTransfer struct {
+	BlockNumber uint32
+	Index       uint32
+	BlockID     thor.Bytes32
+	BlockTime   uint64
+	TxID        thor.Bytes32
+	TxOrigin    thor.Address
+	ClauseIndex uint32
+	Token       thor.Address // the token contract
+	From        thor.Address
+	To          thor.Address
+	Amount      *big.Int
+}
+
+// Cursor returns the cursor pointing to the token transfer.
+func (t *TokenTransfer) Cursor() *LogCursor {
+	return &LogCursor{t.BlockNumber, t.Index}
+}
+
+// FilterTokenTransfers queries VIP-180 transfers of the token, in ascending order. The from and to
+// are optional, and all blocks are covered if rng is nil.
+// Transfer events of other layouts, e.g. the ERC-721 one with the indexed token id, are skipped.
+func (db *LogDB) FilterTokenTransfers(ctx context.Context, token thor.Address, from, to *thor.Address, rng *Range) ([]*TokenTransfer, error) {
+	criteria := &EventCriteria{Address: &token}
+	criteria.Topics[0] = &TokenTransferTopic
+	if from != nil {
+		topic := thor.BytesToBytes32(from.Bytes())
+		criteria.Topics[1] = &topic
+	}
+	if to != nil {
+		topic := thor.BytesToBytes32(to.Bytes())
+		criteria.Topics[2] = &topic
+	}
+
+	cursor, err := db.FilterEventsStream(ctx, &EventFilter{
+		CriteriaSet: []*EventCriteria{criteria},
+		Range:       rng,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close() }()
+
+	var transfers []*TokenTransfer
+	for {
+		event, ok, err := cursor.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return transfers, nil
+		}
+		if t := parseTokenTransfer(event); t != nil {
+			transfers = append(transfers, t)
+		}
+	}
+}
+
+// parseTokenTransfer parses the Transfer event, nil returned if it's not in the VIP-180 layout.
+func parseTokenTransfer(event *Event) *TokenTransfer {
+	if event.Topics[1] == nil || event.Topics[2] == nil || event.Topics[3] != nil || len(event.Data) != 32 {
+		return nil
+	}
+	return &TokenTransfer{
+		BlockNumber: event.BlockNumber,
+		Index:       event.Index,
+		BlockID:     event.BlockID,
+		BlockTime:   event.BlockTime,
+		TxID:        event.TxID,
+		TxOrigin:    event.TxOrigin,
+		ClauseIndex: event.ClauseIndex,
+		Token:       event.Address,
+		From:        thor.BytesToAddress(event.Topics[1][:]),
+		To:          thor.BytesToAddress(event.Topics[2][:]),
+		Amount:      new(big.Int).SetBytes(event.Data),
+	}
+}
@@ -6,7 +6,11 @@
 package logdb
 
 import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 
 	"github.com/vechain/thor/v2/thor"
@@ -26,6 +30,11 @@ type Event struct {
 	Data        []byte
 }
 
+// Cursor returns the cursor pointing to the event.
+func (e *Event) Cursor() *LogCursor {
+	return &LogCursor{e.BlockNumber, e.Index}
+}
+
 // Transfer represents tx.Transfer that can be stored in db.
 type Transfer struct {
 	BlockNumber uint32
@@ -40,6 +49,11 @@ type Transfer struct {
 	Amount      *big.Int
 }
 
+// Cursor returns the cursor pointing to the transfer.
+func (t *Transfer) Cursor() *LogCursor {
+	return &LogCursor{t.BlockNumber, t.Index}
+}
+
 type Order string
 
 const (
@@ -53,8 +67,64 @@ type Range struct {
 }
 
 type Options struct {
-	Offset uint64
+	Offset uint64 // discouraged for deep pagination, since skipped rows are still scanned, use After instead
 	Limit  uint64
+	After  *LogCursor // if set, only logs after the cursor in the given order are returned
+}
+
+// LogCursor is the position of a log, for keyset pagination.
+// It's encoded into an opaque string to be round-tripped by clients.
+type LogCursor struct {
+	BlockNumber uint32
+	LogIndex    uint32
+}
+
+// ParseLogCursor parses the cursor string returned by LogCursor.String.
+func ParseLogCursor(s string) (*LogCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(data) != 8 {
+		return nil, errors.New("invalid cursor")
+	}
+	seq := sequence(binary.BigEndian.Uint64(data))
+	if seq < 0 {
+		return nil, errors.New("invalid cursor")
+	}
+	return &LogCursor{seq.BlockNumber(), seq.Index()}, nil
+}
+
+func (c *LogCursor) sequence() (sequence, error) {
+	if c.LogIndex > math.MaxInt32 {
+		return 0, errors.New("cursor log index too large")
+	}
+	return newSequence(c.BlockNumber, c.LogIndex), nil
+}
+
+func (c *LogCursor) String() string {
+	seq, err := c.sequence()
+	if err != nil {
+		return ""
+	}
+	var data [8]byte
+	binary.BigEndian.PutUint64(data[:], uint64(seq))
+	return base64.RawURLEncoding.EncodeToString(data[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c *LogCursor) MarshalText() ([]byte, error) {
+	if _, err := c.sequence(); err != nil {
+		return nil, err
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *LogCursor) UnmarshalText(text []byte) error {
+	parsed, err := ParseLogCursor(string(text))
+	if err != nil {
+		return err
+	}
+	*c = *parsed
+	return nil
 }
 
 type EventCriteria struct {
@@ -80,6 +150,7 @@ func (c *EventCriteria) toWhereCondition() (cond string, args []interface{}) {
 // EventFilter filter
 type EventFilter struct {
 	CriteriaSet []*EventCriteria
+	Origin      *thor.Address // who sent the transaction that emitted the event
 	Range       *Range
 	Options     *Options
 	Order       Order //default asc
@@ -112,5 +183,16 @@ type TransferFilter struct {
 	CriteriaSet []*TransferCriteria
 	Range       *Range
 	Options     *Options
-	Order       Order //default asc
+	Order       Order    //default asc
+	MinAmount   *big.Int // inclusive, no lower bound if nil
+	MaxAmount   *big.Int // inclusive, no upper bound if nil
+}
+
+// Stats contains statistics of the log db.
+type Stats struct {
+	EventCount    uint64
+	TransferCount uint64
+	EarliestBlock uint32 // the earliest block number with logs
+	LatestBlock   uint32 // the latest block number with logs
+	FileSizeBytes int64  // size of db files on disk, 0 for in-memory db
 }
@@ -15,4 +15,6 @@ import (
 type Engine interface {
 	kv.Store
 	io.Closer
+	// Compact compacts the whole key space to reclaim disk space.
+	Compact() error
 }
@@ -42,6 +42,10 @@ func (ldb *levelEngine) Close() error {
 	return ldb.db.Close()
 }
 
+func (ldb *levelEngine) Compact() error {
+	return ldb.db.CompactRange(util.Range{})
+}
+
 func (ldb *levelEngine) IsNotFound(err error) bool {
 	return err == leveldb.ErrNotFound
 }
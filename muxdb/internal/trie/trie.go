@@ -7,6 +7,7 @@ package trie
 
 import (
 	"context"
+	"strings"
 
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
@@ -19,9 +20,12 @@ var log = log15.New("pkg", "muxdb.trie")
 
 // Backend is the backend of the trie.
 type Backend struct {
-	Store    kv.Store
-	Cache    *Cache
-	LeafBank *LeafBank
+	Store kv.Store
+	Cache *Cache
+	// PrefixedCaches are dedicated caches for tries with matched name prefix,
+	// which take precedence over Cache. The longest matched prefix wins.
+	PrefixedCaches []PrefixedCache
+	LeafBank       *LeafBank
 	HistSpace,
 	DedupedSpace byte
 	HistPtnFactor,
@@ -29,6 +33,29 @@ type Backend struct {
 	CachedNodeTTL uint16
 }
 
+// PrefixedCache is the cache dedicated to tries whose names have the prefix.
+type PrefixedCache struct {
+	NamePrefix string
+	Cache      *Cache
+}
+
+// cacheOf returns the cache for the trie of the given name.
+func (b *Backend) cacheOf(name string) *Cache {
+	var (
+		matched *Cache
+		n       = -1
+	)
+	for _, pc := range b.PrefixedCaches {
+		if len(pc.NamePrefix) > n && strings.HasPrefix(name, pc.NamePrefix) {
+			matched, n = pc.Cache, len(pc.NamePrefix)
+		}
+	}
+	if matched != nil {
+		return matched
+	}
+	return b.Cache
+}
+
 // sequence helps convert sequence number from/to commitNum & distinctNum.
 type sequence uint64
 
@@ -41,9 +68,10 @@ func (s sequence) DistinctNum() uint32 { return uint32(s >> 32) }
 
 // Trie is the managed trie.
 type Trie struct {
-	back *Backend
-	name string
-	ext  *trie.ExtendedTrie
+	back  *Backend
+	name  string
+	cache *Cache
+	ext   *trie.ExtendedTrie
 
 	dirty       bool
 	deletions   []string
@@ -61,12 +89,13 @@ func New(
 	nonCrypto bool,
 ) *Trie {
 	t := &Trie{
-		back: back,
-		name: name,
+		back:  back,
+		name:  name,
+		cache: back.cacheOf(name),
 	}
 
 	seq := makeSequence(commitNum, distinctNum)
-	if rootNode, ok := back.Cache.GetRootNode(name, uint64(seq), false); ok {
+	if rootNode, ok := t.cache.GetRootNode(name, uint64(seq), false); ok {
 		t.ext = trie.NewExtendedCached(rootNode, t.newDatabase(), nonCrypto)
 	} else {
 		t.ext = trie.NewExtended(root, uint64(seq), t.newDatabase(), nonCrypto)
@@ -117,12 +146,12 @@ func (t *Trie) newDatabase() trie.Database {
 	}{
 		databaseGetToFunc(func(_ []byte, dst []byte) (blob []byte, err error) {
 			// get from cache
-			if blob = t.back.Cache.GetNodeBlob(t.name, thisSeq, thisPath, t.noFillCache, dst); len(blob) > 0 {
+			if blob = t.cache.GetNodeBlob(t.name, thisSeq, thisPath, t.noFillCache, dst); len(blob) > 0 {
 				return
 			}
 			defer func() {
 				if err == nil && !t.noFillCache {
-					t.back.Cache.AddNodeBlob(t.name, thisSeq, thisPath, blob, false)
+					t.cache.AddNodeBlob(t.name, thisSeq, thisPath, blob, false)
 				}
 			}()
 
@@ -269,55 +298,87 @@ func (t *Trie) Update(key, val, meta []byte) error {
 
 // Stage processes trie updates and calculates the new root hash.
 func (t *Trie) Stage(newCommitNum, newDistinctNum uint32) (root thor.Bytes32, commit func() error) {
-	var (
-		thisPath []byte
-		bulk     = t.back.Store.Bulk()
-		buf      []byte
-	)
+	root, put, done := t.stage(newCommitNum, newDistinctNum)
+	commit = func() error {
+		bulk := t.back.Store.Bulk()
+		if err := put(bulk); err != nil {
+			return err
+		}
+		// real-commit, flush to db
+		if err := bulk.Write(); err != nil {
+			return err
+		}
+		done()
+		return nil
+	}
+	return
+}
 
+// StageTo is like Stage, but the returned commitTo writes the staged changes into the given putter
+// without flush. The putter should write the backend store directly, and the caller is responsible
+// to write it out. The trie is treated as committed once commitTo succeeds.
+func (t *Trie) StageTo(newCommitNum, newDistinctNum uint32) (root thor.Bytes32, commitTo func(putter kv.Putter) error) {
+	root, put, done := t.stage(newCommitNum, newDistinctNum)
+	commitTo = func(putter kv.Putter) error {
+		if err := put(putter); err != nil {
+			return err
+		}
+		done()
+		return nil
+	}
+	return
+}
+
+// stage hashes the copied trie. It returns the new root hash, the function to put the nodes
+// produced by committing the copied trie, and the function to mark the trie as committed.
+// Nothing is written or cached until put called.
+func (t *Trie) stage(newCommitNum, newDistinctNum uint32) (root thor.Bytes32, put func(putter kv.Putter) error, done func()) {
 	// make a copy of the original trie to perform commit.
 	// so later if real commit is discarded, the original trie will be in
 	// correct state.
 	extCpy := *t.ext
 	newSeq := makeSequence(newCommitNum, newDistinctNum)
 
-	db := &struct {
-		trie.DatabaseWriter
-		trie.DatabaseKeyEncoder
-	}{
-		kv.PutFunc(func(_, blob []byte) error {
-			buf = t.makeHistNodeKey(buf[:0], newSeq, thisPath)
-			if err := bulk.Put(buf, blob); err != nil {
-				return err
-			}
-			if !t.noFillCache {
-				t.back.Cache.AddNodeBlob(t.name, newSeq, thisPath, blob, true)
-			}
-			return nil
-		}),
-		databaseKeyEncodeFunc(func(hash []byte, seq uint64, path []byte) []byte {
-			thisPath = path
-			return nil
-		}),
-	}
-
-	// commit the copied trie without flush to db
-	root, err := extCpy.CommitTo(db, uint64(newSeq))
-	if err != nil {
-		return root, func() error { return err }
-	}
+	root = extCpy.Hash()
+
+	put = func(putter kv.Putter) error {
+		var (
+			thisPath []byte
+			buf      []byte
+		)
+		db := &struct {
+			trie.DatabaseWriter
+			trie.DatabaseKeyEncoder
+		}{
+			kv.PutFunc(func(_, blob []byte) error {
+				buf = t.makeHistNodeKey(buf[:0], newSeq, thisPath)
+				if err := putter.Put(buf, blob); err != nil {
+					return err
+				}
+				if !t.noFillCache {
+					t.cache.AddNodeBlob(t.name, newSeq, thisPath, blob, true)
+				}
+				return nil
+			}),
+			databaseKeyEncodeFunc(func(hash []byte, seq uint64, path []byte) []byte {
+				thisPath = path
+				return nil
+			}),
+		}
 
-	commit = func() error {
+		// commit the copied trie without flush to db
+		if _, err := extCpy.CommitTo(db, uint64(newSeq)); err != nil {
+			return err
+		}
 		if t.back.LeafBank != nil {
-			if err := t.back.LeafBank.LogDeletions(bulk, t.name, t.deletions, newCommitNum); err != nil {
+			if err := t.back.LeafBank.LogDeletions(putter, t.name, t.deletions, newCommitNum); err != nil {
 				return err
 			}
 		}
-		// real-commit, flush to db
-		if err := bulk.Write(); err != nil {
-			return err
-		}
+		return nil
+	}
 
+	done = func() {
 		t.dirty = false
 		t.deletions = t.deletions[:0]
 
@@ -325,9 +386,8 @@ func (t *Trie) Stage(newCommitNum, newDistinctNum uint32) (root thor.Bytes32, co
 		newRootNode := extCpy.RootNode()
 		t.ext.SetRootNode(newRootNode)
 		if !t.noFillCache {
-			t.back.Cache.AddRootNode(t.name, newRootNode)
+			t.cache.AddRootNode(t.name, newRootNode)
 		}
-		return nil
 	}
 	return
 }
@@ -418,8 +478,8 @@ func (t *Trie) DumpNodes(ctx context.Context, baseCommitNum uint32, handleLeaf f
 	return bulk.Write()
 }
 
-// CleanHistory cleans history nodes within [startCommitNum, limitCommitNum).
-func CleanHistory(ctx context.Context, back *Backend, startCommitNum, limitCommitNum uint32) error {
+// CleanHistory cleans history nodes within [startCommitNum, limitCommitNum), and returns the count of deleted nodes.
+func CleanHistory(ctx context.Context, back *Backend, startCommitNum, limitCommitNum uint32) (int, error) {
 	startPtn := startCommitNum / back.HistPtnFactor
 	limitPtn := limitCommitNum / back.HistPtnFactor
 	// preserve ptn 0 to make genesis state always visitable
@@ -427,10 +487,38 @@ func CleanHistory(ctx context.Context, back *Backend, startCommitNum, limitCommi
 		startPtn = 1
 	}
 
-	return back.Store.DeleteRange(ctx, kv.Range{
+	// like kv.Store.DeleteRange, but counts the deleted nodes
+	iter := back.Store.Iterate(kv.Range{
 		Start: appendUint32([]byte{back.HistSpace}, startPtn),
 		Limit: appendUint32([]byte{back.HistSpace}, limitPtn),
 	})
+	defer iter.Release()
+
+	bulk := back.Store.Bulk()
+	bulk.EnableAutoFlush()
+
+	cnt := 0
+	for iter.Next() {
+		cnt++
+		// check context every 1000 times.
+		if cnt%1000 == 0 {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			default:
+			}
+		}
+		if err := bulk.Delete(iter.Key()); err != nil {
+			return 0, err
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+	if err := bulk.Write(); err != nil {
+		return 0, err
+	}
+	return cnt, nil
 }
 
 // individual functions of trie database interface.
@@ -74,6 +74,33 @@ func TestTrie(t *testing.T) {
 		}
 	})
 
+	t.Run("stage without commit", func(t *testing.T) {
+		back := newBackend()
+		back.Cache = NewCache(1, 10)
+		tr := New(back, name, thor.Bytes32{}, 0, 0, false)
+		for i := 0; i < 100; i++ {
+			tr.Update([]byte(strconv.Itoa(i)), []byte("v"+strconv.Itoa(i)), nil)
+		}
+
+		// nodes are neither written nor cached until committed
+		root, commit := tr.Stage(1, 0)
+		assert.Nil(t, back.Cache.GetNodeBlob(name, makeSequence(1, 0), nil, true, nil))
+		has, err := back.Store.Has(tr.makeHistNodeKey(nil, makeSequence(1, 0), nil))
+		assert.Nil(t, err)
+		assert.False(t, has)
+
+		assert.Nil(t, commit())
+		assert.NotNil(t, back.Cache.GetNodeBlob(name, makeSequence(1, 0), nil, true, nil))
+		has, err = back.Store.Has(tr.makeHistNodeKey(nil, makeSequence(1, 0), nil))
+		assert.Nil(t, err)
+		assert.True(t, has)
+
+		tr = New(back, name, root, 1, 0, false)
+		val, _, err := tr.Get([]byte("1"))
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("v1"), val)
+	})
+
 	t.Run("fast get", func(t *testing.T) {
 		back := newBackend()
 		tr := New(back, name, thor.Bytes32{}, 0, 0, false)
@@ -119,4 +146,52 @@ func TestTrie(t *testing.T) {
 			}
 		}
 	})
+	t.Run("clean history", func(t *testing.T) {
+		back := newBackend()
+		tr := New(back, name, thor.Bytes32{}, 0, 0, false)
+
+		for i := 0; i < 3; i++ {
+			tr.Update([]byte(strconv.Itoa(i)), []byte("v"), nil)
+			_, commit := tr.Stage(uint32(i), 0)
+			if err := commit(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		n, err := CleanHistory(context.Background(), back, 1, 3)
+		assert.Nil(t, err)
+		assert.True(t, n > 0)
+
+		// already cleaned
+		n, err = CleanHistory(context.Background(), back, 1, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, n)
+	})
+}
+
+func TestPrefixedCaches(t *testing.T) {
+	back := newBackend()
+	back.Cache = NewCache(1, 16)
+	back.PrefixedCaches = []PrefixedCache{
+		{"s", NewCache(1, 16)},
+		{"s1", NewCache(1, 16)},
+	}
+
+	assert.Equal(t, back.Cache, back.cacheOf("a"))
+	assert.Equal(t, back.Cache, back.cacheOf(""))
+	assert.Equal(t, back.PrefixedCaches[0].Cache, back.cacheOf("s0"))
+	assert.Equal(t, back.PrefixedCaches[1].Cache, back.cacheOf("s12"), "longest prefix should win")
+
+	tr := New(back, "s0", thor.Bytes32{}, 0, 0, false)
+	tr.Update([]byte("key"), []byte("value"), nil)
+	_, commit := tr.Stage(1, 0)
+	assert.Nil(t, commit())
+
+	_, ok := back.PrefixedCaches[0].Cache.GetRootNode("s0", uint64(makeSequence(1, 0)), true)
+	assert.True(t, ok, "root node should be cached in the prefixed cache")
+	_, ok = back.Cache.GetRootNode("s0", uint64(makeSequence(1, 0)), true)
+	assert.False(t, ok, "root node should not be cached in the shared cache")
+
+	cpy := tr.Copy()
+	assert.Equal(t, back.PrefixedCaches[0].Cache, cpy.cache)
 }
@@ -10,7 +10,9 @@ package muxdb
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
+	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
 	dberrors "github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
@@ -41,6 +43,9 @@ type Trie = trie.Trie
 type Options struct {
 	// TrieNodeCacheSizeMB is the size of the cache for trie node blobs.
 	TrieNodeCacheSizeMB int
+	// TrieNodeCachePartitions dedicates parts of the trie node cache, in MB, to tries with the
+	// keyed name prefix. The rest of TrieNodeCacheSizeMB is shared by other tries.
+	TrieNodeCachePartitions map[string]int
 	// TrieRootCacheCapacity is the capacity of the cache for trie root nodes.
 	TrieRootCacheCapacity int
 	// TrieCachedNodeTTL defines the life time(times of commit) of cached trie nodes.
@@ -60,6 +65,8 @@ type Options struct {
 	ReadCacheMB int
 	// WriteBufferMB is the size of write buffer for underlying database.
 	WriteBufferMB int
+	// ReadOnly opens the underlying database in read-only mode, all writes will fail.
+	ReadOnly bool
 }
 
 // MuxDB is the database to efficiently store state trie and block-chain data.
@@ -70,6 +77,17 @@ type MuxDB struct {
 
 // Open opens or creates DB at the given path.
 func Open(path string, options *Options) (*MuxDB, error) {
+	sharedCacheMB := options.TrieNodeCacheSizeMB
+	for prefix, sizeMB := range options.TrieNodeCachePartitions {
+		if sizeMB < 0 {
+			return nil, fmt.Errorf("negative trie node cache size for prefix %q", prefix)
+		}
+		sharedCacheMB -= sizeMB
+	}
+	if sharedCacheMB < 0 {
+		return nil, errors.New("trie node cache partitions exceed the total size")
+	}
+
 	// prepare leveldb options
 	ldbOpts := opt.Options{
 		OpenFilesCacheCapacity: options.OpenFilesCacheCapacity,
@@ -78,6 +96,7 @@ func Open(path string, options *Options) (*MuxDB, error) {
 		Filter:                 filter.NewBloomFilter(10),
 		BlockSize:              1024 * 32, // balance performance of point reads and compression ratio.
 		CompactionTableSize:    4 * opt.MiB,
+		ReadOnly:               options.ReadOnly,
 	}
 
 	if options.TrieWillCleanHistory {
@@ -110,9 +129,17 @@ func Open(path string, options *Options) (*MuxDB, error) {
 	}
 
 	trieCache := trie.NewCache(
-		options.TrieNodeCacheSizeMB,
+		sharedCacheMB,
 		options.TrieRootCacheCapacity)
 
+	triePrefixedCaches := make([]trie.PrefixedCache, 0, len(options.TrieNodeCachePartitions))
+	for prefix, sizeMB := range options.TrieNodeCachePartitions {
+		triePrefixedCaches = append(triePrefixedCaches, trie.PrefixedCache{
+			NamePrefix: prefix,
+			Cache:      trie.NewCache(sizeMB, options.TrieRootCacheCapacity),
+		})
+	}
+
 	trieLeafBank := trie.NewLeafBank(
 		engine,
 		trieLeafBankSpace,
@@ -123,6 +150,7 @@ func Open(path string, options *Options) (*MuxDB, error) {
 		trieBackend: &trie.Backend{
 			Store:            engine,
 			Cache:            trieCache,
+			PrefixedCaches:   triePrefixedCaches,
 			LeafBank:         trieLeafBank,
 			HistSpace:        trieHistSpace,
 			DedupedSpace:     trieDedupedSpace,
@@ -189,16 +217,33 @@ func (db *MuxDB) NewNonCryptoTrie(name string, root thor.Bytes32, commitNum, dis
 	)
 }
 
-// CleanTrieHistory clean trie history within [startCommitNum, limitCommitNum).
-func (db *MuxDB) CleanTrieHistory(ctx context.Context, startCommitNum, limitCommitNum uint32) error {
+// CleanTrieHistory clean trie history within [startCommitNum, limitCommitNum), and returns the count of deleted nodes.
+func (db *MuxDB) CleanTrieHistory(ctx context.Context, startCommitNum, limitCommitNum uint32) (int, error) {
 	return trie.CleanHistory(ctx, db.trieBackend, startCommitNum, limitCommitNum)
 }
 
+// Compact compacts the whole underlying database, to get the space freed by deletions reclaimed.
+// It blocks until done, and may take a long time for a large database.
+func (db *MuxDB) Compact() error {
+	return db.engine.Compact()
+}
+
 // NewStore creates named kv-store.
 func (db *MuxDB) NewStore(name string) kv.Store {
 	return kv.Bucket(string(namedStoreSpace) + name).NewStore(db.engine)
 }
 
+// NewBulk creates a bulk which writes the underlying database directly.
+// It is used to write tries and named stores in one atomic batch, see Trie.StageTo and NewStorePutter.
+func (db *MuxDB) NewBulk() kv.Bulk {
+	return db.engine.Bulk()
+}
+
+// NewStorePutter creates putter of the named kv-store, which puts into the bulk created by NewBulk.
+func (db *MuxDB) NewStorePutter(name string, bulk kv.Putter) kv.Putter {
+	return kv.Bucket(string(namedStoreSpace) + name).NewPutter(bulk)
+}
+
 // IsNotFound returns if the error indicates key not found.
 func (db *MuxDB) IsNotFound(err error) bool {
 	return db.engine.IsNotFound(err)
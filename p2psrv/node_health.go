@@ -0,0 +1,117 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package p2psrv
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+// NodeHealth is the connection health of a tracked node.
+type NodeHealth struct {
+	Node        *discover.Node
+	Connected   bool
+	Successes   uint64    // count of successful dials
+	Failures    uint64    // count of failed dials
+	LastSuccess time.Time // zero if never connected
+	LastFailure time.Time // zero if never failed
+	LastError   string
+}
+
+// nodeHealthTracker tracks the connection health of the given nodes.
+type nodeHealthTracker struct {
+	lock      sync.Mutex
+	nodes     []*NodeHealth // in the given order
+	m         map[discover.NodeID]*NodeHealth
+	startTime time.Time
+}
+
+func newNodeHealthTracker(nodes Nodes) *nodeHealthTracker {
+	t := &nodeHealthTracker{
+		m:         make(map[discover.NodeID]*NodeHealth),
+		startTime: time.Now(),
+	}
+	for _, node := range nodes {
+		if _, ok := t.m[node.ID]; ok {
+			continue
+		}
+		h := &NodeHealth{Node: node}
+		t.nodes = append(t.nodes, h)
+		t.m[node.ID] = h
+	}
+	return t
+}
+
+func (t *nodeHealthTracker) Contains(id discover.NodeID) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.m[id] != nil
+}
+
+// DialDone records the result of a dial. Failures of dialing a connected node are ignored,
+// since they are duplicated connections.
+func (t *nodeHealthTracker) DialDone(id discover.NodeID, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	h := t.m[id]
+	if h == nil {
+		return
+	}
+	if err == nil {
+		h.Successes++
+		h.LastSuccess = time.Now()
+	} else if !h.Connected {
+		h.Failures++
+		h.LastFailure = time.Now()
+		h.LastError = err.Error()
+	}
+}
+
+// SetConnected records the node connected or disconnected, inbound or outbound.
+func (t *nodeHealthTracker) SetConnected(id discover.NodeID, connected bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if h := t.m[id]; h != nil {
+		h.Connected = connected
+		if connected {
+			h.LastSuccess = time.Now()
+		}
+	}
+}
+
+// Health returns copies of health of all tracked nodes.
+func (t *nodeHealthTracker) Health() []*NodeHealth {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	list := make([]*NodeHealth, 0, len(t.nodes))
+	for _, h := range t.nodes {
+		cpy := *h
+		list = append(list, &cpy)
+	}
+	return list
+}
+
+// Unreachable returns how long none of the tracked nodes has been connected, 0 if any is connected
+// or there's no tracked node.
+func (t *nodeHealthTracker) Unreachable(now time.Time) time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if len(t.nodes) == 0 {
+		return 0
+	}
+	last := t.startTime
+	for _, h := range t.nodes {
+		if h.Connected {
+			return 0
+		}
+		if h.LastSuccess.After(last) {
+			last = h.LastSuccess
+		}
+	}
+	return now.Sub(last)
+}
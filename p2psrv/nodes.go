@@ -74,3 +74,27 @@ func (nm *nodeMap) Len() int {
 	defer nm.lock.Unlock()
 	return len(nm.m)
 }
+
+// Replace replaces all nodes, and returns the added and removed ones.
+func (nm *nodeMap) Replace(nodes []*discover.Node) (added, removed Nodes) {
+	nm.lock.Lock()
+	defer nm.lock.Unlock()
+
+	m := make(map[discover.NodeID]*discover.Node, len(nodes))
+	for _, node := range nodes {
+		if _, ok := m[node.ID]; ok {
+			continue
+		}
+		m[node.ID] = node
+		if nm.m[node.ID] == nil {
+			added = append(added, node)
+		}
+	}
+	for id, node := range nm.m {
+		if m[id] == nil {
+			removed = append(removed, node)
+		}
+	}
+	nm.m = m
+	return
+}
@@ -7,6 +7,7 @@ package p2psrv
 
 import (
 	"crypto/ecdsa"
+	"time"
 
 	"github.com/ethereum/go-ethereum/p2p/nat"
 	"github.com/ethereum/go-ethereum/p2p/netutil"
@@ -40,11 +41,23 @@ type Options struct {
 
 	KnownNodes Nodes
 
+	// AllowedNodes restricts peers, inbound or outbound, to the given nodes if not empty.
+	// It can be updated by Server.SetAllowedNodes.
+	AllowedNodes Nodes
+
 	// DiscoveryNodes are used to establish connectivity
 	// with the rest of the network using the V5 discovery
 	// protocol.
 	DiscoveryNodes Nodes
 
+	// TrackedNodes are nodes whose connection health is tracked, e.g. the bootstrap nodes.
+	// They are kept to be redialed until connected.
+	TrackedNodes Nodes
+
+	// TrackedNodesWarnAfter is the period after which a warning is logged if none of the tracked nodes
+	// is reachable. No warning if 0.
+	TrackedNodesWarnAfter time.Duration
+
 	// RemoteDiscoveryList is the url of remote dynamic discovery node list.
 	RemoteDiscoveryList string
 
@@ -58,6 +71,11 @@ type Options struct {
 	// Internet.
 	NAT nat.Interface
 
+	// AdvertisedPort is the port advertised to other nodes, if it's different from
+	// the listening port, e.g. behind a port-forwarding load balancer.
+	// Zero means the listening port.
+	AdvertisedPort int
+
 	// If NoDial is true, the server will not dial any peers.
 	NoDial bool
 }
@@ -36,8 +36,12 @@ type Server struct {
 	knownNodes      *cache.PrioCache
 	discoveredNodes *cache.RandCache
 	dialingNodes    *nodeMap
+	allowedNodes    *nodeMap // nil if not restricted
+	trackedNodes    *nodeHealthTracker
 }
 
+var errPeerNotAllowed = errors.New("peer not allowed")
+
 // New create a p2p server.
 func New(opts *Options) *Server {
 	knownNodes := cache.NewPrioCache(5)
@@ -46,6 +50,16 @@ func New(opts *Options) *Server {
 		knownNodes.Set(node.ID, node, 0)
 		discoveredNodes.Set(node.ID, node)
 	}
+	for _, node := range opts.TrackedNodes {
+		discoveredNodes.Set(node.ID, node)
+	}
+	var allowedNodes *nodeMap
+	if len(opts.AllowedNodes) > 0 {
+		allowedNodes = newNodeMap()
+		for _, node := range opts.AllowedNodes {
+			allowedNodes.Add(node)
+		}
+	}
 
 	return &Server{
 		opts: opts,
@@ -67,6 +81,8 @@ func New(opts *Options) *Server {
 		knownNodes:      knownNodes,
 		discoveredNodes: discoveredNodes,
 		dialingNodes:    newNodeMap(),
+		allowedNodes:    allowedNodes,
+		trackedNodes:    newNodeHealthTracker(opts.TrackedNodes),
 	}
 }
 
@@ -82,6 +98,10 @@ func (s *Server) Start(protocols []*p2p.Protocol, topic discv5.Topic) error {
 		cpy := *proto
 		run := cpy.Run
 		cpy.Run = func(peer *p2p.Peer, rw p2p.MsgReadWriter) (err error) {
+			if s.allowedNodes != nil && !s.allowedNodes.Contains(peer.ID()) {
+				s.dialingNodes.Remove(peer.ID())
+				return errPeerNotAllowed
+			}
 			dir := "outbound"
 			if peer.Inbound() {
 				dir = "inbound"
@@ -90,6 +110,7 @@ func (s *Server) Start(protocols []*p2p.Protocol, topic discv5.Topic) error {
 
 			log.Debug("peer connected")
 			metricConnectedPeers().Add(1)
+			s.trackedNodes.SetConnected(peer.ID(), true)
 
 			startTime := mclock.Now()
 			defer func() {
@@ -99,6 +120,7 @@ func (s *Server) Start(protocols []*p2p.Protocol, topic discv5.Topic) error {
 					s.knownNodes.Set(peer.ID(), node, float64(mclock.Now()-startTime))
 				}
 				metricConnectedPeers().Add(-1)
+				s.trackedNodes.SetConnected(peer.ID(), false)
 			}()
 			return run(peer, rw)
 		}
@@ -128,6 +150,9 @@ func (s *Server) Start(protocols []*p2p.Protocol, topic discv5.Topic) error {
 	log.Debug("start up", "self", s.Self())
 
 	s.goes.Go(s.dialLoop)
+	if s.opts.TrackedNodesWarnAfter > 0 && len(s.opts.TrackedNodes) > 0 {
+		s.goes.Go(s.trackedNodesLoop)
+	}
 	return nil
 }
 
@@ -163,6 +188,30 @@ func (s *Server) RemoveStatic(node *discover.Node) {
 	s.srv.RemovePeer(node)
 }
 
+// SetAllowedNodes replaces the allowed nodes of a running server, which is started with allowed nodes.
+// Peers no longer allowed are disconnected, and newly allowed nodes will be dialed.
+// It returns the added and removed nodes.
+func (s *Server) SetAllowedNodes(nodes Nodes) (added, removed Nodes, err error) {
+	if s.allowedNodes == nil {
+		return nil, nil, errors.New("not started with allowed nodes")
+	}
+	if len(nodes) == 0 {
+		return nil, nil, errors.New("empty allowed nodes")
+	}
+
+	added, removed = s.allowedNodes.Replace(nodes)
+	for _, node := range removed {
+		s.knownNodes.Remove(node.ID)
+		s.discoveredNodes.Remove(node.ID)
+		s.srv.RemovePeer(node)
+	}
+	for _, node := range added {
+		s.knownNodes.Set(node.ID, node, 0)
+		s.discoveredNodes.Set(node.ID, node)
+	}
+	return added, removed, nil
+}
+
 // NodeInfo gathers and returns a collection of metadata known about the host.
 func (s *Server) NodeInfo() *p2p.NodeInfo {
 	return s.srv.NodeInfo()
@@ -180,15 +229,22 @@ func (s *Server) listenDiscV5() (err error) {
 	}
 
 	realaddr := conn.LocalAddr().(*net.UDPAddr)
+	extPort := realaddr.Port
+	if s.opts.AdvertisedPort > 0 {
+		extPort = s.opts.AdvertisedPort
+	}
 	if s.opts.NAT != nil {
 		if !realaddr.IP.IsLoopback() {
-			s.goes.Go(func() { nat.Map(s.opts.NAT, s.done, "udp", realaddr.Port, realaddr.Port, "vechain discovery") })
+			intPort := realaddr.Port
+			s.goes.Go(func() { nat.Map(s.opts.NAT, s.done, "udp", extPort, intPort, "vechain discovery") })
 		}
 		// TODO: react to external IP changes over time.
 		if ext, err := s.opts.NAT.ExternalIP(); err == nil {
 			realaddr = &net.UDPAddr{IP: ext, Port: realaddr.Port}
 		}
 	}
+	// advertised as both udp and tcp port
+	realaddr = &net.UDPAddr{IP: realaddr.IP, Port: extPort}
 
 	network, err := discv5.ListenUDP(s.opts.PrivateKey, conn, realaddr, "", s.opts.NetRestrict)
 	if err != nil {
@@ -308,12 +364,18 @@ func (s *Server) dialLoop() {
 				metricDialingNewNode().Add(1)
 				defer metricDialingNewNode().Add(-1)
 
-				if err := s.tryDial(node); err != nil {
+				err := s.tryDial(node)
+				if err != nil {
 					s.dialingNodes.Remove(node.ID)
 					log.Debug("failed to dial node", "err", err)
 				}
+				s.trackedNodes.DialDone(node.ID, err)
 
-				s.discoveredNodes.Remove(node.ID)
+				// without discovery, known nodes are the only ones to dial, keep them to be redialed,
+				// so as tracked nodes
+				if !s.opts.NoDiscovery && !s.trackedNodes.Contains(node.ID) {
+					s.discoveredNodes.Remove(node.ID)
+				}
 			}()
 
 			dialCount++
@@ -323,6 +385,28 @@ func (s *Server) dialLoop() {
 	}
 }
 
+// trackedNodesLoop warns once every period if none of the tracked nodes is reachable.
+func (s *Server) trackedNodesLoop() {
+	period := s.opts.TrackedNodesWarnAfter
+	var lastWarn time.Time
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-time.After(time.Second * 10):
+			if d := s.trackedNodes.Unreachable(now); d >= period && now.Sub(lastWarn) >= period {
+				lastWarn = now
+				log.Warn("!!! none of the bootstrap nodes is reachable, check the network or the bootnodes", "for", d.Truncate(time.Second), "nodes", len(s.opts.TrackedNodes))
+			}
+		}
+	}
+}
+
+// TrackedNodes returns the connection health of the tracked nodes.
+func (s *Server) TrackedNodes() []*NodeHealth {
+	return s.trackedNodes.Health()
+}
+
 func (s *Server) tryDial(node *discover.Node) error {
 	conn, err := s.srv.Dialer.Dial(node)
 	if err != nil {
@@ -6,7 +6,9 @@
 package p2psrv
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/p2p/discover"
@@ -75,3 +77,82 @@ func TestNewServerConnectOnly(t *testing.T) {
 	assert.True(t, server.discoveredNodes.Contains(knownNode.ID))
 	assert.True(t, server.knownNodes.Contains(knownNode.ID))
 }
+
+func TestSetAllowedNodes(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Unable to generate private key: %v", err)
+	}
+	node1 := &discover.Node{ID: discover.NodeID{1}}
+	node2 := &discover.Node{ID: discover.NodeID{2}}
+	node3 := &discover.Node{ID: discover.NodeID{3}}
+
+	// not restricted
+	server := New(&Options{PrivateKey: privateKey, MaxPeers: 10, KnownNodes: Nodes{node1}})
+	_, _, err = server.SetAllowedNodes(Nodes{node1})
+	assert.Error(t, err)
+
+	server = New(&Options{
+		PrivateKey:   privateKey,
+		MaxPeers:     10,
+		ListenAddr:   "127.0.0.1:0",
+		NoDiscovery:  true,
+		KnownNodes:   Nodes{node1, node2},
+		AllowedNodes: Nodes{node1, node2},
+	})
+	if err := server.Start(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	_, _, err = server.SetAllowedNodes(nil)
+	assert.Error(t, err)
+
+	added, removed, err := server.SetAllowedNodes(Nodes{node2, node3})
+	assert.Nil(t, err)
+	assert.Equal(t, Nodes{node3}, added)
+	assert.Equal(t, Nodes{node1}, removed)
+
+	assert.False(t, server.allowedNodes.Contains(node1.ID))
+	assert.True(t, server.allowedNodes.Contains(node3.ID))
+	assert.False(t, server.knownNodes.Contains(node1.ID))
+	assert.True(t, server.knownNodes.Contains(node3.ID))
+}
+
+func TestNodeHealthTracker(t *testing.T) {
+	node := discover.MustParseNode("enode://1234cf28ab5f0255a3923ac094d0168ce884a9fa5f3998b1844986b4a2b1eac52fcccd8f2916be9b8b0f7798147ee5592ec3c83518925fac50f812577515d6ad@10.3.58.6:30303")
+	tracker := newNodeHealthTracker(Nodes{node, node})
+
+	assert.Len(t, tracker.Health(), 1, "duplicated nodes should be removed")
+	assert.True(t, tracker.Contains(node.ID))
+	assert.False(t, tracker.Contains(discover.NodeID{}))
+
+	now := tracker.startTime.Add(time.Minute)
+	assert.Equal(t, time.Minute, tracker.Unreachable(now))
+
+	tracker.DialDone(node.ID, errors.New("i/o timeout"))
+	h := tracker.Health()[0]
+	assert.Equal(t, uint64(1), h.Failures)
+	assert.Equal(t, "i/o timeout", h.LastError)
+	assert.False(t, h.LastFailure.IsZero())
+
+	tracker.DialDone(node.ID, nil)
+	tracker.SetConnected(node.ID, true)
+	assert.Equal(t, time.Duration(0), tracker.Unreachable(now))
+
+	// failures dialing a connected node are ignored
+	tracker.DialDone(node.ID, errors.New("already connected"))
+	h = tracker.Health()[0]
+	assert.Equal(t, uint64(1), h.Successes)
+	assert.Equal(t, uint64(1), h.Failures)
+	assert.True(t, h.Connected)
+
+	tracker.SetConnected(node.ID, false)
+	assert.True(t, tracker.Unreachable(h.LastSuccess.Add(time.Second)) == time.Second)
+
+	// untracked nodes are ignored
+	tracker.DialDone(discover.NodeID{}, nil)
+	assert.Len(t, tracker.Health(), 1)
+
+	assert.Equal(t, time.Duration(0), newNodeHealthTracker(nil).Unreachable(now))
+}
@@ -28,6 +28,8 @@ type Flow struct {
 	txs          tx.Transactions
 	receipts     tx.Receipts
 	features     tx.Features
+
+	beforeParent bool // the time scheduled at is before the parent block time
 }
 
 func newFlow(
@@ -60,6 +62,11 @@ func (f *Flow) When() uint64 {
 	return f.runtime.Context().Time
 }
 
+// IsBeforeParent returns if the time scheduled at is before the parent block time, which is likely a clock error.
+func (f *Flow) IsBeforeParent() bool {
+	return f.beforeParent
+}
+
 // TotalScore returns total score of new block.
 func (f *Flow) TotalScore() uint64 {
 	return f.runtime.Context().TotalScore
@@ -17,15 +17,20 @@ import (
 	"github.com/vechain/thor/v2/xenv"
 )
 
+// BeneficiaryFunc returns the beneficiary of the block to be packed with the given number.
+// Returning the zero address means to use the default beneficiary.
+type BeneficiaryFunc func(blockNumber uint32) thor.Address
+
 // Packer to pack txs and build new blocks.
 type Packer struct {
-	repo           *chain.Repository
-	stater         *state.Stater
-	nodeMaster     thor.Address
-	beneficiary    *thor.Address
-	targetGasLimit uint64
-	forkConfig     thor.ForkConfig
-	seeder         *poa.Seeder
+	repo            *chain.Repository
+	stater          *state.Stater
+	nodeMaster      thor.Address
+	beneficiary     *thor.Address
+	beneficiaryFunc BeneficiaryFunc
+	targetGasLimit  uint64
+	forkConfig      thor.ForkConfig
+	seeder          *poa.Seeder
 }
 
 // New create a new Packer instance.
@@ -42,6 +47,7 @@ func New(
 		stater,
 		nodeMaster,
 		beneficiary,
+		nil,
 		0,
 		forkConfig,
 		poa.NewSeeder(repo),
@@ -95,6 +101,11 @@ func (p *Packer) Schedule(parent *chain.BlockSummary, nowTimestamp uint64) (flow
 			Active:  c.Active,
 		})
 	}
+	if p.beneficiaryFunc != nil {
+		if b := p.beneficiaryFunc(parent.Header.Number() + 1); !b.IsZero() {
+			beneficiary = b
+		}
+	}
 
 	// calc the time when it's turn to produce block
 	var sched poa.Scheduler
@@ -134,7 +145,9 @@ func (p *Packer) Schedule(parent *chain.BlockSummary, nowTimestamp uint64) (flow
 		},
 		p.forkConfig)
 
-	return newFlow(p, parent.Header, rt, features), nil
+	flow = newFlow(p, parent.Header, rt, features)
+	flow.beforeParent = sched.IsBeforeParent(nowTimestamp)
+	return flow, nil
 }
 
 // Mock create a packing flow upon given parent, but with a designated timestamp.
@@ -181,3 +194,9 @@ func (p *Packer) gasLimit(parentGasLimit uint64) uint64 {
 func (p *Packer) SetTargetGasLimit(gl uint64) {
 	p.targetGasLimit = gl
 }
+
+// SetBeneficiaryFunc set the func to determine beneficiary per block, which overrides the static one.
+// The default beneficiary is used if the func returns the zero address.
+func (p *Packer) SetBeneficiaryFunc(fn BeneficiaryFunc) {
+	p.beneficiaryFunc = fn
+}
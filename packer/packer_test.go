@@ -270,3 +270,40 @@ func TestSetGasLimit(t *testing.T) {
 	// This is just for code coverage purposes. There is no getter function for targetGasLimit to test the function.
 	p.SetTargetGasLimit(0xFFFF)
 }
+
+func TestSetBeneficiaryFunc(t *testing.T) {
+	db := muxdb.NewMem()
+
+	g := genesis.NewDevnet()
+	stater := state.NewStater(db)
+	b0, _, _, _ := g.Build(stater)
+	repo, _ := chain.NewRepository(db, b0)
+
+	a0 := genesis.DevAccounts()[0]
+	a1 := genesis.DevAccounts()[1]
+
+	p := packer.New(repo, stater, a0.Address, &a0.Address, thor.NoFork)
+	schedule := func() thor.Address {
+		flow, err := p.Schedule(repo.BestBlockSummary(), uint64(time.Now().Unix()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blk, _, _, err := flow.Pack(a0.PrivateKey, 0, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return blk.Header().Beneficiary()
+	}
+
+	var number uint32
+	p.SetBeneficiaryFunc(func(blockNumber uint32) thor.Address {
+		number = blockNumber
+		return a1.Address
+	})
+	assert.Equal(t, a1.Address, schedule())
+	assert.Equal(t, uint32(1), number)
+
+	// falls back to the static one
+	p.SetBeneficiaryFunc(func(uint32) thor.Address { return thor.Address{} })
+	assert.Equal(t, a0.Address, schedule())
+}
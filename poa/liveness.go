@@ -0,0 +1,79 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa
+
+import (
+	"github.com/vechain/thor/v2/thor"
+)
+
+// ProducedBlock is a produced block post VIP-214, along with what's needed to recover its schedule.
+type ProducedBlock struct {
+	Proposer     thor.Address
+	Timestamp    uint64
+	ParentNumber uint32
+	ParentTime   uint64
+	Seed         []byte         // the seed generated upon the parent, see Seeder.Generate
+	Inactive     []thor.Address // proposers being absentees upon the parent, which are not scheduled
+}
+
+// LivenessScore computes the on-time production rate of each proposer over the blocks, which is the
+// fraction of its scheduled slots in which it produced a block, against the slots it was absent from.
+//
+// The schedule of each block is recovered by SchedulerV2, with the block validated against it. The
+// slots skipped between the parent and the block are attributed to the proposers scheduled for them,
+// each counted as an absence. Like the consensus marks absentees, only the skipped slots of the first
+// round are attributed, and a proposer is charged at most once per block, while the producer itself
+// is never charged. Inactive proposers are not scheduled, so no absence is charged until they're back.
+//
+// Blocks by unlisted proposers or off the schedule are ignored. Proposers without any slot attributed
+// are omitted from the result.
+func LivenessScore(proposers []thor.Address, blocks []ProducedBlock) map[thor.Address]float64 {
+	type tally struct {
+		onTime, absent uint64
+	}
+	tallies := make(map[thor.Address]*tally, len(proposers))
+	for _, addr := range proposers {
+		tallies[addr] = &tally{}
+	}
+
+	list := make([]Proposer, 0, len(proposers))
+	for _, b := range blocks {
+		list = list[:0]
+		for _, addr := range proposers {
+			list = append(list, Proposer{Address: addr, Active: !containsAddress(b.Inactive, addr)})
+		}
+
+		sched, err := NewSchedulerV2(b.Proposer, list, b.ParentNumber, b.ParentTime, b.Seed)
+		if err != nil || !sched.IsTheTime(b.Timestamp) {
+			continue
+		}
+		tallies[b.Proposer].onTime++
+
+		updates, _ := sched.Updates(b.Timestamp)
+		for _, u := range updates {
+			if !u.Active {
+				tallies[u.Address].absent++
+			}
+		}
+	}
+
+	scores := make(map[thor.Address]float64, len(tallies))
+	for addr, t := range tallies {
+		if total := t.onTime + t.absent; total > 0 {
+			scores[addr] = float64(t.onTime) / float64(total)
+		}
+	}
+	return scores
+}
+
+func containsAddress(addrs []thor.Address, addr thor.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
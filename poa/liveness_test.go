@@ -0,0 +1,63 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestLivenessScore(t *testing.T) {
+	const T = thor.BlockInterval
+	var (
+		proposers = []thor.Address{p1, p2, p3, p4}
+		seed      = []byte("seed")
+	)
+
+	// orderOf returns the shuffled order of active proposers upon the parent #1 at time 0, p1 always active
+	orderOf := func(inactive ...thor.Address) []thor.Address {
+		list := make([]Proposer, 0, len(proposers))
+		for _, addr := range proposers {
+			list = append(list, Proposer{Address: addr, Active: !containsAddress(inactive, addr)})
+		}
+		sched, err := NewSchedulerV2(p1, list, 1, 0, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sched.RoundOrder(0)
+	}
+	block := func(proposer thor.Address, slot uint64, inactive ...thor.Address) ProducedBlock {
+		return ProducedBlock{
+			Proposer:     proposer,
+			Timestamp:    (slot + 1) * T,
+			ParentNumber: 1,
+			ParentTime:   0,
+			Seed:         seed,
+			Inactive:     inactive,
+		}
+	}
+
+	// pin the shuffled orders, which the expected scores depend on
+	assert.Equal(t, []thor.Address{p1, p3, p2, p4}, orderOf())
+	assert.Equal(t, []thor.Address{p1, p2, p4}, orderOf(p3))
+
+	scores := LivenessScore(proposers, []ProducedBlock{
+		block(p1, 0),     // on time
+		block(p2, 2),     // p1 and p3 absent
+		block(p3, 1),     // p1 absent
+		block(p1, 3),     // off the schedule, ignored
+		block(p5, 0),     // unlisted, ignored
+		block(p2, 1, p3), // p1 absent, while p3 is inactive thus not scheduled
+	})
+
+	assert.Equal(t, map[thor.Address]float64{
+		p1: 1.0 / 4,
+		p2: 1,
+		p3: 1.0 / 2,
+	}, scores, "p4 has no slot attributed")
+}
@@ -15,6 +15,7 @@ import (
 // Scheduler defines the interface of schedulers.
 type Scheduler interface {
 	Schedule(nowTime uint64) (newBlockTime uint64)
+	IsBeforeParent(nowTime uint64) bool
 	IsTheTime(newBlockTime uint64) bool
 	Updates(newBlockTime uint64) (updates []Proposer, score uint64)
 }
@@ -91,6 +92,12 @@ func (s *SchedulerV1) Schedule(nowTime uint64) (newBlockTime uint64) {
 	}
 }
 
+// IsBeforeParent returns if nowTime precedes the parent block time, which is likely a clock error,
+// and the new block will be scheduled far in the future.
+func (s *SchedulerV1) IsBeforeParent(nowTime uint64) bool {
+	return nowTime < s.parentBlockTime
+}
+
 // IsTheTime returns if the newBlockTime is correct for the proposer.
 func (s *SchedulerV1) IsTheTime(newBlockTime uint64) bool {
 	if s.parentBlockTime >= newBlockTime {
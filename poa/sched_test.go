@@ -47,6 +47,20 @@ func TestSchedule(t *testing.T) {
 	}
 }
 
+func TestIsBeforeParent(t *testing.T) {
+	v1, _ := poa.NewSchedulerV1(p1, proposers, 1, parentTime)
+	v2, _ := poa.NewSchedulerV2(p2, proposers, 1, parentTime, nil)
+
+	for _, sched := range []poa.Scheduler{v1, v2} {
+		assert.True(t, sched.IsBeforeParent(parentTime-1))
+		assert.False(t, sched.IsBeforeParent(parentTime))
+		assert.False(t, sched.IsBeforeParent(parentTime+thor.BlockInterval))
+
+		// still scheduled after the parent
+		assert.True(t, sched.Schedule(parentTime-thor.BlockInterval) > parentTime)
+	}
+}
+
 func TestIsTheTime(t *testing.T) {
 	sched, _ := poa.NewSchedulerV1(p2, proposers, 1, parentTime)
 
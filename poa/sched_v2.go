@@ -9,6 +9,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"math"
 	"sort"
 
 	"github.com/vechain/thor/v2/thor"
@@ -24,6 +26,25 @@ type SchedulerV2 struct {
 
 var _ Scheduler = (*SchedulerV2)(nil)
 
+// Shuffler permutes the proposers list of a round, in which a weighted proposer appears as many times as its
+// weight, consecutively. It must return a permutation of the list.
+type Shuffler func(list []thor.Address, seed []byte, parentBlockNumber uint32) []thor.Address
+
+// SchedulerOption is the option to create a SchedulerV2.
+type SchedulerOption func(*schedulerOptions)
+
+type schedulerOptions struct {
+	shuffler Shuffler
+}
+
+// WithShuffler overrides the default shuffler, which orders proposers by hash of seed, parent block number
+// and address. It's for testing only, since the resulted schedule differs from the consensus one.
+func WithShuffler(shuffler Shuffler) SchedulerOption {
+	return func(o *schedulerOptions) {
+		o.shuffler = shuffler
+	}
+}
+
 // NewSchedulerV2 create a SchedulerV2 object.
 // `addr` is the proposer to be scheduled.
 // If `addr` is not listed in `proposers` or not active, an error returned.
@@ -32,31 +53,92 @@ func NewSchedulerV2(
 	proposers []Proposer,
 	parentBlockNumber uint32,
 	parentBlockTime uint64,
-	seed []byte) (*SchedulerV2, error) {
+	seed []byte,
+	opts ...SchedulerOption) (*SchedulerV2, error) {
+	return newSchedulerV2(addr, proposers, nil, parentBlockNumber, parentBlockTime, seed, opts)
+}
+
+// MaxWeightedSlots is the max count of time slots in a round of the weighted SchedulerV2, which is the sum
+// of normalized weights.
+const MaxWeightedSlots = 1 << 16
+
+// NewSchedulerV2Weighted create a SchedulerV2 object, in which proposers get time slots in proportion to `weights`.
+// Each weight is for the proposer at the same index, and weights are normalized by their greatest common divisor,
+// so the schedule is identical to NewSchedulerV2 if all weights are equal.
+// An error returned if the sum of normalized weights exceeds MaxWeightedSlots.
+func NewSchedulerV2Weighted(
+	addr thor.Address,
+	proposers []Proposer,
+	weights []uint64,
+	parentBlockNumber uint32,
+	parentBlockTime uint64,
+	seed []byte,
+	opts ...SchedulerOption) (*SchedulerV2, error) {
+	if len(weights) != len(proposers) {
+		return nil, errors.New("weights and proposers length mismatch")
+	}
+	var d uint64
+	for _, w := range weights {
+		if w == 0 {
+			return nil, errors.New("zero proposer weight")
+		}
+		d = gcd(d, w)
+	}
+
+	var (
+		normalized = make([]uint64, 0, len(weights))
+		total      uint64
+	)
+	for _, w := range weights {
+		w /= d
+		// both operands are bounded, so the sum never overflows
+		if w > MaxWeightedSlots || total+w > MaxWeightedSlots {
+			return nil, fmt.Errorf("normalized weights exceed the limit of %d slots", MaxWeightedSlots)
+		}
+		total += w
+		normalized = append(normalized, w)
+	}
+	return newSchedulerV2(addr, proposers, normalized, parentBlockNumber, parentBlockTime, seed, opts)
+}
+
+// newSchedulerV2 shuffles the proposers, each proposer is expanded into `weights[i]` entries
+// before shuffling. nil weights means all one.
+func newSchedulerV2(
+	addr thor.Address,
+	proposers []Proposer,
+	weights []uint64,
+	parentBlockNumber uint32,
+	parentBlockTime uint64,
+	seed []byte,
+	opts []SchedulerOption) (*SchedulerV2, error) {
+	options := schedulerOptions{shuffler: defaultShuffler}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	var (
 		listed   = false
 		proposer Proposer
-		list     []struct {
-			addr thor.Address
-			hash thor.Bytes32
-		}
 	)
-	var num [4]byte
-	binary.BigEndian.PutUint32(num[:], parentBlockNumber)
 
-	for _, p := range proposers {
+	size := len(proposers)
+	for _, w := range weights {
+		size += int(w) - 1
+	}
+	list := make([]thor.Address, 0, size)
+
+	for i, p := range proposers {
 		if p.Address == addr {
 			proposer = p
 			listed = true
 		}
 		if p.Active || p.Address == addr {
-			list = append(list, struct {
-				addr thor.Address
-				hash thor.Bytes32
-			}{
-				p.Address,
-				thor.Blake2b(seed, num[:], p.Address.Bytes()),
-			})
+			list = append(list, p.Address)
+			if weights != nil {
+				for j := uint64(1); j < weights[i]; j++ {
+					list = append(list, p.Address)
+				}
+			}
 		}
 	}
 
@@ -64,13 +146,9 @@ func NewSchedulerV2(
 		return nil, errors.New("unauthorized block proposer")
 	}
 
-	sort.Slice(list, func(i, j int) bool {
-		return bytes.Compare(list[i].hash.Bytes(), list[j].hash.Bytes()) < 0
-	})
-
-	shuffled := make([]thor.Address, 0, len(list))
-	for _, t := range list {
-		shuffled = append(shuffled, t.addr)
+	shuffled := options.shuffler(list, seed, parentBlockNumber)
+	if len(shuffled) != len(list) {
+		return nil, errors.New("shuffler changed the proposers count")
 	}
 
 	return &SchedulerV2{
@@ -80,16 +158,56 @@ func NewSchedulerV2(
 	}, nil
 }
 
+// defaultShuffler sorts the list by hash of seed, parent block number and address.
+// The extra entries of a weighted proposer are hashed along with the entry index.
+func defaultShuffler(list []thor.Address, seed []byte, parentBlockNumber uint32) []thor.Address {
+	var num [4]byte
+	binary.BigEndian.PutUint32(num[:], parentBlockNumber)
+
+	entries := make([]struct {
+		addr thor.Address
+		hash thor.Bytes32
+	}, 0, len(list))
+
+	var j uint64
+	for i, addr := range list {
+		if i > 0 && list[i-1] == addr {
+			j++
+		} else {
+			j = 0
+		}
+
+		var hash thor.Bytes32
+		if j == 0 {
+			hash = thor.Blake2b(seed, num[:], addr.Bytes())
+		} else {
+			var index [8]byte
+			binary.BigEndian.PutUint64(index[:], j)
+			hash = thor.Blake2b(seed, num[:], addr.Bytes(), index[:])
+		}
+		entries = append(entries, struct {
+			addr thor.Address
+			hash thor.Bytes32
+		}{addr, hash})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].hash.Bytes(), entries[j].hash.Bytes()) < 0
+	})
+
+	shuffled := make([]thor.Address, 0, len(entries))
+	for _, e := range entries {
+		shuffled = append(shuffled, e.addr)
+	}
+	return shuffled
+}
+
 // Schedule to determine time of the proposer to produce a block, according to `nowTime`.
 // `newBlockTime` is promised to be >= nowTime and > parentBlockTime
 func (s *SchedulerV2) Schedule(nowTime uint64) (newBlockTime uint64) {
 	const T = thor.BlockInterval
 
-	newBlockTime = s.parentBlockTime + T
-	if nowTime > newBlockTime {
-		// ensure T aligned, and >= nowTime
-		newBlockTime += (nowTime - newBlockTime + T - 1) / T * T
-	}
+	newBlockTime = s.slotTime(nowTime)
 
 	offset := (newBlockTime-s.parentBlockTime)/T - 1
 	for i, n := uint64(0), uint64(len(s.shuffled)); i < n; i++ {
@@ -103,6 +221,47 @@ func (s *SchedulerV2) Schedule(nowTime uint64) (newBlockTime uint64) {
 	panic("something wrong with proposers list")
 }
 
+// NextProposer returns the proposer expected to produce the next block and the block time, according to `nowTime`.
+// The scheduled proposer is skipped if it's inactive, since it's an absentee until it brings itself active.
+// It falls back to the scheduled proposer if no other proposer is available.
+func (s *SchedulerV2) NextProposer(nowTime uint64) (thor.Address, uint64, error) {
+	const T = thor.BlockInterval
+
+	n := uint64(len(s.shuffled))
+	if n == 0 {
+		return thor.Address{}, 0, errors.New("no proposer scheduled")
+	}
+
+	blockTime := s.slotTime(nowTime)
+	offset := (blockTime-s.parentBlockTime)/T - 1
+	for i := uint64(0); i < n; i++ {
+		addr := s.shuffled[(i+offset)%n]
+		if addr != s.proposer.Address || s.proposer.Active {
+			return addr, blockTime + i*T, nil
+		}
+	}
+	// no active proposer, only the scheduled proposer is able to produce
+	return s.proposer.Address, s.Schedule(nowTime), nil
+}
+
+// slotTime returns the earliest time slot which is >= nowTime and > parentBlockTime.
+func (s *SchedulerV2) slotTime(nowTime uint64) uint64 {
+	const T = thor.BlockInterval
+
+	blockTime := s.parentBlockTime + T
+	if nowTime > blockTime {
+		// ensure T aligned, and >= nowTime
+		blockTime += (nowTime - blockTime + T - 1) / T * T
+	}
+	return blockTime
+}
+
+// IsBeforeParent returns if nowTime precedes the parent block time, which is likely a clock error,
+// and the new block will be scheduled far in the future.
+func (s *SchedulerV2) IsBeforeParent(nowTime uint64) bool {
+	return nowTime < s.parentBlockTime
+}
+
 // IsTheTime returns if the newBlockTime is correct for the proposer.
 func (s *SchedulerV2) IsTheTime(newBlockTime uint64) bool {
 	return s.IsScheduled(newBlockTime, s.proposer.Address)
@@ -125,20 +284,81 @@ func (s *SchedulerV2) IsScheduled(blockTime uint64, proposer thor.Address) bool
 	return s.shuffled[index] == proposer
 }
 
+// ScheduleEntry is a pair of proposer and block time, to be validated against the schedule.
+type ScheduleEntry struct {
+	Addr      thor.Address
+	Timestamp uint64
+}
+
+// ValidateBatch validates entries against the schedule, like IsScheduled does for each of them, sharing
+// the shuffled proposers list. It stops at the first invalid entry, and returns the results up to and
+// including it, along with an error.
+func (s *SchedulerV2) ValidateBatch(entries []ScheduleEntry) ([]bool, error) {
+	results := make([]bool, 0, len(entries))
+	for i, e := range entries {
+		if !s.IsScheduled(e.Timestamp, e.Addr) {
+			return append(results, false), fmt.Errorf("entry %v: %v not scheduled at %v", i, e.Addr, e.Timestamp)
+		}
+		results = append(results, true)
+	}
+	return results, nil
+}
+
+// RoundOf returns the round that the given timestamp falls into. A round is a pass through all
+// shuffled proposers, counted from the slot right after the parent block, which is round 0.
+// Timestamps are aligned to time slots the same way as Schedule does.
+func (s *SchedulerV2) RoundOf(timestamp uint64) uint64 {
+	n := uint64(len(s.shuffled))
+	if n == 0 {
+		return 0
+	}
+	return (s.slotTime(timestamp) - s.parentBlockTime - thor.BlockInterval) / thor.BlockInterval / n
+}
+
+// RoundOrder returns the order of proposers producing blocks in the given round, counted the same way as RoundOf.
+// Absentees are excluded, including the scheduled proposer if it's inactive. A weighted proposer appears as many
+// times as its slots. The order is independent of time, and since the shuffle is seeded by the parent block, it
+// repeats in every round upon the same parent. It returns nil if the slots of the round overflow the timestamp.
+func (s *SchedulerV2) RoundOrder(round uint64) []thor.Address {
+	const T = thor.BlockInterval
+
+	n := uint64(len(s.shuffled))
+	if n == 0 || round >= (math.MaxUint64-s.parentBlockTime)/T/n {
+		return nil
+	}
+
+	order := make([]thor.Address, 0, n)
+	for i := uint64(0); i < n; i++ {
+		// the proposer of each slot in the round, located the same way as IsScheduled does
+		blockTime := s.parentBlockTime + (round*n+i+1)*T
+		addr := s.shuffled[(blockTime-s.parentBlockTime-T)/T%n]
+		if addr == s.proposer.Address && !s.proposer.Active {
+			continue
+		}
+		order = append(order, addr)
+	}
+	return order
+}
+
 // Updates returns proposers whose status are changed, and the score when new block time is assumed to be newBlockTime.
 func (s *SchedulerV2) Updates(newBlockTime uint64) (updates []Proposer, score uint64) {
 	T := thor.BlockInterval
 
+	var missed uint64
 	for i := uint64(0); i < uint64(len(s.shuffled)); i++ {
 		if s.parentBlockTime+T+i*T >= newBlockTime {
 			break
 		}
 		if s.shuffled[i] != s.proposer.Address {
-			updates = append(updates, Proposer{Address: s.shuffled[i], Active: false})
+			missed++
+			// a weighted proposer may miss more than one slot
+			if !containsProposer(updates, s.shuffled[i]) {
+				updates = append(updates, Proposer{Address: s.shuffled[i], Active: false})
+			}
 		}
 	}
 
-	score = uint64(len(s.shuffled) - len(updates))
+	score = uint64(len(s.shuffled)) - missed
 
 	if !s.proposer.Active {
 		cpy := s.proposer
@@ -147,3 +367,19 @@ func (s *SchedulerV2) Updates(newBlockTime uint64) (updates []Proposer, score ui
 	}
 	return
 }
+
+func containsProposer(proposers []Proposer, addr thor.Address) bool {
+	for _, p := range proposers {
+		if p.Address == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -6,6 +6,7 @@
 package poa
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
@@ -231,3 +232,288 @@ func TestSchedulerV2_Schedule(t *testing.T) {
 		})
 	}
 }
+
+func TestSchedulerV2_NextProposer(t *testing.T) {
+	type fields struct {
+		proposer        Proposer
+		parentBlockTime uint64
+		shuffled        []thor.Address
+	}
+	tests := []struct {
+		name          string
+		fields        fields
+		nowTime       uint64
+		wantProposer  thor.Address
+		wantBlockTime uint64
+	}{
+		{"p1 at first slot", fields{
+			Proposer{p1, true},
+			parentTime,
+			[]thor.Address{p1, p2, p3, p4, p5},
+		}, 5, p1, 10},
+		{"p3 at third slot", fields{
+			Proposer{p1, true},
+			parentTime,
+			[]thor.Address{p1, p2, p3, p4, p5},
+		}, 25, p3, 30},
+		{"wrap around to next round", fields{
+			Proposer{p1, true},
+			parentTime,
+			[]thor.Address{p1, p2, p3, p4, p5},
+		}, 55, p1, 60},
+		{"inactive scheduled proposer is skipped", fields{
+			Proposer{p1, false},
+			parentTime,
+			[]thor.Address{p1, p2, p3, p4, p5},
+		}, 5, p2, 20},
+		{"inactive scheduled proposer is the only one", fields{
+			Proposer{p1, false},
+			parentTime,
+			[]thor.Address{p1},
+		}, 15, p1, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SchedulerV2{
+				proposer:        tt.fields.proposer,
+				parentBlockTime: tt.fields.parentBlockTime,
+				shuffled:        tt.fields.shuffled,
+			}
+			gotProposer, gotBlockTime, err := s.NextProposer(tt.nowTime)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotProposer != tt.wantProposer || gotBlockTime != tt.wantBlockTime {
+				t.Errorf("SchedulerV2.NextProposer() = %v, %v, want %v, %v", gotProposer, gotBlockTime, tt.wantProposer, tt.wantBlockTime)
+			}
+		})
+	}
+
+	_, _, err := (&SchedulerV2{}).NextProposer(0)
+	if err == nil {
+		t.Error("SchedulerV2.NextProposer() with empty list should return error")
+	}
+}
+
+func TestSchedulerV2_RoundOf(t *testing.T) {
+	s := &SchedulerV2{
+		proposer:        Proposer{p1, true},
+		parentBlockTime: parentTime,
+		shuffled:        []thor.Address{p1, p2, p3, p4, p5},
+	}
+	tests := []struct {
+		timestamp uint64
+		wantRound uint64
+	}{
+		{0, 0},
+		{10, 0},
+		{50, 0},
+		{51, 1},
+		{60, 1},
+		{100, 1},
+		{110, 2},
+	}
+	for _, tt := range tests {
+		if got := s.RoundOf(tt.timestamp); got != tt.wantRound {
+			t.Errorf("SchedulerV2.RoundOf(%v) = %v, want %v", tt.timestamp, got, tt.wantRound)
+		}
+	}
+
+	// round boundaries line up with the schedule
+	if got := s.RoundOf(s.Schedule(15)); got != 1 {
+		t.Errorf("SchedulerV2.RoundOf(Schedule(15)) = %v, want 1", got)
+	}
+	if got := (&SchedulerV2{}).RoundOf(100); got != 0 {
+		t.Errorf("SchedulerV2.RoundOf() with empty list = %v, want 0", got)
+	}
+}
+
+func TestSchedulerV2_RoundOrder(t *testing.T) {
+	proposers := []Proposer{{p1, true}, {p2, true}, {p3, true}, {p4, true}, {p5, true}}
+	s, err := NewSchedulerV2(p1, proposers, 1, parentTime, thor.Bytes32{}.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := uint64(len(proposers))
+	for round := uint64(0); round < 3; round++ {
+		order := s.RoundOrder(round)
+		if len(order) != len(proposers) {
+			t.Fatalf("SchedulerV2.RoundOrder() len = %v, want %v", len(order), len(proposers))
+		}
+		// consistent with the schedule
+		for i, addr := range order {
+			blockTime := parentTime + (round*n+uint64(i)+1)*thor.BlockInterval
+			if !s.IsScheduled(blockTime, addr) {
+				t.Errorf("SchedulerV2.RoundOrder(%v)[%v] = %v, not scheduled at %v", round, i, addr, blockTime)
+			}
+			if got := s.RoundOf(blockTime); got != round {
+				t.Errorf("SchedulerV2.RoundOf(%v) = %v, want %v", blockTime, got, round)
+			}
+		}
+	}
+
+	// inactive scheduled proposer is excluded
+	s = &SchedulerV2{
+		proposer:        Proposer{p3, false},
+		parentBlockTime: parentTime,
+		shuffled:        []thor.Address{p1, p2, p3, p4, p5},
+	}
+	if got, want := s.RoundOrder(0), []thor.Address{p1, p2, p4, p5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SchedulerV2.RoundOrder() = %v, want %v", got, want)
+	}
+	// the last round before the timestamp overflows
+	last := (math.MaxUint64 - parentTime) / thor.BlockInterval / 5
+	if got, want := s.RoundOrder(last-1), []thor.Address{p1, p2, p4, p5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SchedulerV2.RoundOrder() = %v, want %v", got, want)
+	}
+	if got := s.RoundOrder(last); got != nil {
+		t.Errorf("SchedulerV2.RoundOrder() = %v, want nil", got)
+	}
+}
+
+func TestNewSchedulerV2Weighted(t *testing.T) {
+	seed := thor.Bytes32{}.Bytes()
+	parentNumber := uint32(10)
+	proposers := []Proposer{{p1, true}, {p2, true}, {p3, true}, {p4, true}, {p5, true}}
+
+	want, err := NewSchedulerV2(p1, proposers, parentNumber, parentTime, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// equal weights should be identical to unweighted
+	got, err := NewSchedulerV2Weighted(p1, proposers, []uint64{3, 3, 3, 3, 3}, parentNumber, parentTime, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewSchedulerV2Weighted() = %v, want %v", got, want)
+	}
+
+	got, err = NewSchedulerV2Weighted(p1, proposers, []uint64{4, 2, 2, 2, 2}, parentNumber, parentTime, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := make(map[thor.Address]int)
+	for _, addr := range got.shuffled {
+		count[addr]++
+	}
+	if want := map[thor.Address]int{p1: 2, p2: 1, p3: 1, p4: 1, p5: 1}; !reflect.DeepEqual(count, want) {
+		t.Errorf("NewSchedulerV2Weighted() slots = %v, want %v", count, want)
+	}
+
+	if _, err := NewSchedulerV2Weighted(p1, proposers, []uint64{1, 1}, parentNumber, parentTime, seed); err == nil {
+		t.Error("NewSchedulerV2Weighted() should return error if weights length mismatch")
+	}
+	if _, err := NewSchedulerV2Weighted(p1, proposers, []uint64{1, 1, 0, 1, 1}, parentNumber, parentTime, seed); err == nil {
+		t.Error("NewSchedulerV2Weighted() should return error if weight is zero")
+	}
+
+	// stake sized weights are normalized
+	got, err = NewSchedulerV2Weighted(p1, proposers, []uint64{3e12, 1e12, 1e12, 1e12, 1e12}, parentNumber, parentTime, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.shuffled) != 7 {
+		t.Errorf("NewSchedulerV2Weighted() slots = %v, want %v", len(got.shuffled), 7)
+	}
+
+	// huge weight
+	if _, err := NewSchedulerV2Weighted(p1, proposers, []uint64{math.MaxUint64, 1, 1, 1, 1}, parentNumber, parentTime, seed); err == nil {
+		t.Error("NewSchedulerV2Weighted() should return error if weight is huge")
+	}
+	// co-prime large weights
+	if _, err := NewSchedulerV2Weighted(p1, proposers, []uint64{1e9 + 7, 1e9 + 9, 1e9 + 7, 1e9 + 9, 1e9 + 7}, parentNumber, parentTime, seed); err == nil {
+		t.Error("NewSchedulerV2Weighted() should return error if weights are co-prime and large")
+	}
+	// each within the limit but the sum exceeds
+	if _, err := NewSchedulerV2Weighted(p1, proposers, []uint64{MaxWeightedSlots, 1, 1, 1, 1}, parentNumber, parentTime, seed); err == nil {
+		t.Error("NewSchedulerV2Weighted() should return error if the sum of weights exceeds the limit")
+	}
+}
+
+func TestSchedulerV2_WeightedUpdates(t *testing.T) {
+	s := &SchedulerV2{
+		proposer:        Proposer{p3, true},
+		parentBlockTime: parentTime,
+		shuffled:        []thor.Address{p1, p2, p1, p3, p2},
+	}
+	gotUpdates, gotScore := s.Updates(40)
+	if want := []Proposer{{p1, false}, {p2, false}}; !reflect.DeepEqual(gotUpdates, want) {
+		t.Errorf("SchedulerV2.Updates() gotUpdates = %v, want %v", gotUpdates, want)
+	}
+	if gotScore != 2 {
+		t.Errorf("SchedulerV2.Updates() gotScore = %v, want %v", gotScore, 2)
+	}
+}
+
+func TestSchedulerV2_ValidateBatch(t *testing.T) {
+	s := &SchedulerV2{Proposer{p1, true}, parentTime, []thor.Address{p1, p2, p3}}
+
+	tests := []struct {
+		name    string
+		entries []ScheduleEntry
+		want    []bool
+		wantErr bool
+	}{
+		{"empty", nil, []bool{}, false},
+		{"all valid", []ScheduleEntry{{p1, 10}, {p2, 20}, {p3, 30}, {p1, 40}}, []bool{true, true, true, true}, false},
+		{"stop at first invalid", []ScheduleEntry{{p1, 10}, {p3, 20}, {p3, 30}}, []bool{true, false}, true},
+		{"unaligned time", []ScheduleEntry{{p1, 15}, {p2, 20}}, []bool{false}, true},
+		{"not after parent", []ScheduleEntry{{p1, parentTime}}, []bool{false}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ValidateBatch(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SchedulerV2.ValidateBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SchedulerV2.ValidateBatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSchedulerV2WithShuffler(t *testing.T) {
+	seed := thor.Bytes32{}.Bytes()
+	proposers := []Proposer{{p1, true}, {p2, true}, {p3, true}, {p4, true}, {p5, true}}
+	identity := func(list []thor.Address, seed []byte, parentBlockNumber uint32) []thor.Address {
+		return list
+	}
+
+	s, err := NewSchedulerV2(p3, proposers, 1, parentTime, seed, WithShuffler(identity))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []thor.Address{p1, p2, p3, p4, p5}; !reflect.DeepEqual(s.shuffled, want) {
+		t.Errorf("NewSchedulerV2() shuffled = %v, want %v", s.shuffled, want)
+	}
+	if got := s.Schedule(parentTime); got != parentTime+3*thor.BlockInterval {
+		t.Errorf("SchedulerV2.Schedule() = %v, want %v", got, parentTime+3*thor.BlockInterval)
+	}
+
+	// weighted entries are consecutive
+	s, err = NewSchedulerV2Weighted(p3, proposers, []uint64{1, 2, 1, 1, 1}, 1, parentTime, seed, WithShuffler(identity))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []thor.Address{p1, p2, p2, p3, p4, p5}; !reflect.DeepEqual(s.shuffled, want) {
+		t.Errorf("NewSchedulerV2Weighted() shuffled = %v, want %v", s.shuffled, want)
+	}
+
+	// default shuffler is used if unset
+	want, _ := NewSchedulerV2(p3, proposers, 1, parentTime, seed)
+	got, _ := NewSchedulerV2(p3, proposers, 1, parentTime, seed, WithShuffler(defaultShuffler))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewSchedulerV2() = %v, want %v", got, want)
+	}
+
+	drop := func(list []thor.Address, seed []byte, parentBlockNumber uint32) []thor.Address {
+		return list[1:]
+	}
+	if _, err := NewSchedulerV2(p3, proposers, 1, parentTime, seed, WithShuffler(drop)); err == nil {
+		t.Error("NewSchedulerV2() should return error if shuffler changed the proposers count")
+	}
+}
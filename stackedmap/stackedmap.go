@@ -79,13 +79,22 @@ func (sm *StackedMap) PopTo(depth int) {
 // Get gets value for given key.
 // The second return value indicates whether the given key is found.
 func (sm *StackedMap) Get(key interface{}) (interface{}, bool, error) {
+	if v, ok := sm.Peek(key); ok {
+		return v, true, nil
+	}
+	return sm.src(key)
+}
+
+// Peek gets the value put for the key, without falling back to the source.
+// It returns false if the key was never put or all puts are reverted.
+func (sm *StackedMap) Peek(key interface{}) (interface{}, bool) {
 	if revs, ok := sm.keyRevisionMap[key]; ok {
 		lvl := sm.mapStack[revs.top().(int)].(*level)
 		if v, ok := lvl.kvs[key]; ok {
-			return v, true, nil
+			return v, true
 		}
 	}
-	return sm.src(key)
+	return nil, false
 }
 
 // Put puts key value into map at stack top.
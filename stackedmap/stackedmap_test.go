@@ -94,3 +94,16 @@ func TestStackedMapPuts(t *testing.T) {
 
 	assert.Equal(1, i, "Journal traverse should abort")
 }
+
+func TestStackedMapPeek(t *testing.T) {
+	sm := stackedmap.New(func(key interface{}) (interface{}, bool, error) {
+		return "src", true, nil
+	})
+
+	assert.Equal(t, M(nil, false), M(sm.Peek("foo")))
+	sm.Push()
+	sm.Put("foo", "bar")
+	assert.Equal(t, M("bar", true), M(sm.Peek("foo")))
+	sm.Pop()
+	assert.Equal(t, M(nil, false), M(sm.Peek("foo")))
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+)
+
+// AccountDiff is the difference of an account between two states.
+type AccountDiff struct {
+	// HashedKey is the hashed address, i.e. thor.Blake2b(address), since addresses are not
+	// kept in the account trie.
+	HashedKey thor.Bytes32
+	From      *Account // nil if the account is added
+	To        *Account // nil if the account is removed
+	// Storage is the changed storage entries, only if storage diff requested.
+	Storage []*StorageDiff
+}
+
+// StorageDiff is the difference of a storage entry between two states.
+type StorageDiff struct {
+	Key  thor.Bytes32
+	From rlp.RawValue // nil if the entry is added
+	To   rlp.RawValue // nil if the entry is removed
+}
+
+// Diff walks the account tries of the two states, and calls fn with each added, removed or changed account,
+// in the order of hashed keys. Identical sub-tries are skipped, but it's still expensive for large changes,
+// so diffs are streamed to fn rather than collected. Storage diffs are included if withStorage is true.
+// Only committed states are compared, uncommitted changes are ignored.
+// The walk stops with the error returned by fn, or ctx.Err() if ctx is done.
+func Diff(ctx context.Context, from, to *State, withStorage bool, fn func(*AccountDiff) error) error {
+	return diffTries(from.trie, to.trie, func(key []byte, fromLeaf, toLeaf *trie.Leaf) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		diff := AccountDiff{HashedKey: thor.BytesToBytes32(key)}
+		var fromMeta, toMeta AccountMetadata
+		if fromLeaf != nil {
+			if err := decodeAccountLeaf(fromLeaf, &diff.From, &fromMeta); err != nil {
+				return &Error{err}
+			}
+		}
+		if toLeaf != nil {
+			if err := decodeAccountLeaf(toLeaf, &diff.To, &toMeta); err != nil {
+				return &Error{err}
+			}
+		}
+
+		if withStorage {
+			fromStorage := from.newStorageTrie(diff.From, &fromMeta)
+			toStorage := to.newStorageTrie(diff.To, &toMeta)
+			if err := diffTries(fromStorage, toStorage, func(key []byte, fromLeaf, toLeaf *trie.Leaf) error {
+				var sd StorageDiff
+				if fromLeaf != nil {
+					sd.Key = thor.BytesToBytes32(fromLeaf.Meta)
+					sd.From = fromLeaf.Value
+				}
+				if toLeaf != nil {
+					sd.Key = thor.BytesToBytes32(toLeaf.Meta)
+					sd.To = toLeaf.Value
+				}
+				diff.Storage = append(diff.Storage, &sd)
+				return nil
+			}); err != nil {
+				return &Error{err}
+			}
+		}
+		return fn(&diff)
+	})
+}
+
+func decodeAccountLeaf(leaf *trie.Leaf, acc **Account, meta *AccountMetadata) error {
+	var a Account
+	if err := rlp.DecodeBytes(leaf.Value, &a); err != nil {
+		return err
+	}
+	if len(leaf.Meta) > 0 {
+		if err := rlp.DecodeBytes(leaf.Meta, meta); err != nil {
+			return err
+		}
+	}
+	*acc = &a
+	return nil
+}
+
+// newStorageTrie creates the committed storage trie of the account, or an empty trie if the account is nil
+// or has no storage.
+func (s *State) newStorageTrie(acc *Account, meta *AccountMetadata) *muxdb.Trie {
+	if acc == nil || len(acc.StorageRoot) == 0 {
+		return s.db.NewTrie("", thor.Bytes32{}, 0, 0)
+	}
+	return s.db.NewTrie(
+		StorageTrieName(meta.StorageID),
+		thor.BytesToBytes32(acc.StorageRoot),
+		meta.StorageCommitNum,
+		meta.StorageDistinctNum)
+}
+
+// diffTries calls fn with leaves differing between trie a and b in the order of keys.
+// Either leaf is nil if the key is absent in that trie.
+func diffTries(a, b *muxdb.Trie, fn func(key []byte, aLeaf, bLeaf *trie.Leaf) error) error {
+	var (
+		onlyA, _ = trie.NewDifferenceIterator(b.NodeIterator(nil, 0), a.NodeIterator(nil, 0))
+		onlyB, _ = trie.NewDifferenceIterator(a.NodeIterator(nil, 0), b.NodeIterator(nil, 0))
+	)
+	nextLeaf := func(it trie.NodeIterator) ([]byte, *trie.Leaf) {
+		for it.Next(true) {
+			if leaf := it.Leaf(); leaf != nil {
+				return append([]byte(nil), it.LeafKey()...), &trie.Leaf{
+					Value: append([]byte(nil), leaf.Value...),
+					Meta:  append([]byte(nil), leaf.Meta...),
+				}
+			}
+		}
+		return nil, nil
+	}
+
+	keyA, leafA := nextLeaf(onlyA)
+	keyB, leafB := nextLeaf(onlyB)
+	for leafA != nil || leafB != nil {
+		var err error
+		switch cmp := bytes.Compare(keyA, keyB); {
+		case leafB == nil || (leafA != nil && cmp < 0):
+			err = fn(keyA, leafA, nil)
+			keyA, leafA = nextLeaf(onlyA)
+		case leafA == nil || cmp > 0:
+			err = fn(keyB, nil, leafB)
+			keyB, leafB = nextLeaf(onlyB)
+		default:
+			err = fn(keyA, leafA, leafB)
+			keyA, leafA = nextLeaf(onlyA)
+			keyB, leafB = nextLeaf(onlyB)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := onlyA.Error(); err != nil {
+		return err
+	}
+	return onlyB.Error()
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestDiff(t *testing.T) {
+	db := muxdb.NewMem()
+
+	var (
+		unchanged = thor.BytesToAddress([]byte("unchanged"))
+		changed   = thor.BytesToAddress([]byte("changed"))
+		removed   = thor.BytesToAddress([]byte("removed"))
+		added     = thor.BytesToAddress([]byte("added"))
+		key1      = thor.BytesToBytes32([]byte("key1"))
+		key2      = thor.BytesToBytes32([]byte("key2"))
+		key3      = thor.BytesToBytes32([]byte("key3"))
+	)
+
+	st := New(db, thor.Bytes32{}, 0, 0, 0)
+	st.SetBalance(unchanged, big.NewInt(1))
+	st.SetBalance(changed, big.NewInt(1))
+	st.SetStorage(changed, key1, thor.BytesToBytes32([]byte("v1")))
+	st.SetStorage(changed, key2, thor.BytesToBytes32([]byte("v2")))
+	st.SetBalance(removed, big.NewInt(1))
+	stage, err := st.Stage(1, 0)
+	assert.Nil(t, err)
+	root1, err := stage.Commit()
+	assert.Nil(t, err)
+
+	st = New(db, root1, 1, 0, 0)
+	st.SetStorage(changed, key1, thor.Bytes32{})
+	st.SetStorage(changed, key2, thor.BytesToBytes32([]byte("v2'")))
+	st.SetStorage(changed, key3, thor.BytesToBytes32([]byte("v3")))
+	st.Delete(removed)
+	st.SetBalance(added, big.NewInt(2))
+	stage, err = st.Stage(2, 0)
+	assert.Nil(t, err)
+	root2, err := stage.Commit()
+	assert.Nil(t, err)
+
+	from := New(db, root1, 1, 0, 0)
+	to := New(db, root2, 2, 0, 0)
+
+	diffs := make(map[thor.Bytes32]*AccountDiff)
+	var prev thor.Bytes32
+	assert.Nil(t, Diff(context.Background(), from, to, true, func(d *AccountDiff) error {
+		assert.True(t, prev.String() < d.HashedKey.String(), "should be in order of hashed keys")
+		prev = d.HashedKey
+		diffs[d.HashedKey] = d
+		return nil
+	}))
+	assert.Equal(t, 3, len(diffs))
+
+	assert.Nil(t, diffs[thor.Blake2b(unchanged[:])])
+
+	d := diffs[thor.Blake2b(added[:])]
+	assert.Nil(t, d.From)
+	assert.Equal(t, big.NewInt(2), d.To.Balance)
+	assert.Empty(t, d.Storage)
+
+	d = diffs[thor.Blake2b(removed[:])]
+	assert.Equal(t, big.NewInt(1), d.From.Balance)
+	assert.Nil(t, d.To)
+
+	encode := func(v string) rlp.RawValue {
+		data, _ := rlp.EncodeToBytes(thor.BytesToBytes32([]byte(v)).Bytes()[32-len(v):])
+		return data
+	}
+	d = diffs[thor.Blake2b(changed[:])]
+	assert.NotEqual(t, d.From.StorageRoot, d.To.StorageRoot)
+	storage := make(map[thor.Bytes32]*StorageDiff)
+	for _, sd := range d.Storage {
+		storage[sd.Key] = sd
+	}
+	assert.Equal(t, &StorageDiff{Key: key1, From: encode("v1")}, storage[key1])
+	assert.Equal(t, &StorageDiff{Key: key2, From: encode("v2"), To: encode("v2'")}, storage[key2])
+	assert.Equal(t, &StorageDiff{Key: key3, To: encode("v3")}, storage[key3])
+
+	// without storage
+	assert.Nil(t, Diff(context.Background(), from, to, false, func(d *AccountDiff) error {
+		assert.Nil(t, d.Storage)
+		return nil
+	}))
+
+	// identical states
+	assert.Nil(t, Diff(context.Background(), to, to, true, func(d *AccountDiff) error {
+		t.Errorf("unexpected diff %v", d.HashedKey)
+		return nil
+	}))
+
+	// stopped by fn error
+	errStop := errors.New("stop")
+	count := 0
+	assert.Equal(t, errStop, Diff(context.Background(), from, to, true, func(d *AccountDiff) error {
+		count++
+		return errStop
+	}))
+	assert.Equal(t, 1, count)
+
+	// canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, Diff(ctx, from, to, true, func(d *AccountDiff) error { return nil }))
+}
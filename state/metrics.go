@@ -0,0 +1,15 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/vechain/thor/v2/metrics"
+)
+
+var (
+	metricCodeWrittenBytes = metrics.LazyLoadCounter("state_code_written_bytes") // contract codes written on commits
+	metricCodeSkippedBytes = metrics.LazyLoadCounter("state_code_skipped_bytes") // contract codes already stored, e.g. of clones
+)
@@ -5,12 +5,25 @@
 
 package state
 
-import "github.com/vechain/thor/v2/thor"
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/vechain/thor/v2/kv"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+)
 
 // Stage abstracts changes on the main accounts trie.
 type Stage struct {
-	root    thor.Bytes32
-	commits []func() error
+	db             *muxdb.MuxDB
+	root           thor.Bytes32
+	storageRoots   map[thor.Address]thor.Bytes32
+	storageCommits []func(kv.Putter) error // storage tries, independent of each other
+	commits        []func(kv.Putter) error // the accounts trie and codes, after storage tries
+	reverted       bool
 }
 
 // Hash computes hash of the main accounts trie.
@@ -18,13 +31,152 @@ func (s *Stage) Hash() thor.Bytes32 {
 	return s.root
 }
 
+// StorageRoots returns roots of the storage tries computed by the stage, keyed by accounts which had storage changes.
+// Accounts emptied by the changes are excluded, since their storage changes are skipped.
+func (s *Stage) StorageRoots() map[thor.Address]thor.Bytes32 {
+	roots := make(map[thor.Address]thor.Bytes32, len(s.storageRoots))
+	for addr, root := range s.storageRoots {
+		roots[addr] = root
+	}
+	return roots
+}
+
 // Commit commits all changes into main accounts trie and storage tries.
+// It returns an error if the stage is reverted.
 func (s *Stage) Commit() (root thor.Bytes32, err error) {
+	bulk := s.db.NewBulk()
+	if root, err = s.CommitTo(bulk); err != nil {
+		return
+	}
+	if err = bulk.Write(); err != nil {
+		return thor.Bytes32{}, &Error{err}
+	}
+	return
+}
+
+// CommitTo puts all changes of main accounts trie and storage tries into the given bulk,
+// which must be created by muxdb.MuxDB.NewBulk. Changes are persisted after the bulk written
+// by the caller, so that they can be written atomically along with other data.
+// It returns an error if the stage is reverted.
+func (s *Stage) CommitTo(bulk kv.Putter) (root thor.Bytes32, err error) {
+	if s.reverted {
+		return thor.Bytes32{}, &Error{errors.New("stage reverted")}
+	}
+	if err = commitStorages(bulk, s.storageCommits, runtime.GOMAXPROCS(0)); err != nil {
+		err = &Error{err}
+		return
+	}
 	for _, c := range s.commits {
-		if err = c(); err != nil {
+		if err = c(bulk); err != nil {
 			err = &Error{err}
 			return
 		}
 	}
 	return s.root, nil
 }
+
+// Revert discards all changes of the stage, so that the staged tries and codes can be reclaimed promptly.
+func (s *Stage) Revert() {
+	s.storageCommits = nil
+	s.commits = nil
+	s.reverted = true
+}
+
+// commitStorages runs commits of storage tries across at most nWorker workers. Each commit puts into
+// its own batch, and batches are replayed into the bulk in the staged order, so that the bulk receives
+// exactly the same puts as committed sequentially.
+func commitStorages(bulk kv.Putter, commits []func(kv.Putter) error, nWorker int) error {
+	if nWorker > len(commits) {
+		nWorker = len(commits)
+	}
+	if nWorker <= 1 {
+		for _, c := range commits {
+			if err := c(bulk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	batches := make([]batch, len(commits))
+	errs := make([]error, len(commits))
+	parallel(len(commits), nWorker, func(i int) {
+		errs[i] = commits[i](&batches[i])
+	})
+
+	for i := range batches {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		if err := batches[i].replay(bulk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parallel calls fn for each index in [0, n) across at most nWorker goroutines, and waits for all done.
+func parallel(n, nWorker int, fn func(i int)) {
+	if nWorker > n {
+		nWorker = n
+	}
+	if nWorker <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	var (
+		next = int32(-1)
+		wg   sync.WaitGroup
+	)
+	for w := 0; w < nWorker; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt32(&next, 1))
+				if i >= n {
+					return
+				}
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// batch buffers puts and deletes in memory.
+type batch []batchOp
+
+type batchOp struct {
+	key, val []byte
+	del      bool
+}
+
+func (b *batch) Put(key, val []byte) error {
+	// the putter may reuse buffers, copy them
+	*b = append(*b, batchOp{key: append([]byte(nil), key...), val: append([]byte(nil), val...)})
+	return nil
+}
+
+func (b *batch) Delete(key []byte) error {
+	*b = append(*b, batchOp{key: append([]byte(nil), key...), del: true})
+	return nil
+}
+
+func (b batch) replay(putter kv.Putter) error {
+	for _, op := range b {
+		var err error
+		if op.del {
+			err = putter.Delete(op.key)
+		} else {
+			err = putter.Put(op.key, op.val)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
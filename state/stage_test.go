@@ -6,11 +6,14 @@
 package state
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/kv"
 	"github.com/vechain/thor/v2/muxdb"
 	"github.com/vechain/thor/v2/thor"
 )
@@ -80,7 +83,7 @@ func TestStageCommitError(t *testing.T) {
 	assert.Nil(t, err, "Stage should not return an error")
 
 	// Mock a commit function to simulate an error.
-	commitFuncWithError := func() error {
+	commitFuncWithError := func(kv.Putter) error {
 		return errors.New("commit error")
 	}
 
@@ -94,3 +97,253 @@ func TestStageCommitError(t *testing.T) {
 	assert.NotNil(t, err, "Commit should return an error")
 	assert.EqualError(t, err, "state: commit error", "The error message should match the mock error")
 }
+
+func TestStageRevert(t *testing.T) {
+	db := muxdb.NewMem()
+	state := New(db, thor.Bytes32{}, 0, 0, 0)
+
+	addr := thor.BytesToAddress([]byte("acc1"))
+	state.SetBalance(addr, big.NewInt(10))
+	state.SetCode(addr, []byte{1, 2, 3})
+	state.SetStorage(addr, thor.BytesToBytes32([]byte("s1")), thor.BytesToBytes32([]byte("v1")))
+
+	stage, err := state.Stage(1, 0)
+	assert.Nil(t, err)
+
+	stage.Revert()
+	assert.Nil(t, stage.commits)
+
+	_, err = stage.Commit()
+	assert.EqualError(t, err, "state: stage reverted")
+
+	// nothing written
+	state = New(db, stage.Hash(), 1, 0, 0)
+	_, err = state.GetBalance(addr)
+	assert.NotNil(t, err)
+}
+
+func TestStageCommitTo(t *testing.T) {
+	db := muxdb.NewMem()
+	state := New(db, thor.Bytes32{}, 0, 0, 0)
+
+	addr := thor.BytesToAddress([]byte("acc1"))
+	key := thor.BytesToBytes32([]byte("s1"))
+	value := thor.BytesToBytes32([]byte("v1"))
+	code := []byte{1, 2, 3}
+
+	state.SetBalance(addr, big.NewInt(10))
+	state.SetCode(addr, code)
+	state.SetStorage(addr, key, value)
+
+	stage, err := state.Stage(1, 0)
+	assert.Nil(t, err)
+
+	bulk := db.NewBulk()
+	extraPutter := db.NewStorePutter("extra", bulk)
+	assert.Nil(t, extraPutter.Put([]byte("k"), []byte("v")))
+
+	root, err := stage.CommitTo(bulk)
+	assert.Nil(t, err)
+	assert.Equal(t, stage.Hash(), root)
+
+	// nothing written before the bulk written
+	_, err = New(db, root, 1, 0, 0).GetBalance(addr)
+	assert.NotNil(t, err)
+	_, err = db.NewStore("extra").Get([]byte("k"))
+	assert.True(t, db.IsNotFound(err))
+
+	assert.Nil(t, bulk.Write())
+
+	state = New(db, root, 1, 0, 0)
+	assert.Equal(t, M(big.NewInt(10), nil), M(state.GetBalance(addr)))
+	assert.Equal(t, M(code, nil), M(state.GetCode(addr)))
+	assert.Equal(t, M(value, nil), M(state.GetStorage(addr, key)))
+	assert.Equal(t, M([]byte("v"), nil), M(db.NewStore("extra").Get([]byte("k"))))
+}
+
+func TestStageContext(t *testing.T) {
+	db := muxdb.NewMem()
+	state := New(db, thor.Bytes32{}, 0, 0, 0)
+	addr := thor.BytesToAddress([]byte("acc1"))
+	state.SetBalance(addr, big.NewInt(10))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stage, err := state.StageContext(ctx, 1, 0)
+	assert.Nil(t, err)
+
+	expected, err := state.Stage(1, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, expected.Hash(), stage.Hash())
+
+	cancel()
+	_, err = state.StageContext(ctx, 1, 0)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestStageStorageRoots(t *testing.T) {
+	db := muxdb.NewMem()
+	state := New(db, thor.Bytes32{}, 0, 0, 0)
+	addr1 := thor.BytesToAddress([]byte("acc1"))
+	addr2 := thor.BytesToAddress([]byte("acc2"))
+
+	state.SetBalance(addr1, big.NewInt(10))
+	state.SetStorage(addr1, thor.BytesToBytes32([]byte("s1")), thor.BytesToBytes32([]byte("v1")))
+	// no storage change
+	state.SetBalance(addr2, big.NewInt(10))
+
+	stage, err := state.Stage(1, 0)
+	assert.Nil(t, err)
+	roots := stage.StorageRoots()
+	assert.Len(t, roots, 1)
+
+	root, err := stage.Commit()
+	assert.Nil(t, err)
+
+	acc, err := New(db, root, 1, 0, 0).getAccount(addr1)
+	assert.Nil(t, err)
+	assert.Equal(t, thor.BytesToBytes32(acc.StorageRoot), roots[addr1])
+
+	// returns a copy
+	delete(roots, addr1)
+	assert.Len(t, stage.StorageRoots(), 1)
+}
+
+// recordPutter records puts and deletes into a map.
+type recordPutter map[string][]byte
+
+func (r recordPutter) Put(key, val []byte) error {
+	r[string(key)] = append([]byte(nil), val...)
+	return nil
+}
+
+func (r recordPutter) Delete(key []byte) error {
+	r[string(key)] = nil
+	return nil
+}
+
+func TestStageParallelStorageCommit(t *testing.T) {
+	// builds a stage touching many contracts, based on some committed storage tries
+	newStage := func() *Stage {
+		db := muxdb.NewMem()
+		state := New(db, thor.Bytes32{}, 0, 0, 0)
+		for i := 0; i < 100; i++ {
+			addr := thor.BytesToAddress([]byte{byte(i), 1})
+			state.SetBalance(addr, big.NewInt(1))
+			state.SetStorage(addr, thor.BytesToBytes32([]byte("base")), thor.BytesToBytes32([]byte{byte(i)}))
+		}
+		stage, err := state.Stage(1, 0)
+		assert.Nil(t, err)
+		root, err := stage.Commit()
+		assert.Nil(t, err)
+
+		state = New(db, root, 1, 0, 0)
+		for i := 0; i < 200; i++ {
+			addr := thor.BytesToAddress([]byte{byte(i), 1})
+			state.SetBalance(addr, big.NewInt(2))
+			for j := 0; j < 10; j++ {
+				state.SetStorage(addr, thor.BytesToBytes32([]byte{byte(j)}), thor.BytesToBytes32([]byte{byte(i), byte(j)}))
+			}
+		}
+		stage, err = state.Stage(2, 0)
+		assert.Nil(t, err)
+		return stage
+	}
+
+	seqStage, parStage := newStage(), newStage()
+	assert.Len(t, seqStage.storageCommits, 200)
+	assert.Equal(t, seqStage.Hash(), parStage.Hash())
+
+	seq := recordPutter{}
+	assert.Nil(t, commitStorages(seq, seqStage.storageCommits, 1))
+	for _, c := range seqStage.commits {
+		assert.Nil(t, c(seq))
+	}
+
+	par := recordPutter{}
+	assert.Nil(t, commitStorages(par, parStage.storageCommits, 8))
+	for _, c := range parStage.commits {
+		assert.Nil(t, c(par))
+	}
+	assert.Equal(t, seq, par)
+
+	// the first error in staged order is returned
+	err := commitStorages(recordPutter{}, []func(kv.Putter) error{
+		func(kv.Putter) error { return nil },
+		func(kv.Putter) error { return errors.New("error 1") },
+		func(kv.Putter) error { return errors.New("error 2") },
+	}, 3)
+	assert.EqualError(t, err, "error 1")
+}
+
+func BenchmarkStageCommit(b *testing.B) {
+	db := muxdb.NewMem()
+	root := thor.Bytes32{}
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		blockNum := uint32(i + 1)
+		state := New(db, root, blockNum-1, 0, 0)
+		// a block touching 500 contracts
+		for j := 0; j < 500; j++ {
+			addr := thor.BytesToAddress([]byte{byte(j >> 8), byte(j)})
+			state.SetBalance(addr, big.NewInt(int64(blockNum)))
+			for k := 0; k < 20; k++ {
+				state.SetStorage(addr, thor.BytesToBytes32([]byte{byte(k), byte(blockNum)}), thor.BytesToBytes32([]byte{byte(j), byte(k)}))
+			}
+		}
+		b.StartTimer()
+
+		stage, err := state.Stage(blockNum, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if root, err = stage.Commit(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type codeCountingPutter struct {
+	kv.Putter
+	code  []byte
+	count int
+}
+
+func (p *codeCountingPutter) Put(key, val []byte) error {
+	if bytes.Equal(val, p.code) {
+		p.count++
+	}
+	return p.Putter.Put(key, val)
+}
+
+func TestStageSkipStoredCode(t *testing.T) {
+	db := muxdb.NewMem()
+	code := bytes.Repeat([]byte{0x60}, 1000)
+	addr1 := thor.BytesToAddress([]byte("acc1"))
+	addr2 := thor.BytesToAddress([]byte("acc2"))
+
+	commit := func(root thor.Bytes32, blockNum uint32, addr thor.Address) (thor.Bytes32, int) {
+		state := New(db, root, blockNum, 0, 0)
+		state.SetCode(addr, code)
+		stage, err := state.Stage(blockNum+1, 0)
+		assert.Nil(t, err)
+
+		bulk := db.NewBulk()
+		putter := &codeCountingPutter{Putter: bulk, code: code}
+		newRoot, err := stage.CommitTo(putter)
+		assert.Nil(t, err)
+		assert.Nil(t, bulk.Write())
+		return newRoot, putter.count
+	}
+
+	root, count := commit(thor.Bytes32{}, 0, addr1)
+	assert.Equal(t, 1, count)
+
+	// the clone's code is already stored
+	root, count = commit(root, 1, addr2)
+	assert.Equal(t, 0, count)
+
+	state := New(db, root, 2, 0, 0)
+	assert.Equal(t, M(code, nil), M(state.GetCode(addr1)))
+	assert.Equal(t, M(code, nil), M(state.GetCode(addr2)))
+}
@@ -7,10 +7,12 @@ package state
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/v2/kv"
 	"github.com/vechain/thor/v2/lowrlp"
 	"github.com/vechain/thor/v2/muxdb"
 	"github.com/vechain/thor/v2/stackedmap"
@@ -330,12 +332,22 @@ func (s *State) SetCode(addr thor.Address, code []byte) error {
 
 // Exists returns whether an account exists at the given address.
 // See Account.IsEmpty()
+//
+// An account neither modified nor loaded is checked against the accounts trie directly, without
+// being decoded or cached, since empty accounts are never saved.
 func (s *State) Exists(addr thor.Address) (bool, error) {
-	acc, err := s.getAccount(addr)
+	if v, ok := s.sm.Peek(addr); ok {
+		return !v.(*Account).IsEmpty(), nil
+	}
+	if co, ok := s.cache[addr]; ok {
+		return !co.data.IsEmpty(), nil
+	}
+	hashedKey := thor.Blake2b(addr[:])
+	data, _, err := s.trie.FastGet(hashedKey[:], s.steadyBlockNum)
 	if err != nil {
 		return false, &Error{err}
 	}
-	return !acc.IsEmpty(), nil
+	return len(data) > 0, nil
 }
 
 // Delete delete an account at the given address.
@@ -407,6 +419,12 @@ func (s *State) BuildStorageTrie(addr thor.Address) (trie *muxdb.Trie, err error
 
 // Stage makes a stage object to compute hash of trie or commit all changes.
 func (s *State) Stage(newBlockNum, newBlockConflicts uint32) (*Stage, error) {
+	return s.StageContext(context.Background(), newBlockNum, newBlockConflicts)
+}
+
+// StageContext is like Stage, but it can be canceled by the given context.
+// The context is checked before staging each changed account, and ctx.Err() is returned if done.
+func (s *State) StageContext(ctx context.Context, newBlockNum, newBlockConflicts uint32) (*Stage, error) {
 	type changed struct {
 		data            Account
 		meta            AccountMetadata
@@ -484,9 +502,14 @@ func (s *State) Stage(newBlockNum, newBlockConflicts uint32) (*Stage, error) {
 	}
 
 	trieCpy := s.trie.Copy()
-	commits := make([]func() error, 0, len(changes)+2)
+	// storage tries are independent of each other, committed in parallel on commit
+	storageCommits := make([]func(kv.Putter) error, 0, len(changes))
+	storageRoots := make(map[thor.Address]thor.Bytes32)
 
 	for addr, c := range changes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		// skip storage changes if account is empty
 		if !c.data.IsEmpty() {
 			if len(c.storage) > 0 {
@@ -505,35 +528,45 @@ func (s *State) Stage(newBlockNum, newBlockConflicts uint32) (*Stage, error) {
 						return nil, &Error{err}
 					}
 				}
-				sRoot, commit := sTrie.Stage(newBlockNum, newBlockConflicts)
+				sRoot, commit := sTrie.StageTo(newBlockNum, newBlockConflicts)
 				c.data.StorageRoot = sRoot[:]
+				storageRoots[addr] = sRoot
 				c.meta.StorageCommitNum = newBlockNum
 				c.meta.StorageDistinctNum = newBlockConflicts
-				commits = append(commits, commit)
+				storageCommits = append(storageCommits, commit)
 			}
 		}
 		if err := saveAccount(trieCpy, addr, &c.data, &c.meta); err != nil {
 			return nil, &Error{err}
 		}
 	}
-	root, commitAcc := trieCpy.Stage(newBlockNum, newBlockConflicts)
-	commitCodes := func() error {
-		if len(codes) > 0 {
-			bulk := s.db.NewStore(codeStoreName).Bulk()
-			for hash, code := range codes {
-				if err := bulk.Put(hash[:], code); err != nil {
-					return err
-				}
+	root, commitAcc := trieCpy.StageTo(newBlockNum, newBlockConflicts)
+	commitCodes := func(putter kv.Putter) error {
+		store := s.db.NewStore(codeStoreName)
+		putter = s.db.NewStorePutter(codeStoreName, putter)
+		for hash, code := range codes {
+			// codes are keyed by hash, so the stored one is identical, which is common for clone contracts.
+			// It's safe with concurrent commits, which at worst put the same value again.
+			if has, err := store.Has(hash[:]); err != nil {
+				return err
+			} else if has {
+				metricCodeSkippedBytes().Add(int64(len(code)))
+				continue
+			}
+			if err := putter.Put(hash[:], code); err != nil {
+				return err
 			}
-			return bulk.Write()
+			metricCodeWrittenBytes().Add(int64(len(code)))
 		}
 		return nil
 	}
-	commits = append(commits, commitAcc, commitCodes)
 
 	return &Stage{
-		root:    root,
-		commits: commits,
+		db:             s.db,
+		root:           root,
+		storageRoots:   storageRoots,
+		storageCommits: storageCommits,
+		commits:        []func(kv.Putter) error{commitAcc, commitCodes},
 	}, nil
 }
 
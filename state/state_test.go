@@ -226,3 +226,86 @@ func TestStorageBarrier(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(acc.StorageRoot), "should skip storage writes when account deleteed then recreated")
 }
+
+func TestExistsCommitted(t *testing.T) {
+	db := muxdb.NewMem()
+	st := New(db, thor.Bytes32{}, 0, 0, 0)
+
+	addr1 := thor.BytesToAddress([]byte("addr1"))
+	addr2 := thor.BytesToAddress([]byte("addr2"))
+	st.SetBalance(addr1, big.NewInt(1))
+	st.SetEnergy(addr2, big.NewInt(1), 0)
+
+	stage, err := st.Stage(1, 0)
+	assert.Nil(t, err)
+	root, err := stage.Commit()
+	assert.Nil(t, err)
+
+	// checked against the trie
+	st = New(db, root, 1, 0, 0)
+	assert.Equal(t, M(true, nil), M(st.Exists(addr1)))
+	assert.Equal(t, M(true, nil), M(st.Exists(addr2)))
+	assert.Equal(t, M(false, nil), M(st.Exists(thor.BytesToAddress([]byte("addr3")))))
+	assert.Equal(t, 0, len(st.cache), "should not load accounts")
+
+	// modified
+	st.Delete(addr1)
+	assert.Equal(t, M(false, nil), M(st.Exists(addr1)))
+	chk := st.NewCheckpoint()
+	st.SetBalance(addr1, big.NewInt(2))
+	assert.Equal(t, M(true, nil), M(st.Exists(addr1)))
+	st.RevertTo(chk)
+	assert.Equal(t, M(false, nil), M(st.Exists(addr1)))
+
+	// loaded
+	_, err = st.GetBalance(addr2)
+	assert.Nil(t, err)
+	assert.Equal(t, M(true, nil), M(st.Exists(addr2)))
+}
+
+func BenchmarkExists(b *testing.B) {
+	db := muxdb.NewMem()
+	st := New(db, thor.Bytes32{}, 0, 0, 0)
+
+	addrs := make([]thor.Address, 10000)
+	for i := range addrs {
+		addrs[i] = thor.BytesToAddress(thor.Blake2b(big.NewInt(int64(i)).Bytes()).Bytes())
+		// half of them exist
+		if i%2 == 0 {
+			st.SetBalance(addrs[i], big.NewInt(1))
+			st.SetCode(addrs[i], []byte("code"))
+		}
+	}
+	stage, err := st.Stage(1, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	root, err := stage.Commit()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("exists", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			st := New(db, root, 1, 0, 0)
+			for _, addr := range addrs {
+				if _, err := st.Exists(addr); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("load", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			st := New(db, root, 1, 0, 0)
+			for _, addr := range addrs {
+				if _, err := st.getAccount(addr); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := st.GetCode(addr); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
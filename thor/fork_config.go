@@ -6,6 +6,7 @@
 package thor
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -39,6 +40,42 @@ func (fc ForkConfig) String() string {
 	return strings.Join(strs, ", ")
 }
 
+// Validate checks the enabled forks activate in the order of declaration. Forks sharing the
+// activation block are allowed, and disabled ones (math.MaxUint32) are skipped.
+func (fc ForkConfig) Validate() error {
+	forks := []struct {
+		name     string
+		blockNum uint32
+	}{
+		{"VIP191", fc.VIP191},
+		{"ETH_CONST", fc.ETH_CONST},
+		{"BLOCKLIST", fc.BLOCKLIST},
+		{"ETH_IST", fc.ETH_IST},
+		{"VIP214", fc.VIP214},
+		{"FINALITY", fc.FINALITY},
+	}
+
+	var (
+		errs    []string
+		maxName string
+		maxNum  uint32
+	)
+	for _, f := range forks {
+		if f.blockNum == math.MaxUint32 {
+			continue
+		}
+		if maxName != "" && f.blockNum < maxNum {
+			errs = append(errs, fmt.Sprintf("%v (#%v) activates before %v (#%v)", f.name, f.blockNum, maxName, maxNum))
+			continue
+		}
+		maxName, maxNum = f.name, f.blockNum
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
 // NoFork a special config without any forks.
 var NoFork = ForkConfig{
 	VIP191:    math.MaxUint32,
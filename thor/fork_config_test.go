@@ -57,3 +57,50 @@ func TestGetForkConfig(t *testing.T) {
 		}
 	}
 }
+
+// TestForkConfigValidate checks forks are required to activate in the order of declaration.
+func TestForkConfigValidate(t *testing.T) {
+	for id, fc := range forkConfigs {
+		if err := fc.Validate(); err != nil {
+			t.Errorf("ForkConfig(%v).Validate() = %v, want nil", id, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		fc      ForkConfig
+		wantErr string
+	}{
+		{"no fork", NoFork, ""},
+		{"all from start", ForkConfig{}, ""},
+		{"disabled skipped", ForkConfig{
+			VIP191:    1,
+			ETH_CONST: math.MaxUint32,
+			BLOCKLIST: 2,
+			ETH_IST:   math.MaxUint32,
+			VIP214:    2,
+			FINALITY:  3,
+		}, ""},
+		{"descending", ForkConfig{
+			VIP191:    10,
+			ETH_CONST: 20,
+			BLOCKLIST: math.MaxUint32,
+			ETH_IST:   15,
+			VIP214:    30,
+			FINALITY:  5,
+		}, "ETH_IST (#15) activates before ETH_CONST (#20), FINALITY (#5) activates before VIP214 (#30)"},
+	}
+
+	for _, tt := range tests {
+		err := tt.fc.Validate()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%v: Validate() = %v, want nil", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("%v: Validate() = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tracers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirectoryDisableJS(t *testing.T) {
+	d := directory{elems: make(map[string]elem)}
+	ctor := func(json.RawMessage) (Tracer, error) { return nil, nil }
+	d.Register("nativeTracer", ctor, false)
+	d.Register("jsTracer", ctor, true)
+
+	// no JS evaluator registered
+	_, err := d.New("{}", nil, true)
+	assert.Equal(t, ErrJSTracerDisabled, err)
+
+	d.RegisterJSEval(func(string, json.RawMessage) (Tracer, error) { return nil, nil })
+	for _, name := range []string{"native", "nativeTracer", "js", "jsTracer", "{}"} {
+		_, err := d.New(name, nil, true)
+		assert.Nil(t, err, name)
+	}
+	_, err = d.New("{}", nil, false)
+	assert.EqualError(t, err, "unsupported tracer")
+
+	d.DisableJS()
+	for _, name := range []string{"native", "nativeTracer"} {
+		_, err := d.New(name, nil, true)
+		assert.Nil(t, err, name)
+	}
+	for _, name := range []string{"js", "jsTracer", "{}"} {
+		_, err := d.New(name, nil, true)
+		assert.Equal(t, ErrJSTracerDisabled, err, name)
+	}
+}
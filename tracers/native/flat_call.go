@@ -0,0 +1,155 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/vechain/thor/v2/tracers"
+	"github.com/vechain/thor/v2/vm"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("flatCallTracer", newFlatCallTracer, false)
+}
+
+// flatCall is an entry of the flat call list.
+type flatCall struct {
+	Type    string          `json:"type"`
+	Depth   int             `json:"depth"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to,omitempty"`
+	Gas     hexutil.Uint64  `json:"gas"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Value   *hexutil.Big    `json:"value,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	// Reverted is true if the call itself or any of its callers failed, so its effects are discarded.
+	Reverted bool `json:"reverted,omitempty"`
+}
+
+// flatCallTracer is a native go tracer which lists calls of a clause in execution order,
+// annotated with the call depth, the top call at depth 0.
+type flatCallTracer struct {
+	noopTracer
+	calls     []flatCall
+	stack     []int // indices of the entered calls
+	gasLimit  uint64
+	interrupt atomic.Value // Atomic flag to signal execution interruption
+	reason    error        // Textual reason for the interruption
+}
+
+// newFlatCallTracer returns a native go tracer which tracks calls of a clause as a flat list.
+func newFlatCallTracer(_ json.RawMessage) (tracers.Tracer, error) {
+	return &flatCallTracer{}, nil
+}
+
+func (t *flatCallTracer) enter(typ vm.OpCode, from common.Address, to common.Address, gas uint64, value *big.Int) {
+	toCopy := to
+	t.stack = append(t.stack, len(t.calls))
+	t.calls = append(t.calls, flatCall{
+		Type:  typ.String(),
+		Depth: len(t.stack) - 1,
+		From:  from,
+		To:    &toCopy,
+		Gas:   hexutil.Uint64(gas),
+		Value: (*hexutil.Big)(value),
+	})
+}
+
+func (t *flatCallTracer) exit(gasUsed uint64, err error) {
+	if len(t.stack) == 0 {
+		return
+	}
+	call := &t.calls[t.stack[len(t.stack)-1]]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	call.GasUsed = hexutil.Uint64(gasUsed)
+	if err != nil {
+		call.Error = err.Error()
+		if call.Type == vm.CREATE.String() || call.Type == vm.CREATE2.String() {
+			call.To = nil
+		}
+	}
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *flatCallTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	typ := vm.CALL
+	if create {
+		typ = vm.CREATE
+	}
+	t.enter(typ, from, to, t.gasLimit, value)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *flatCallTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	// gas used of the top call is set on clause end
+	t.exit(0, err)
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *flatCallTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Skip if tracing was interrupted
+	if stop := t.interrupt.Load(); stop != nil && stop.(bool) {
+		return
+	}
+	t.enter(typ, from, to, gas, value)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *flatCallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	// keep the top call, which is exited by CaptureEnd
+	if len(t.stack) <= 1 {
+		return
+	}
+	// Skip if tracing was interrupted
+	if stop := t.interrupt.Load(); stop != nil && stop.(bool) {
+		return
+	}
+	t.exit(gasUsed, err)
+}
+
+func (t *flatCallTracer) CaptureClauseStart(gasLimit uint64) {
+	t.gasLimit = gasLimit
+}
+
+func (t *flatCallTracer) CaptureClauseEnd(restGas uint64) {
+	if len(t.calls) > 0 {
+		t.calls[0].GasUsed = hexutil.Uint64(t.gasLimit - restGas)
+	}
+}
+
+// GetResult returns the json-encoded flat list of calls, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *flatCallTracer) GetResult() (json.RawMessage, error) {
+	if len(t.calls) == 0 {
+		return nil, errors.New("no top-level call")
+	}
+	// failed[d] tells whether the latest call at depth d is reverted
+	var failed []bool
+	for i := range t.calls {
+		call := &t.calls[i]
+		failed = append(failed[:call.Depth], call.Error != "" || (call.Depth > 0 && failed[call.Depth-1]))
+		call.Reverted = failed[call.Depth]
+	}
+	res, err := json.Marshal(t.calls)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *flatCallTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
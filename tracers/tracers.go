@@ -56,6 +56,9 @@ type elem struct {
 	isJS bool
 }
 
+// ErrJSTracerDisabled is returned when a JS tracer is requested while JS tracers are disabled.
+var ErrJSTracerDisabled = errors.New("js tracer disabled")
+
 // DefaultDirectory is the collection of tracers bundled by default.
 var DefaultDirectory = directory{elems: make(map[string]elem)}
 
@@ -63,8 +66,9 @@ var DefaultDirectory = directory{elems: make(map[string]elem)}
 // and a function to instantiate it. It falls back to a JS code evaluator
 // if no tracer of the given name exists.
 type directory struct {
-	elems  map[string]elem
-	jsEval jsCtorFn
+	elems      map[string]elem
+	jsEval     jsCtorFn
+	jsDisabled bool
 }
 
 // Register registers a method as a lookup for tracers, meaning that
@@ -79,19 +83,32 @@ func (d *directory) RegisterJSEval(f jsCtorFn) {
 	d.jsEval = f
 }
 
+// DisableJS disables tracers evaluating JS code, both the bundled and custom ones,
+// while native tracers are still available. It should be called before serving.
+func (d *directory) DisableJS() {
+	d.jsDisabled = true
+}
+
 // New returns a new instance of a tracer, by iterating through the
 // registered lookups. Name is either name of an existing tracer
 // or an arbitrary JS code.
 func (d *directory) New(name string, cfg json.RawMessage, allowCustom bool) (Tracer, error) {
-	if elem, ok := d.elems[name]; ok {
-		return elem.ctor(cfg)
+	elem, ok := d.elems[name]
+	if !ok {
+		// backward compatible, allow users emit "Tracer" suffix
+		elem, ok = d.elems[name+"Tracer"]
 	}
-	// backward compatible, allow users emit "Tracer" suffix
-	if elem, ok := d.elems[name+"Tracer"]; ok {
+	if ok {
+		if elem.isJS && d.jsDisabled {
+			return nil, ErrJSTracerDisabled
+		}
 		return elem.ctor(cfg)
 	}
 
 	if allowCustom {
+		if d.jsDisabled || d.jsEval == nil {
+			return nil, ErrJSTracerDisabled
+		}
 		// Assume JS code
 		tracer, err := d.jsEval(name, cfg)
 		if err != nil {
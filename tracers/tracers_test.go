@@ -239,6 +239,77 @@ func TestCallTracers(t *testing.T) {
 	}
 }
 
+type flatCall struct {
+	Type     string                `json:"type"`
+	Depth    int                   `json:"depth"`
+	From     thor.Address          `json:"from"`
+	To       thor.Address          `json:"to,omitempty"`
+	Gas      math.HexOrDecimal64   `json:"gas"`
+	GasUsed  math.HexOrDecimal64   `json:"gasUsed"`
+	Value    *math.HexOrDecimal256 `json:"value,omitempty"`
+	Error    string                `json:"error,omitempty"`
+	Reverted bool                  `json:"reverted,omitempty"`
+}
+
+func flattenCalls(frame callFrame, depth int, parentReverted bool) []flatCall {
+	reverted := parentReverted || frame.Error != ""
+	calls := []flatCall{{
+		Type:     frame.Type,
+		Depth:    depth,
+		From:     frame.From,
+		To:       frame.To,
+		Gas:      frame.Gas,
+		GasUsed:  frame.GasUsed,
+		Value:    frame.Value,
+		Error:    frame.Error,
+		Reverted: reverted,
+	}}
+	for _, sub := range frame.Calls {
+		calls = append(calls, flattenCalls(sub, depth+1, reverted)...)
+	}
+	return calls
+}
+
+func TestFlatCallTracer(t *testing.T) {
+	files, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		f := file
+		t.Run(strings.TrimSuffix(f.Name(), ".json"), func(t *testing.T) {
+			var testData callTest
+			if blob, err := os.ReadFile(filepath.Join("testdata", file.Name())); err != nil {
+				t.Fatalf("failed to read testcase: %v", err)
+			} else if err := json.Unmarshal(blob, &testData); err != nil {
+				t.Fatalf("failed to parse testcase: %v", err)
+			}
+			// the flat call tracer has no config
+			testData.Config = nil
+
+			expected := testData.Calls
+			if strings.HasPrefix(f.Name(), "call_only_top") || strings.HasPrefix(f.Name(), "call_with_log") {
+				// expected calls are customized by config, trace them with the call tracer
+				var frame callFrame
+				if err := json.Unmarshal(RunTracerTest(t, &testData.traceTest, "callTracer"), &frame); err != nil {
+					t.Fatal(err)
+				}
+				expected = frame
+			}
+
+			result := RunTracerTest(t, &testData.traceTest, "flatCallTracer")
+			var got []flatCall
+			if err := json.Unmarshal(result, &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, flattenCalls(expected, 0, false), got)
+		})
+	}
+}
+
 func TestPreStateTracers(t *testing.T) {
 	files, err := os.ReadDir("testdata/prestate_diff")
 	if err != nil {
@@ -149,7 +149,10 @@ func (e *ExtendedTrie) Update(key, value, meta []byte) error {
 // database and can be used even if the trie doesn't have one.
 func (e *ExtendedTrie) Hash() thor.Bytes32 {
 	t := &e.trie
-	return t.Hash()
+	// hash as CommitTo does, so that the cached hashes are reused on commit, e.g. of non-crypto nodes
+	hash, cached, _ := e.hashRoot(nil, 0)
+	t.root = cached
+	return hash.(*hashNode).Hash
 }
 
 // Commit writes all nodes with the given sequence number to the trie's database.
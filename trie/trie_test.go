@@ -703,6 +703,39 @@ func TestNonCryptoExtended(t *testing.T) {
 	}
 }
 
+func TestExtendedHashBeforeCommit(t *testing.T) {
+	for _, nonCrypto := range []bool{false, true} {
+		var (
+			db1 = &kedb{ethdb.NewMemDatabase()}
+			db2 = &kedb{ethdb.NewMemDatabase()}
+			tr1 = NewExtended(thor.Bytes32{}, 0, db1, nonCrypto)
+			tr2 = NewExtended(thor.Bytes32{}, 0, db2, nonCrypto)
+		)
+		for i := uint32(0); i < 100; i++ {
+			var k [4]byte
+			binary.BigEndian.PutUint32(k[:], i)
+			tr1.Update(k[:], thor.Blake2b(k[:]).Bytes(), nil)
+			tr2.Update(k[:], thor.Blake2b(k[:]).Bytes(), nil)
+		}
+
+		// hashed first, the cached hashes are reused on commit
+		hash := tr2.Hash()
+		root2, err := tr2.Commit(1)
+		assert.Nil(t, err)
+		assert.Equal(t, hash, root2)
+
+		root1, err := tr1.Commit(1)
+		assert.Nil(t, err)
+		assert.Equal(t, root1, root2)
+		assert.Equal(t, db1.Len(), db2.Len())
+		for _, key := range db1.Keys() {
+			v1, _ := db1.Get(key)
+			v2, _ := db2.Get(key)
+			assert.Equal(t, v1, v2)
+		}
+	}
+}
+
 func TestExtendedCached(t *testing.T) {
 	db := ethdb.NewMemDatabase()
 	tr := NewExtended(thor.Bytes32{}, 0, db, false)
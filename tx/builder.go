@@ -7,13 +7,36 @@ package tx
 
 import (
 	"encoding/binary"
+	"errors"
+	"math/big"
 
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/vechain/thor/v2/thor"
 )
 
 // Builder to make it easy to build transaction.
 type Builder struct {
-	body body
+	body            body
+	gasPriceCoefSet bool
+}
+
+// NewBuilderFromTx creates a builder with the body of the given tx, to build a variant of it.
+// The body is deep copied so that the given tx is never affected, and the signature is dropped.
+func NewBuilderFromTx(tx *Transaction) *Builder {
+	body := tx.body
+	body.Clauses = make([]*Clause, 0, len(tx.body.Clauses))
+	for _, c := range tx.body.Clauses {
+		body.Clauses = append(body.Clauses, NewClause(c.body.To).WithValue(c.body.Value).WithData(c.body.Data))
+	}
+	if tx.body.DependsOn != nil {
+		cpy := *tx.body.DependsOn
+		body.DependsOn = &cpy
+	}
+	body.Reserved.Unused = append([]rlp.RawValue(nil), tx.body.Reserved.Unused...)
+	body.Signature = nil
+	body.maxFeePerGas = copyBig(tx.body.maxFeePerGas)
+	body.maxPriorityFeePerGas = copyBig(tx.body.maxPriorityFeePerGas)
+	return &Builder{body: body}
 }
 
 // ChainTag set chain tag.
@@ -31,6 +54,19 @@ func (b *Builder) Clause(c *Clause) *Builder {
 // GasPriceCoef set gas price coef.
 func (b *Builder) GasPriceCoef(coef uint8) *Builder {
 	b.body.GasPriceCoef = coef
+	b.gasPriceCoefSet = true
+	return b
+}
+
+// MaxFeePerGas set max fee per gas, which makes it a dynamic fee tx.
+func (b *Builder) MaxFeePerGas(fee *big.Int) *Builder {
+	b.body.maxFeePerGas = copyBig(fee)
+	return b
+}
+
+// MaxPriorityFeePerGas set max priority fee per gas, which makes it a dynamic fee tx.
+func (b *Builder) MaxPriorityFeePerGas(fee *big.Int) *Builder {
+	b.body.maxPriorityFeePerGas = copyBig(fee)
 	return b
 }
 
@@ -46,6 +82,17 @@ func (b *Builder) BlockRef(br BlockRef) *Builder {
 	return b
 }
 
+// BlockRefFromID set block reference derived from the block id.
+func (b *Builder) BlockRefFromID(id thor.Bytes32) *Builder {
+	return b.BlockRef(NewBlockRefFromID(id))
+}
+
+// BlockRefFromHeader set block reference derived from the block header, e.g. *block.Header.
+// The header is taken as an interface since package block depends on tx.
+func (b *Builder) BlockRefFromHeader(h interface{ ID() thor.Bytes32 }) *Builder {
+	return b.BlockRefFromID(h.ID())
+}
+
 // Expiration set expiration.
 func (b *Builder) Expiration(exp uint32) *Builder {
 	b.body.Expiration = exp
@@ -75,8 +122,99 @@ func (b *Builder) Features(feat Features) *Builder {
 	return b
 }
 
+// DelegatePayment set the delegation feature, so that the tx fee is paid by a delegator (VIP-191).
+// The tx should be signed by both origin and delegator.
+func (b *Builder) DelegatePayment() *Builder {
+	b.body.Reserved.Features.SetDelegated(true)
+	return b
+}
+
 // Build build tx object.
+// It's a dynamic fee tx if any of the dynamic fee fields is set, otherwise a legacy tx.
+// The fields are not validated, see BuildChecked.
 func (b *Builder) Build() *Transaction {
-	tx := Transaction{body: b.body}
+	body := b.body
+	if body.maxFeePerGas != nil || body.maxPriorityFeePerGas != nil {
+		if body.maxFeePerGas == nil {
+			body.maxFeePerGas = new(big.Int)
+		}
+		if body.maxPriorityFeePerGas == nil {
+			body.maxPriorityFeePerGas = new(big.Int)
+		}
+		body.txType = TypeDynamicFee
+		body.GasPriceCoef = 0
+	}
+	tx := Transaction{body: body}
 	return &tx
 }
+
+// BuildChecked is like Build, but returns an error if the fields are invalid for the tx type,
+// e.g. the gas price coef is set along with the dynamic fee fields.
+func (b *Builder) BuildChecked() (*Transaction, error) {
+	if b.body.maxFeePerGas != nil || b.body.maxPriorityFeePerGas != nil {
+		if b.gasPriceCoefSet {
+			return nil, errors.New("gas price coef can not be set for dynamic fee tx")
+		}
+		maxFee, maxPriorityFee := b.body.maxFeePerGas, b.body.maxPriorityFeePerGas
+		if maxFee == nil {
+			maxFee = new(big.Int)
+		}
+		if maxPriorityFee == nil {
+			maxPriorityFee = new(big.Int)
+		}
+		if maxFee.Sign() < 0 || maxPriorityFee.Sign() < 0 {
+			return nil, errors.New("negative fee per gas")
+		}
+		if maxPriorityFee.Cmp(maxFee) > 0 {
+			return nil, errors.New("max priority fee per gas exceeds max fee per gas")
+		}
+	}
+	return b.Build(), nil
+}
+
+// BuildStrict is like BuildChecked, but additionally requires the chain tag to be set and at least one clause,
+// to catch the mistake of sending a tx to the wrong network.
+func (b *Builder) BuildStrict() (*Transaction, error) {
+	if b.body.ChainTag == 0 {
+		return nil, errors.New("chain tag not set")
+	}
+	if len(b.body.Clauses) == 0 {
+		return nil, errors.New("no clause")
+	}
+	return b.BuildChecked()
+}
+
+// IntrinsicGas returns the intrinsic gas of the clauses added so far, which is the minimum gas
+// the transaction to be built requires.
+func (b *Builder) IntrinsicGas() (uint64, error) {
+	return IntrinsicGas(b.body.Clauses...)
+}
+
+// SigningHash builds the tx and returns the hash for the origin to sign, e.g. by an external signer, since
+// the built tx is unsigned. The signature is attached by WithSignature of the built tx. If the payment is
+// delegated, the signing hash covers the delegation feature, and the delegator signs a different hash,
+// see DelegatorSigningHash.
+func (b *Builder) SigningHash() (thor.Bytes32, error) {
+	tx, err := b.BuildChecked()
+	if err != nil {
+		return thor.Bytes32{}, err
+	}
+	return tx.SigningHash(), nil
+}
+
+// DelegatorSigningHash builds the tx and returns the hash for the delegator to sign, on behalf of the origin.
+// The signature is attached after the origin's, i.e. WithSignature(append(originSig, delegatorSig...)).
+func (b *Builder) DelegatorSigningHash(origin thor.Address) (thor.Bytes32, error) {
+	tx, err := b.BuildChecked()
+	if err != nil {
+		return thor.Bytes32{}, err
+	}
+	return tx.DelegatorSigningHash(origin), nil
+}
+
+func copyBig(x *big.Int) *big.Int {
+	if x == nil {
+		return nil
+	}
+	return new(big.Int).Set(x)
+}
@@ -6,9 +6,335 @@
 package tx_test
 
 import (
+	"math"
+	"math/big"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
 )
 
 func TestBuilder(t *testing.T) {
+	var feat tx.Features
+	feat.SetDelegated(true)
+
+	trx := new(tx.Builder).
+		ChainTag(1).
+		Expiration(math.MaxUint32).
+		Features(feat).
+		Build()
+
+	assert.Equal(t, uint32(math.MaxUint32), trx.Expiration())
+	assert.True(t, trx.Features().IsDelegated())
+
+	data, err := rlp.EncodeToBytes(trx)
+	assert.Nil(t, err)
+
+	var decoded tx.Transaction
+	assert.Nil(t, rlp.DecodeBytes(data, &decoded))
+	assert.Equal(t, trx.Expiration(), decoded.Expiration())
+	assert.Equal(t, trx.Features(), decoded.Features())
+	assert.Equal(t, trx.ID(), decoded.ID())
+}
+
+func TestBuilderDelegatePayment(t *testing.T) {
+	origin, _ := crypto.GenerateKey()
+	delegator, _ := crypto.GenerateKey()
+
+	trx := new(tx.Builder).
+		ChainTag(1).
+		Gas(21000).
+		Expiration(100).
+		DelegatePayment().
+		Build()
+	assert.True(t, trx.Features().IsDelegated())
+
+	originAddr := thor.Address(crypto.PubkeyToAddress(origin.PublicKey))
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), origin)
+	dsig, _ := crypto.Sign(trx.DelegatorSigningHash(originAddr).Bytes(), delegator)
+	trx = trx.WithSignature(append(sig, dsig...))
+
+	data, err := rlp.EncodeToBytes(trx)
+	assert.Nil(t, err)
+
+	var decoded tx.Transaction
+	assert.Nil(t, rlp.DecodeBytes(data, &decoded))
+	assert.True(t, decoded.Features().IsDelegated())
+	assert.Equal(t, trx.ID(), decoded.ID())
+
+	gotOrigin, err := decoded.Origin()
+	assert.Nil(t, err)
+	assert.Equal(t, originAddr, gotOrigin)
+	gotDelegator, err := decoded.Delegator()
+	assert.Nil(t, err)
+	assert.Equal(t, thor.Address(crypto.PubkeyToAddress(delegator.PublicKey)), *gotDelegator)
+
+	// works along with the dynamic fee
+	trx = new(tx.Builder).MaxFeePerGas(big.NewInt(100)).DelegatePayment().Build()
+	data, err = rlp.EncodeToBytes(trx)
+	assert.Nil(t, err)
+	assert.Nil(t, rlp.DecodeBytes(data, &decoded))
+	assert.Equal(t, tx.TypeDynamicFee, decoded.Type())
+	assert.True(t, decoded.Features().IsDelegated())
+}
+
+func TestBuilderGasPriceCoef(t *testing.T) {
+	baseGasPrice := big.NewInt(1e15)
+	for _, coef := range []uint8{0, 128, 255} {
+		trx := new(tx.Builder).
+			ChainTag(1).
+			GasPriceCoef(coef).
+			Gas(21000).
+			Build()
+
+		data, err := rlp.EncodeToBytes(trx)
+		assert.Nil(t, err)
+
+		var decoded tx.Transaction
+		assert.Nil(t, rlp.DecodeBytes(data, &decoded))
+		assert.Equal(t, coef, decoded.GasPriceCoef())
+		assert.Equal(t, trx.ID(), decoded.ID())
+
+		// gasPrice = baseGasPrice + baseGasPrice * gasPriceCoef / 255
+		expected := new(big.Int).Mul(baseGasPrice, big.NewInt(int64(coef)))
+		expected.Div(expected, big.NewInt(255)).Add(expected, baseGasPrice)
+		assert.Equal(t, expected, decoded.GasPrice(baseGasPrice))
+	}
+}
+
+func TestBuilderDynamicFee(t *testing.T) {
+	trx := new(tx.Builder).
+		ChainTag(1).
+		MaxFeePerGas(big.NewInt(100)).
+		MaxPriorityFeePerGas(big.NewInt(10)).
+		Build()
+	assert.Equal(t, tx.TypeDynamicFee, trx.Type())
+	assert.Equal(t, big.NewInt(100), trx.MaxFeePerGas())
+	assert.Equal(t, big.NewInt(10), trx.MaxPriorityFeePerGas())
+
+	// legacy by default
+	trx = new(tx.Builder).GasPriceCoef(10).Build()
+	assert.Equal(t, tx.TypeLegacy, trx.Type())
+	assert.Equal(t, uint8(10), trx.GasPriceCoef())
+	assert.Equal(t, new(big.Int), trx.MaxFeePerGas())
+
+	// unset fee field defaults to 0
+	trx = new(tx.Builder).MaxFeePerGas(big.NewInt(100)).Build()
+	assert.Equal(t, tx.TypeDynamicFee, trx.Type())
+	assert.Equal(t, new(big.Int), trx.MaxPriorityFeePerGas())
+}
+
+func TestBuilderChecked(t *testing.T) {
+	trx, err := new(tx.Builder).
+		ChainTag(1).
+		MaxFeePerGas(big.NewInt(100)).
+		MaxPriorityFeePerGas(big.NewInt(10)).
+		BuildChecked()
+	assert.Nil(t, err)
+	assert.Equal(t, tx.TypeDynamicFee, trx.Type())
+
+	trx, err = new(tx.Builder).GasPriceCoef(10).BuildChecked()
+	assert.Nil(t, err)
+	assert.Equal(t, tx.TypeLegacy, trx.Type())
+
+	_, err = new(tx.Builder).GasPriceCoef(0).MaxFeePerGas(big.NewInt(100)).BuildChecked()
+	assert.EqualError(t, err, "gas price coef can not be set for dynamic fee tx")
+
+	_, err = new(tx.Builder).MaxFeePerGas(big.NewInt(10)).MaxPriorityFeePerGas(big.NewInt(100)).BuildChecked()
+	assert.EqualError(t, err, "max priority fee per gas exceeds max fee per gas")
+
+	_, err = new(tx.Builder).MaxFeePerGas(big.NewInt(-1)).BuildChecked()
+	assert.EqualError(t, err, "negative fee per gas")
+
+	_, err = new(tx.Builder).GasPriceCoef(0).MaxFeePerGas(big.NewInt(100)).SigningHash()
+	assert.EqualError(t, err, "gas price coef can not be set for dynamic fee tx")
+}
+
+func TestBuilderStrict(t *testing.T) {
+	to := thor.BytesToAddress([]byte("to"))
+
+	trx, err := new(tx.Builder).
+		ChainTag(1).
+		Clause(tx.NewClause(&to)).
+		BuildStrict()
+	assert.Nil(t, err)
+	assert.Equal(t, byte(1), trx.ChainTag())
+
+	_, err = new(tx.Builder).Clause(tx.NewClause(&to)).BuildStrict()
+	assert.EqualError(t, err, "chain tag not set")
+
+	_, err = new(tx.Builder).ChainTag(1).BuildStrict()
+	assert.EqualError(t, err, "no clause")
+
+	_, err = new(tx.Builder).ChainTag(1).Clause(tx.NewClause(&to)).MaxFeePerGas(big.NewInt(-1)).BuildStrict()
+	assert.EqualError(t, err, "negative fee per gas")
+
+	// non-strict build defaults chain tag to 0
+	trx = new(tx.Builder).Build()
+	assert.Equal(t, byte(0), trx.ChainTag())
+}
+
+func TestNewBuilderFromTx(t *testing.T) {
+	to := thor.BytesToAddress([]byte("to"))
+	dep := thor.Bytes32{1}
+	orig := new(tx.Builder).
+		ChainTag(1).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(10)).WithData([]byte{1, 2})).
+		GasPriceCoef(10).
+		Gas(21000).
+		Expiration(100).
+		Nonce(1).
+		DependsOn(&dep).
+		DelegatePayment().
+		Build()
+	key, _ := crypto.GenerateKey()
+	sig, _ := crypto.Sign(orig.SigningHash().Bytes(), key)
+	orig = orig.WithSignature(sig)
+	origHash := orig.SigningHash()
+
+	// unmodified
+	trx := tx.NewBuilderFromTx(orig).Build()
+	assert.Equal(t, origHash, trx.SigningHash())
+	assert.Nil(t, trx.Signature())
+
+	// bump gas price and add a clause
+	trx = tx.NewBuilderFromTx(orig).
+		GasPriceCoef(20).
+		Clause(tx.NewClause(nil)).
+		Build()
+	assert.Equal(t, uint8(20), trx.GasPriceCoef())
+	assert.Equal(t, 2, len(trx.Clauses()))
+	assert.Equal(t, &dep, trx.DependsOn())
+	assert.True(t, trx.Features().IsDelegated())
+
+	assert.Equal(t, uint8(10), orig.GasPriceCoef())
+	assert.Equal(t, 1, len(orig.Clauses()))
+	assert.Equal(t, origHash, orig.SigningHash())
+	assert.Equal(t, sig, orig.Signature())
+
+	// to dynamic fee
+	trx = tx.NewBuilderFromTx(orig).MaxFeePerGas(big.NewInt(100)).Build()
+	assert.Equal(t, tx.TypeDynamicFee, trx.Type())
+	assert.Equal(t, uint8(0), trx.GasPriceCoef())
+
+	// dynamic fee fields are copied
+	dyn := new(tx.Builder).MaxFeePerGas(big.NewInt(100)).MaxPriorityFeePerGas(big.NewInt(10)).Build()
+	trx = tx.NewBuilderFromTx(dyn).MaxPriorityFeePerGas(big.NewInt(20)).Build()
+	assert.Equal(t, big.NewInt(100), trx.MaxFeePerGas())
+	assert.Equal(t, big.NewInt(20), trx.MaxPriorityFeePerGas())
+	assert.Equal(t, big.NewInt(10), dyn.MaxPriorityFeePerGas())
+}
+
+func TestBuilderBlockRefFromID(t *testing.T) {
+	header := new(block.Builder).ParentID(thor.Bytes32{0, 0, 0, 9}).Timestamp(1000).Build().Header()
+	id := header.ID()
+
+	var want tx.BlockRef
+	copy(want[:], id[:8])
+
+	trx := new(tx.Builder).BlockRefFromID(id).Build()
+	assert.Equal(t, want, trx.BlockRef())
+	assert.Equal(t, header.Number(), trx.BlockRef().Number())
+
+	trx = new(tx.Builder).BlockRefFromHeader(header).Build()
+	assert.Equal(t, want, trx.BlockRef())
+}
+
+func TestBuilderIntrinsicGas(t *testing.T) {
+	to := thor.BytesToAddress([]byte("to"))
+	tests := []struct {
+		name    string
+		clauses []*tx.Clause
+		want    uint64
+	}{
+		{"no clause", nil, 21000},
+		{"transfer", []*tx.Clause{tx.NewClause(&to).WithValue(big.NewInt(1))}, 21000},
+		{"contract creation", []*tx.Clause{tx.NewClause(nil)}, 53000},
+		// 2 zero bytes * 4 + 2 non-zero bytes * 68
+		{"call with data", []*tx.Clause{tx.NewClause(&to).WithData([]byte{0, 1, 0, 2})}, 21000 + 2*4 + 2*68},
+		// 5000 tx base + 16000 per clause + 48000 per creation clause
+		{"multiple clauses", []*tx.Clause{tx.NewClause(&to), tx.NewClause(&to), tx.NewClause(nil)}, 5000 + 16000*2 + 48000},
+	}
+	for _, tt := range tests {
+		builder := new(tx.Builder)
+		for _, c := range tt.clauses {
+			builder.Clause(c)
+		}
+		gas, err := builder.IntrinsicGas()
+		assert.Nil(t, err, tt.name)
+		assert.Equal(t, tt.want, gas, tt.name)
+
+		// equal to the built tx
+		trx := builder.Gas(gas).Build()
+		txGas, err := trx.IntrinsicGas()
+		assert.Nil(t, err, tt.name)
+		assert.Equal(t, gas, txGas, tt.name)
+	}
+}
+
+func TestBuilderSigningHash(t *testing.T) {
+	origin, _ := crypto.GenerateKey()
+	delegator, _ := crypto.GenerateKey()
+	originAddr := thor.Address(crypto.PubkeyToAddress(origin.PublicKey))
+
+	newBuilder := func() *tx.Builder {
+		return new(tx.Builder).
+			ChainTag(1).
+			Clause(tx.NewClause(&thor.Address{}).WithValue(big.NewInt(1))).
+			Gas(21000).
+			Expiration(100)
+	}
+
+	t.Run("non-delegated", func(t *testing.T) {
+		hash, err := newBuilder().SigningHash()
+		assert.Nil(t, err)
+
+		trx := newBuilder().Build()
+		assert.Equal(t, trx.SigningHash(), hash)
+
+		// signed externally
+		sig, _ := crypto.Sign(hash.Bytes(), origin)
+		trx = trx.WithSignature(sig)
+		gotOrigin, err := trx.Origin()
+		assert.Nil(t, err)
+		assert.Equal(t, originAddr, gotOrigin)
+	})
+
+	t.Run("delegated", func(t *testing.T) {
+		hash, err := newBuilder().DelegatePayment().SigningHash()
+		assert.Nil(t, err)
+		dhash, err := newBuilder().DelegatePayment().DelegatorSigningHash(originAddr)
+		assert.Nil(t, err)
+
+		plain, _ := newBuilder().SigningHash()
+		assert.NotEqual(t, plain, hash, "delegation feature should be covered")
+		assert.NotEqual(t, hash, dhash, "delegator should sign a different hash")
+
+		trx := newBuilder().DelegatePayment().Build()
+		assert.Equal(t, trx.SigningHash(), hash)
+		assert.Equal(t, trx.DelegatorSigningHash(originAddr), dhash)
+
+		sig, _ := crypto.Sign(hash.Bytes(), origin)
+		dsig, _ := crypto.Sign(dhash.Bytes(), delegator)
+		trx = trx.WithSignature(append(sig, dsig...))
+		gotOrigin, err := trx.Origin()
+		assert.Nil(t, err)
+		assert.Equal(t, originAddr, gotOrigin)
+		gotDelegator, err := trx.Delegator()
+		assert.Nil(t, err)
+		assert.Equal(t, thor.Address(crypto.PubkeyToAddress(delegator.PublicKey)), *gotDelegator)
+		// the delegator signing hash is the tx id
+		assert.Equal(t, trx.ID(), dhash)
+	})
 
+	t.Run("build error", func(t *testing.T) {
+		_, err := newBuilder().GasPriceCoef(1).MaxFeePerGas(big.NewInt(1)).SigningHash()
+		assert.NotNil(t, err)
+		_, err = newBuilder().GasPriceCoef(1).MaxFeePerGas(big.NewInt(1)).DelegatorSigningHash(originAddr)
+		assert.NotNil(t, err)
+	})
 }
@@ -24,6 +24,17 @@ import (
 
 var (
 	errIntrinsicGasOverflow = errors.New("intrinsic gas overflow")
+
+	// ErrTxTypeNotSupported is returned when decoding a typed tx of unknown type.
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+)
+
+// Transaction types.
+const (
+	// TypeLegacy is the original tx type, which pays gas by gas price coef.
+	TypeLegacy = byte(0x00)
+	// TypeDynamicFee is the EIP-1559 style tx type, which pays gas by max fee and max priority fee.
+	TypeDynamicFee = byte(0x51)
 )
 
 // Transaction is an immutable tx type.
@@ -54,6 +65,39 @@ type body struct {
 	Nonce        uint64
 	Reserved     reserved
 	Signature    []byte
+
+	// fields below are not part of the legacy encoding
+	txType               byte
+	maxPriorityFeePerGas *big.Int
+	maxFeePerGas         *big.Int
+}
+
+// dynamicFeeBody is the encoding of the dynamic fee tx body.
+type dynamicFeeBody struct {
+	ChainTag             byte
+	BlockRef             uint64
+	Expiration           uint32
+	Clauses              []*Clause
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerGas         *big.Int
+	Gas                  uint64
+	DependsOn            *thor.Bytes32 `rlp:"nil"`
+	Nonce                uint64
+	Reserved             reserved
+	Signature            []byte
+}
+
+// feeFields returns the fields about gas price, which vary by tx type.
+func (b *body) feeFields() []interface{} {
+	if b.txType == TypeDynamicFee {
+		return []interface{}{b.maxPriorityFeePerGas, b.maxFeePerGas}
+	}
+	return []interface{}{b.GasPriceCoef}
+}
+
+// Type returns the type of tx.
+func (t *Transaction) Type() byte {
+	return t.body.txType
 }
 
 // ChainTag returns chain tag.
@@ -132,17 +176,22 @@ func (t *Transaction) UnprovedWork() (w *big.Int) {
 // EvaluateWork try to compute work when tx origin assumed.
 func (t *Transaction) EvaluateWork(origin thor.Address) func(nonce uint64) *big.Int {
 	hashWithoutNonce := thor.Blake2bFn(func(w io.Writer) {
-		rlp.Encode(w, []interface{}{
+		if t.body.txType != TypeLegacy {
+			w.Write([]byte{t.body.txType})
+		}
+		fields := []interface{}{
 			t.body.ChainTag,
 			t.body.BlockRef,
 			t.body.Expiration,
 			t.body.Clauses,
-			t.body.GasPriceCoef,
+		}
+		fields = append(fields, t.body.feeFields()...)
+		rlp.Encode(w, append(fields,
 			t.body.Gas,
 			t.body.DependsOn,
 			&t.body.Reserved,
 			origin,
-		})
+		))
 	})
 
 	return func(nonce uint64) *big.Int {
@@ -154,7 +203,7 @@ func (t *Transaction) EvaluateWork(origin thor.Address) func(nonce uint64) *big.
 	}
 }
 
-// SigningHash returns hash of tx excludes signature.
+// SigningHash returns hash of tx excludes signature, which is signed by the origin.
 func (t *Transaction) SigningHash() (hash thor.Bytes32) {
 	if cached := t.cache.signingHash.Load(); cached != nil {
 		return cached.(thor.Bytes32)
@@ -162,26 +211,49 @@ func (t *Transaction) SigningHash() (hash thor.Bytes32) {
 	defer func() { t.cache.signingHash.Store(hash) }()
 
 	return thor.Blake2bFn(func(w io.Writer) {
-		rlp.Encode(w, []interface{}{
+		// the type prefix keeps signing hashes of different tx types apart
+		if t.body.txType != TypeLegacy {
+			w.Write([]byte{t.body.txType})
+		}
+		fields := []interface{}{
 			t.body.ChainTag,
 			t.body.BlockRef,
 			t.body.Expiration,
 			t.body.Clauses,
-			t.body.GasPriceCoef,
+		}
+		fields = append(fields, t.body.feeFields()...)
+		rlp.Encode(w, append(fields,
 			t.body.Gas,
 			t.body.DependsOn,
 			t.body.Nonce,
 			&t.body.Reserved,
-		})
+		))
 	})
 }
 
 // GasPriceCoef returns gas price coef.
 // gas price = bgp + bgp * gpc / 255.
+// It's always 0 for dynamic fee tx.
 func (t *Transaction) GasPriceCoef() uint8 {
 	return t.body.GasPriceCoef
 }
 
+// MaxFeePerGas returns the max fee per gas. It's 0 for legacy tx.
+func (t *Transaction) MaxFeePerGas() *big.Int {
+	if t.body.maxFeePerGas == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(t.body.maxFeePerGas)
+}
+
+// MaxPriorityFeePerGas returns the max priority fee per gas. It's 0 for legacy tx.
+func (t *Transaction) MaxPriorityFeePerGas() *big.Int {
+	if t.body.maxPriorityFeePerGas == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(t.body.maxPriorityFeePerGas)
+}
+
 // Gas returns gas provision for this tx.
 func (t *Transaction) Gas() uint64 {
 	return t.body.Gas
@@ -292,19 +364,76 @@ func (t *Transaction) TestFeatures(supported Features) error {
 	return nil
 }
 
-// EncodeRLP implements rlp.Encoder
+// EncodeRLP implements rlp.Encoder.
+// Legacy tx is encoded as a list, while typed tx is encoded as a string of type byte followed by the encoded body.
 func (t *Transaction) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &t.body)
+	if t.body.txType == TypeLegacy {
+		return rlp.Encode(w, &t.body)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(t.body.txType)
+	if err := rlp.Encode(&buf, &dynamicFeeBody{
+		ChainTag:             t.body.ChainTag,
+		BlockRef:             t.body.BlockRef,
+		Expiration:           t.body.Expiration,
+		Clauses:              t.body.Clauses,
+		MaxPriorityFeePerGas: t.body.maxPriorityFeePerGas,
+		MaxFeePerGas:         t.body.maxFeePerGas,
+		Gas:                  t.body.Gas,
+		DependsOn:            t.body.DependsOn,
+		Nonce:                t.body.Nonce,
+		Reserved:             t.body.Reserved,
+		Signature:            t.body.Signature,
+	}); err != nil {
+		return err
+	}
+	return rlp.Encode(w, buf.Bytes())
 }
 
 // DecodeRLP implements rlp.Decoder
 func (t *Transaction) DecodeRLP(s *rlp.Stream) error {
-	_, size, _ := s.Kind()
-	var body body
-	if err := s.Decode(&body); err != nil {
+	kind, size, err := s.Kind()
+	if err != nil {
 		return err
 	}
-	*t = Transaction{body: body}
+
+	if kind == rlp.List {
+		var body body
+		if err := s.Decode(&body); err != nil {
+			return err
+		}
+		*t = Transaction{body: body}
+	} else {
+		data, err := s.Bytes()
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return errors.New("typed transaction too short")
+		}
+		if data[0] != TypeDynamicFee {
+			return ErrTxTypeNotSupported
+		}
+		var dyn dynamicFeeBody
+		if err := rlp.DecodeBytes(data[1:], &dyn); err != nil {
+			return err
+		}
+		*t = Transaction{body: body{
+			ChainTag:             dyn.ChainTag,
+			BlockRef:             dyn.BlockRef,
+			Expiration:           dyn.Expiration,
+			Clauses:              dyn.Clauses,
+			Gas:                  dyn.Gas,
+			DependsOn:            dyn.DependsOn,
+			Nonce:                dyn.Nonce,
+			Reserved:             dyn.Reserved,
+			Signature:            dyn.Signature,
+			txType:               TypeDynamicFee,
+			maxPriorityFeePerGas: dyn.MaxPriorityFeePerGas,
+			maxFeePerGas:         dyn.MaxFeePerGas,
+		}}
+	}
 
 	t.cache.size.Store(thor.StorageSize(rlp.ListSize(size)))
 	return nil
@@ -337,7 +466,16 @@ func (t *Transaction) IntrinsicGas() (uint64, error) {
 
 // GasPrice returns gas price.
 // gasPrice = baseGasPrice + baseGasPrice * gasPriceCoef / 255
+// For dynamic fee tx, gasPrice = min(maxFeePerGas, baseGasPrice + maxPriorityFeePerGas).
 func (t *Transaction) GasPrice(baseGasPrice *big.Int) *big.Int {
+	if t.body.txType == TypeDynamicFee {
+		x := new(big.Int).Add(baseGasPrice, t.body.maxPriorityFeePerGas)
+		if x.Cmp(t.body.maxFeePerGas) > 0 {
+			return x.Set(t.body.maxFeePerGas)
+		}
+		return x
+	}
+
 	x := big.NewInt(int64(t.body.GasPriceCoef))
 	x.Mul(x, baseGasPrice)
 	x.Div(x, big.NewInt(math.MaxUint8))
@@ -262,6 +262,61 @@ func TestDelegatedTx(t *testing.T) {
 	assert.Equal(t, "0xd3ae78222beadb038203be21ed5ce7c9b1bff602", func() string { s, _ := newTx.Delegator(); return s.String() }())
 }
 
+func TestDynamicFeeTx(t *testing.T) {
+	to, _ := thor.ParseAddress("0x7567d83b7b8d80addcb281a71d54fc7b3364ffed")
+	builder := func() *tx.Builder {
+		return new(tx.Builder).ChainTag(1).
+			BlockRef(tx.BlockRef{0, 0, 0, 0, 0xaa, 0xbb, 0xcc, 0xdd}).
+			Expiration(32).
+			Clause(tx.NewClause(&to).WithValue(big.NewInt(10000)).WithData([]byte{0, 0, 0, 0x60, 0x60, 0x60})).
+			Gas(21000).
+			Nonce(12345678)
+	}
+	legacy := builder().Build()
+	trx := builder().MaxFeePerGas(big.NewInt(150)).MaxPriorityFeePerGas(big.NewInt(20)).Build()
+
+	assert.NotEqual(t, legacy.SigningHash(), trx.SigningHash())
+	assert.Equal(t, big.NewInt(120), trx.GasPrice(big.NewInt(100)))
+	assert.Equal(t, big.NewInt(150), trx.GasPrice(big.NewInt(140)))
+
+	k, _ := hex.DecodeString("7582be841ca040aa940fff6c05773129e135623e41acce3e0b8ba520dc1ae26a")
+	priv, _ := crypto.ToECDSA(k)
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), priv)
+	trx = trx.WithSignature(sig)
+
+	data, err := rlp.EncodeToBytes(trx)
+	assert.Nil(t, err)
+	// encoded as a string, which starts with the type byte
+	content, _, err := rlp.SplitString(data)
+	assert.Nil(t, err)
+	assert.Equal(t, tx.TypeDynamicFee, content[0])
+
+	// no type prefix for legacy tx
+	legacyData, _ := rlp.EncodeToBytes(legacy)
+	kind, _, _, _ := rlp.Split(legacyData)
+	assert.Equal(t, rlp.List, kind)
+
+	var decoded tx.Transaction
+	assert.Nil(t, rlp.DecodeBytes(data, &decoded))
+	assert.Equal(t, tx.TypeDynamicFee, decoded.Type())
+	assert.Equal(t, trx.MaxFeePerGas(), decoded.MaxFeePerGas())
+	assert.Equal(t, trx.MaxPriorityFeePerGas(), decoded.MaxPriorityFeePerGas())
+	assert.Equal(t, trx.ID(), decoded.ID())
+	assert.Equal(t, trx.Size(), decoded.Size())
+	assert.Equal(t, "0xd989829d88b0ed1b06edf5c50174ecfa64f14a64", func() string { s, _ := decoded.Origin(); return s.String() }())
+
+	// mixed tx types in a list
+	var txs tx.Transactions
+	data, _ = rlp.EncodeToBytes(tx.Transactions{legacy, trx})
+	assert.Nil(t, rlp.DecodeBytes(data, &txs))
+	assert.Equal(t, tx.TypeLegacy, txs[0].Type())
+	assert.Equal(t, tx.TypeDynamicFee, txs[1].Type())
+
+	// unknown type
+	data, _ = rlp.EncodeToBytes([]byte{0x01, 0xc0})
+	assert.Equal(t, tx.ErrTxTypeNotSupported, rlp.DecodeBytes(data, &decoded))
+}
+
 func TestIntrinsicGas(t *testing.T) {
 	gas, err := tx.IntrinsicGas()
 	assert.Nil(t, err)
@@ -9,4 +9,12 @@ import (
 	"github.com/vechain/thor/v2/metrics"
 )
 
-var metricTxPoolGauge = metrics.LazyLoadGaugeVec("txpool_current_tx_count", []string{"source", "total"})
+var (
+	metricTxPoolGauge = metrics.LazyLoadGaugeVec("txpool_current_tx_count", []string{"source", "total"})
+
+	metricTxPoolSize          = metrics.LazyLoadGauge("txpool_size_gauge")                                  // txs in the pool
+	metricTxPoolAccounts      = metrics.LazyLoadGauge("txpool_account_gauge")                               // accounts(origin or delegator) occupying the pool
+	metricTxPoolExpired       = metrics.LazyLoadCounter("txpool_expired_tx_count")                          // txs washed out for exceeding max lifetime
+	metricTxPoolDepPending    = metrics.LazyLoadGauge("txpool_dependency_pending_gauge")                    // txs blocked on an unmet dependency
+	metricTxPoolQuotaExceeded = metrics.LazyLoadCounterVec("txpool_quota_exceeded_count", []string{"type"}) // txs rejected for exceeding limit per account
+)
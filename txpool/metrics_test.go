@@ -0,0 +1,80 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/metrics"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/tx"
+)
+
+func init() {
+	metrics.InitializePrometheusMetrics()
+}
+
+// readMetric returns the value of the named metric with the given labels, 0 if not found.
+func readMetric(t *testing.T, name string, labels map[string]string) float64 {
+	rec := httptest.NewRecorder()
+	metrics.HTTPHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(rec.Body)
+	assert.Nil(t, err)
+
+	family, ok := families["thor_metrics_"+name]
+	if !ok {
+		return 0
+	}
+next:
+	for _, m := range family.GetMetric() {
+		for _, l := range m.GetLabel() {
+			if labels[l.GetName()] != l.GetValue() {
+				continue next
+			}
+		}
+		if m.GetCounter() != nil {
+			return m.GetCounter().GetValue()
+		}
+		return m.GetGauge().GetValue()
+	}
+	return 0
+}
+
+func TestTxObjectMapMetrics(t *testing.T) {
+	db := muxdb.NewMem()
+	repo := newChainRepo(db)
+
+	tx1 := newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[0])
+	tx2 := newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[0])
+	tx3 := newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[1])
+
+	txObj1, _ := resolveTx(tx1, false)
+	txObj2, _ := resolveTx(tx2, false)
+	txObj3, _ := resolveTx(tx3, false)
+
+	size := readMetric(t, "txpool_size_gauge", nil)
+	accounts := readMetric(t, "txpool_account_gauge", nil)
+	exceeded := readMetric(t, "txpool_quota_exceeded_count", map[string]string{"type": "account"})
+
+	m := newTxObjectMap()
+	assert.Nil(t, m.Add(txObj1, 1))
+	assert.NotNil(t, m.Add(txObj2, 1))
+	assert.Nil(t, m.Add(txObj3, 1))
+
+	assert.Equal(t, size+2, readMetric(t, "txpool_size_gauge", nil))
+	assert.Equal(t, accounts+2, readMetric(t, "txpool_account_gauge", nil))
+	assert.Equal(t, exceeded+1, readMetric(t, "txpool_quota_exceeded_count", map[string]string{"type": "account"}))
+
+	assert.True(t, m.RemoveByHash(txObj1.Hash()))
+	assert.Equal(t, size+1, readMetric(t, "txpool_size_gauge", nil))
+	assert.Equal(t, accounts+1, readMetric(t, "txpool_account_gauge", nil))
+}
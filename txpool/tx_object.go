@@ -27,6 +27,7 @@ type txObject struct {
 	executable      bool
 	overallGasPrice *big.Int // don't touch this value, it's only be used in pool's housekeeping
 	localSubmitted  bool     // tx is submitted locally on this node, or synced remotely from p2p.
+	depPending      bool     // the tx depends on a tx which is not on the chain yet, updated by Executable.
 }
 
 func resolveTx(tx *tx.Transaction, localSubmitted bool) (*txObject, error) {
@@ -72,10 +73,12 @@ func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock
 		txMeta, err := chain.GetTransactionMeta(*dep)
 		if err != nil {
 			if chain.IsNotFound(err) {
+				o.depPending = true
 				return false, nil
 			}
 			return false, err
 		}
+		o.depPending = false
 		if txMeta.Reverted {
 			return false, errors.New("dep reverted")
 		}
@@ -46,22 +46,41 @@ func (m *txObjectMap) Add(txObj *txObject, limitPerAccount int) error {
 	}
 
 	if m.quota[txObj.Origin()] >= limitPerAccount {
+		metricTxPoolQuotaExceeded().AddWithLabel(1, map[string]string{"type": "account"})
 		return errors.New("account quota exceeded")
 	}
 
 	if d := txObj.Delegator(); d != nil {
 		if m.quota[*d] >= limitPerAccount {
+			metricTxPoolQuotaExceeded().AddWithLabel(1, map[string]string{"type": "delegator"})
 			return errors.New("delegator quota exceeded")
 		}
-		m.quota[*d]++
+		m.incQuota(*d)
 	}
 
-	m.quota[txObj.Origin()]++
+	m.incQuota(txObj.Origin())
 	m.mapByHash[hash] = txObj
 	m.mapByID[txObj.ID()] = txObj
+	metricTxPoolSize().Add(1)
 	return nil
 }
 
+func (m *txObjectMap) incQuota(addr thor.Address) {
+	if m.quota[addr] == 0 {
+		metricTxPoolAccounts().Add(1)
+	}
+	m.quota[addr]++
+}
+
+func (m *txObjectMap) decQuota(addr thor.Address) {
+	if m.quota[addr] > 1 {
+		m.quota[addr]--
+	} else {
+		delete(m.quota, addr)
+		metricTxPoolAccounts().Add(-1)
+	}
+}
+
 func (m *txObjectMap) GetByID(id thor.Bytes32) *txObject {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -73,22 +92,14 @@ func (m *txObjectMap) RemoveByHash(txHash thor.Bytes32) bool {
 	defer m.lock.Unlock()
 
 	if txObj, ok := m.mapByHash[txHash]; ok {
-		if m.quota[txObj.Origin()] > 1 {
-			m.quota[txObj.Origin()]--
-		} else {
-			delete(m.quota, txObj.Origin())
-		}
-
+		m.decQuota(txObj.Origin())
 		if d := txObj.Delegator(); d != nil {
-			if m.quota[*d] > 1 {
-				m.quota[*d]--
-			} else {
-				delete(m.quota, *d)
-			}
+			m.decQuota(*d)
 		}
 
 		delete(m.mapByHash, txHash)
 		delete(m.mapByID, txObj.ID())
+		metricTxPoolSize().Add(-1)
 		return true
 	}
 	return false
@@ -124,12 +135,13 @@ func (m *txObjectMap) Fill(txObjs []*txObject) {
 			continue
 		}
 		// skip account limit check
-		m.quota[txObj.Origin()]++
+		m.incQuota(txObj.Origin())
 		if d := txObj.Delegator(); d != nil {
-			m.quota[*d]++
+			m.incQuota(*d)
 		}
 		m.mapByHash[txObj.Hash()] = txObj
 		m.mapByID[txObj.ID()] = txObj
+		metricTxPoolSize().Add(1)
 	}
 }
 
@@ -9,6 +9,7 @@ import (
 	"context"
 	"math/rand"
 	"os"
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -56,6 +57,7 @@ type TxPool struct {
 	blocklist blocklist
 
 	executables    atomic.Value
+	pendingDeps    atomic.Value
 	all            *txObjectMap
 	addedAfterWash uint32
 
@@ -224,6 +226,8 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonExecutable bool, localSubmi
 		return badTxError{"chain tag mismatch"}
 	case newTx.Size() > maxTxSize:
 		return txRejectedError{"size too large"}
+	case newTx.Type() != tx.TypeLegacy:
+		return txRejectedError{"tx type not supported"}
 	}
 
 	if err := newTx.TestFeatures(headSummary.Header.TxsFeatures()); err != nil {
@@ -325,6 +329,54 @@ func (p *TxPool) Executables() tx.Transactions {
 	return nil
 }
 
+// PendingDependencies returns txs blocked on unmet dependencies as of the last wash, keyed by the
+// ID of the dependency. They are promoted to executables once their dependencies land on the chain.
+func (p *TxPool) PendingDependencies() map[thor.Bytes32][]thor.Bytes32 {
+	if deps := p.pendingDeps.Load(); deps != nil {
+		return deps.(map[thor.Bytes32][]thor.Bytes32)
+	}
+	return nil
+}
+
+// PooledTx is the info of a tx in the pool.
+type PooledTx struct {
+	ID         thor.Bytes32
+	Origin     thor.Address
+	Nonce      uint64
+	TimeAdded  time.Time
+	Executable bool // in the executables as of the last wash, otherwise queued
+	Local      bool // submitted locally on this node
+}
+
+// Content returns info of all txs in the pool, sorted by the time added. If origin is not nil, only txs
+// of the origin are returned.
+func (p *TxPool) Content(origin *thor.Address) []*PooledTx {
+	executables := make(map[thor.Bytes32]bool)
+	for _, tx := range p.Executables() {
+		executables[tx.ID()] = true
+	}
+
+	txObjs := p.all.ToTxObjects()
+	content := make([]*PooledTx, 0, len(txObjs))
+	for _, txObj := range txObjs {
+		if origin != nil && txObj.Origin() != *origin {
+			continue
+		}
+		content = append(content, &PooledTx{
+			ID:         txObj.ID(),
+			Origin:     txObj.Origin(),
+			Nonce:      txObj.Nonce(),
+			TimeAdded:  time.Unix(0, txObj.timeAdded),
+			Executable: executables[txObj.ID()],
+			Local:      txObj.localSubmitted,
+		})
+	}
+	sort.Slice(content, func(i, j int) bool {
+		return content[i].TimeAdded.Before(content[j].TimeAdded)
+	})
+	return content
+}
+
 // Fill fills txs into pool.
 func (p *TxPool) Fill(txs tx.Transactions) {
 	txObjs := make([]*txObject, 0, len(txs))
@@ -350,7 +402,10 @@ func (p *TxPool) Dump() tx.Transactions {
 // this method should only be called in housekeeping go routine
 func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transactions, removed int, err error) {
 	all := p.all.ToTxObjects()
-	var toRemove []*txObject
+	var (
+		toRemove []*txObject
+		expired  int
+	)
 	defer func() {
 		if err != nil {
 			// in case of error, simply cut pool size to limit
@@ -366,6 +421,8 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 				p.all.RemoveByHash(txObj.Hash())
 			}
 			removed = len(toRemove)
+			metricTxPoolExpired().Add(int64(expired))
+			p.updatePendingDeps()
 		}
 	}()
 
@@ -392,9 +449,10 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 			continue
 		}
 
-		// out of lifetime
-		if !txObj.localSubmitted && now > txObj.timeAdded+int64(p.options.MaxLifetime) {
+		// out of lifetime, local txs blocked on an unmet dependency are not spared
+		if (!txObj.localSubmitted || txObj.depPending) && now > txObj.timeAdded+int64(p.options.MaxLifetime) {
 			toRemove = append(toRemove, txObj)
+			expired++
 			log.Debug("tx washed out", "id", txObj.ID(), "err", "out of lifetime")
 			continue
 		}
@@ -475,6 +533,30 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 	return executables, 0, nil
 }
 
+// updatePendingDeps collects txs blocked on unmet dependencies.
+// this method should only be called in housekeeping go routine
+func (p *TxPool) updatePendingDeps() {
+	var (
+		deps  = make(map[thor.Bytes32][]thor.Bytes32)
+		count int
+	)
+	for _, txObj := range p.all.ToTxObjects() {
+		if txObj.depPending {
+			dep := *txObj.DependsOn()
+			deps[dep] = append(deps[dep], txObj.ID())
+			count++
+		}
+	}
+
+	prev := p.PendingDependencies()
+	var prevCount int
+	for _, ids := range prev {
+		prevCount += len(ids)
+	}
+	p.pendingDeps.Store(deps)
+	metricTxPoolDepPending().Add(int64(count - prevCount))
+}
+
 func isChainSynced(nowTimestamp, blockTimestamp uint64) bool {
 	timeDiff := nowTimestamp - blockTimestamp
 	if blockTimestamp > nowTimestamp {
@@ -582,3 +582,93 @@ func TestWash(t *testing.T) {
 		t.Run(tt.name, tt.testFunc)
 	}
 }
+
+func TestPendingDependencies(t *testing.T) {
+	pool := newPool(LIMIT, LIMIT_PER_ACCOUNT)
+	defer pool.Close()
+
+	dep := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), devAccounts[0])
+	depID := dep.ID()
+	trx := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, &depID, tx.Features(0), devAccounts[1])
+
+	assert.Nil(t, pool.AddLocal(trx))
+	assert.Nil(t, pool.PendingDependencies())
+
+	executables, _, err := pool.wash(pool.repo.BestBlockSummary())
+	assert.Nil(t, err)
+	assert.Empty(t, executables)
+	assert.Equal(t, map[thor.Bytes32][]thor.Bytes32{depID: {trx.ID()}}, pool.PendingDependencies())
+
+	// the dependency lands in a block
+	b0 := pool.repo.GenesisBlock()
+	stage, err := pool.stater.NewState(b0.Header().StateRoot(), 0, 0, 0).Stage(1, 0)
+	assert.Nil(t, err)
+	root, err := stage.Commit()
+	assert.Nil(t, err)
+	b1 := new(block.Builder).
+		ParentID(b0.Header().ID()).
+		Timestamp(b0.Header().Timestamp() + thor.BlockInterval).
+		GasLimit(b0.Header().GasLimit()).
+		TotalScore(b0.Header().TotalScore() + 1).
+		StateRoot(root).
+		Transaction(dep).
+		Build()
+	assert.Nil(t, pool.repo.AddBlock(b1, tx.Receipts{&tx.Receipt{}}, 0))
+	assert.Nil(t, pool.repo.SetBestBlockID(b1.Header().ID()))
+
+	executables, _, err = pool.wash(pool.repo.BestBlockSummary())
+	assert.Nil(t, err)
+	assert.Equal(t, tx.Transactions{trx}, executables)
+	assert.Empty(t, pool.PendingDependencies())
+}
+
+func TestPendingDependencyExpired(t *testing.T) {
+	pool := newPool(LIMIT, LIMIT_PER_ACCOUNT)
+	defer pool.Close()
+
+	depID := thor.Bytes32{1}
+	trx := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, &depID, tx.Features(0), devAccounts[0])
+	assert.Nil(t, pool.AddLocal(trx))
+
+	_, _, err := pool.wash(pool.repo.BestBlockSummary())
+	assert.Nil(t, err)
+	assert.Len(t, pool.PendingDependencies()[depID], 1)
+
+	// local tx blocked on an unmet dependency is washed out once out of lifetime
+	pool.all.mapByID[trx.ID()].timeAdded -= int64(pool.options.MaxLifetime) * 2
+	_, removed, err := pool.wash(pool.repo.BestBlockSummary())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Nil(t, pool.Get(trx.ID()))
+	assert.Empty(t, pool.PendingDependencies())
+}
+
+func TestContent(t *testing.T) {
+	pool := newPool(LIMIT, LIMIT_PER_ACCOUNT)
+	defer pool.Close()
+
+	tx1 := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), devAccounts[0])
+	tx2 := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), devAccounts[1])
+	assert.Nil(t, pool.AddLocal(tx1))
+	assert.Nil(t, pool.Add(tx2))
+	pool.executables.Store(tx.Transactions{tx1})
+
+	content := pool.Content(nil)
+	assert.Equal(t, 2, len(content))
+	assert.Equal(t, tx1.ID(), content[0].ID)
+	assert.Equal(t, devAccounts[0].Address, content[0].Origin)
+	assert.Equal(t, tx1.Nonce(), content[0].Nonce)
+	assert.True(t, content[0].Executable)
+	assert.True(t, content[0].Local)
+	assert.Equal(t, tx2.ID(), content[1].ID)
+	assert.False(t, content[1].Executable)
+	assert.False(t, content[1].Local)
+	assert.False(t, content[1].TimeAdded.Before(content[0].TimeAdded))
+
+	content = pool.Content(&devAccounts[1].Address)
+	assert.Equal(t, 1, len(content))
+	assert.Equal(t, tx2.ID(), content[0].ID)
+
+	content = pool.Content(&thor.Address{})
+	assert.Empty(t, content)
+}